@@ -0,0 +1,641 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: ingestor/v1/ingestor.proto
+
+package ingestorv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type TriggerIngestRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	AppId     string                 `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	AppName   string                 `protobuf:"bytes,2,opt,name=app_name,json=appName,proto3" json:"app_name,omitempty"`
+	Countries []string               `protobuf:"bytes,3,rep,name=countries,proto3" json:"countries,omitempty"`
+	// Dates are YYYY-MM-DD.
+	DateFrom string `protobuf:"bytes,4,opt,name=date_from,json=dateFrom,proto3" json:"date_from,omitempty"`
+	DateTo   string `protobuf:"bytes,5,opt,name=date_to,json=dateTo,proto3" json:"date_to,omitempty"`
+	// Defaults to "appstore".
+	Store         string `protobuf:"bytes,6,opt,name=store,proto3" json:"store,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerIngestRequest) Reset() {
+	*x = TriggerIngestRequest{}
+	mi := &file_ingestor_v1_ingestor_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerIngestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerIngestRequest) ProtoMessage() {}
+
+func (x *TriggerIngestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ingestor_v1_ingestor_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerIngestRequest.ProtoReflect.Descriptor instead.
+func (*TriggerIngestRequest) Descriptor() ([]byte, []int) {
+	return file_ingestor_v1_ingestor_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TriggerIngestRequest) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *TriggerIngestRequest) GetAppName() string {
+	if x != nil {
+		return x.AppName
+	}
+	return ""
+}
+
+func (x *TriggerIngestRequest) GetCountries() []string {
+	if x != nil {
+		return x.Countries
+	}
+	return nil
+}
+
+func (x *TriggerIngestRequest) GetDateFrom() string {
+	if x != nil {
+		return x.DateFrom
+	}
+	return ""
+}
+
+func (x *TriggerIngestRequest) GetDateTo() string {
+	if x != nil {
+		return x.DateTo
+	}
+	return ""
+}
+
+func (x *TriggerIngestRequest) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+type TriggerIngestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SagaId        string                 `protobuf:"bytes,1,opt,name=saga_id,json=sagaId,proto3" json:"saga_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerIngestResponse) Reset() {
+	*x = TriggerIngestResponse{}
+	mi := &file_ingestor_v1_ingestor_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerIngestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerIngestResponse) ProtoMessage() {}
+
+func (x *TriggerIngestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ingestor_v1_ingestor_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerIngestResponse.ProtoReflect.Descriptor instead.
+func (*TriggerIngestResponse) Descriptor() ([]byte, []int) {
+	return file_ingestor_v1_ingestor_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TriggerIngestResponse) GetSagaId() string {
+	if x != nil {
+		return x.SagaId
+	}
+	return ""
+}
+
+type GetRunStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SagaId        string                 `protobuf:"bytes,1,opt,name=saga_id,json=sagaId,proto3" json:"saga_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRunStatusRequest) Reset() {
+	*x = GetRunStatusRequest{}
+	mi := &file_ingestor_v1_ingestor_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRunStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRunStatusRequest) ProtoMessage() {}
+
+func (x *GetRunStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ingestor_v1_ingestor_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRunStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetRunStatusRequest) Descriptor() ([]byte, []int) {
+	return file_ingestor_v1_ingestor_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetRunStatusRequest) GetSagaId() string {
+	if x != nil {
+		return x.SagaId
+	}
+	return ""
+}
+
+type GetRunStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Run           *Run                   `protobuf:"bytes,1,opt,name=run,proto3" json:"run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRunStatusResponse) Reset() {
+	*x = GetRunStatusResponse{}
+	mi := &file_ingestor_v1_ingestor_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRunStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRunStatusResponse) ProtoMessage() {}
+
+func (x *GetRunStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ingestor_v1_ingestor_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRunStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetRunStatusResponse) Descriptor() ([]byte, []int) {
+	return file_ingestor_v1_ingestor_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetRunStatusResponse) GetRun() *Run {
+	if x != nil {
+		return x.Run
+	}
+	return nil
+}
+
+type ListRunsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Lists runs for every app when empty.
+	AppId         string `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRunsRequest) Reset() {
+	*x = ListRunsRequest{}
+	mi := &file_ingestor_v1_ingestor_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRunsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRunsRequest) ProtoMessage() {}
+
+func (x *ListRunsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ingestor_v1_ingestor_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRunsRequest.ProtoReflect.Descriptor instead.
+func (*ListRunsRequest) Descriptor() ([]byte, []int) {
+	return file_ingestor_v1_ingestor_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListRunsRequest) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+type ListRunsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Runs          []*Run                 `protobuf:"bytes,1,rep,name=runs,proto3" json:"runs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRunsResponse) Reset() {
+	*x = ListRunsResponse{}
+	mi := &file_ingestor_v1_ingestor_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRunsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRunsResponse) ProtoMessage() {}
+
+func (x *ListRunsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ingestor_v1_ingestor_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRunsResponse.ProtoReflect.Descriptor instead.
+func (*ListRunsResponse) Descriptor() ([]byte, []int) {
+	return file_ingestor_v1_ingestor_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListRunsResponse) GetRuns() []*Run {
+	if x != nil {
+		return x.Runs
+	}
+	return nil
+}
+
+type CancelRunRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SagaId        string                 `protobuf:"bytes,1,opt,name=saga_id,json=sagaId,proto3" json:"saga_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelRunRequest) Reset() {
+	*x = CancelRunRequest{}
+	mi := &file_ingestor_v1_ingestor_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRunRequest) ProtoMessage() {}
+
+func (x *CancelRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ingestor_v1_ingestor_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRunRequest.ProtoReflect.Descriptor instead.
+func (*CancelRunRequest) Descriptor() ([]byte, []int) {
+	return file_ingestor_v1_ingestor_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CancelRunRequest) GetSagaId() string {
+	if x != nil {
+		return x.SagaId
+	}
+	return ""
+}
+
+type CancelRunResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelRunResponse) Reset() {
+	*x = CancelRunResponse{}
+	mi := &file_ingestor_v1_ingestor_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelRunResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRunResponse) ProtoMessage() {}
+
+func (x *CancelRunResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ingestor_v1_ingestor_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRunResponse.ProtoReflect.Descriptor instead.
+func (*CancelRunResponse) Descriptor() ([]byte, []int) {
+	return file_ingestor_v1_ingestor_proto_rawDescGZIP(), []int{7}
+}
+
+type Run struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	SagaId    string                 `protobuf:"bytes,1,opt,name=saga_id,json=sagaId,proto3" json:"saga_id,omitempty"`
+	AppId     string                 `protobuf:"bytes,2,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	Store     string                 `protobuf:"bytes,3,opt,name=store,proto3" json:"store,omitempty"`
+	Countries []string               `protobuf:"bytes,4,rep,name=countries,proto3" json:"countries,omitempty"`
+	// running, completed, failed, parked or cancelled.
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Error         string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	Country       string                 `protobuf:"bytes,7,opt,name=country,proto3" json:"country,omitempty"`
+	Fetched       int64                  `protobuf:"varint,8,opt,name=fetched,proto3" json:"fetched,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt    *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Run) Reset() {
+	*x = Run{}
+	mi := &file_ingestor_v1_ingestor_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Run) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Run) ProtoMessage() {}
+
+func (x *Run) ProtoReflect() protoreflect.Message {
+	mi := &file_ingestor_v1_ingestor_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Run.ProtoReflect.Descriptor instead.
+func (*Run) Descriptor() ([]byte, []int) {
+	return file_ingestor_v1_ingestor_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Run) GetSagaId() string {
+	if x != nil {
+		return x.SagaId
+	}
+	return ""
+}
+
+func (x *Run) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *Run) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+func (x *Run) GetCountries() []string {
+	if x != nil {
+		return x.Countries
+	}
+	return nil
+}
+
+func (x *Run) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Run) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Run) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Run) GetFetched() int64 {
+	if x != nil {
+		return x.Fetched
+	}
+	return 0
+}
+
+func (x *Run) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *Run) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+var File_ingestor_v1_ingestor_proto protoreflect.FileDescriptor
+
+const file_ingestor_v1_ingestor_proto_rawDesc = "" +
+	"\n" +
+	"\x1aingestor/v1/ingestor.proto\x12\vingestor.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb2\x01\n" +
+	"\x14TriggerIngestRequest\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\tR\x05appId\x12\x19\n" +
+	"\bapp_name\x18\x02 \x01(\tR\aappName\x12\x1c\n" +
+	"\tcountries\x18\x03 \x03(\tR\tcountries\x12\x1b\n" +
+	"\tdate_from\x18\x04 \x01(\tR\bdateFrom\x12\x17\n" +
+	"\adate_to\x18\x05 \x01(\tR\x06dateTo\x12\x14\n" +
+	"\x05store\x18\x06 \x01(\tR\x05store\"0\n" +
+	"\x15TriggerIngestResponse\x12\x17\n" +
+	"\asaga_id\x18\x01 \x01(\tR\x06sagaId\".\n" +
+	"\x13GetRunStatusRequest\x12\x17\n" +
+	"\asaga_id\x18\x01 \x01(\tR\x06sagaId\":\n" +
+	"\x14GetRunStatusResponse\x12\"\n" +
+	"\x03run\x18\x01 \x01(\v2\x10.ingestor.v1.RunR\x03run\"(\n" +
+	"\x0fListRunsRequest\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\tR\x05appId\"8\n" +
+	"\x10ListRunsResponse\x12$\n" +
+	"\x04runs\x18\x01 \x03(\v2\x10.ingestor.v1.RunR\x04runs\"+\n" +
+	"\x10CancelRunRequest\x12\x17\n" +
+	"\asaga_id\x18\x01 \x01(\tR\x06sagaId\"\x13\n" +
+	"\x11CancelRunResponse\"\xc3\x02\n" +
+	"\x03Run\x12\x17\n" +
+	"\asaga_id\x18\x01 \x01(\tR\x06sagaId\x12\x15\n" +
+	"\x06app_id\x18\x02 \x01(\tR\x05appId\x12\x14\n" +
+	"\x05store\x18\x03 \x01(\tR\x05store\x12\x1c\n" +
+	"\tcountries\x18\x04 \x03(\tR\tcountries\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x12\x18\n" +
+	"\acountry\x18\a \x01(\tR\acountry\x12\x18\n" +
+	"\afetched\x18\b \x01(\x03R\afetched\x129\n" +
+	"\n" +
+	"started_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12;\n" +
+	"\vfinished_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt2\xd3\x02\n" +
+	"\x0fIngestorService\x12V\n" +
+	"\rTriggerIngest\x12!.ingestor.v1.TriggerIngestRequest\x1a\".ingestor.v1.TriggerIngestResponse\x12S\n" +
+	"\fGetRunStatus\x12 .ingestor.v1.GetRunStatusRequest\x1a!.ingestor.v1.GetRunStatusResponse\x12G\n" +
+	"\bListRuns\x12\x1c.ingestor.v1.ListRunsRequest\x1a\x1d.ingestor.v1.ListRunsResponse\x12J\n" +
+	"\tCancelRun\x12\x1d.ingestor.v1.CancelRunRequest\x1a\x1e.ingestor.v1.CancelRunResponseB@Z>github.com/quiby-ai/review-ingestor/api/ingestor/v1;ingestorv1b\x06proto3"
+
+var (
+	file_ingestor_v1_ingestor_proto_rawDescOnce sync.Once
+	file_ingestor_v1_ingestor_proto_rawDescData []byte
+)
+
+func file_ingestor_v1_ingestor_proto_rawDescGZIP() []byte {
+	file_ingestor_v1_ingestor_proto_rawDescOnce.Do(func() {
+		file_ingestor_v1_ingestor_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_ingestor_v1_ingestor_proto_rawDesc), len(file_ingestor_v1_ingestor_proto_rawDesc)))
+	})
+	return file_ingestor_v1_ingestor_proto_rawDescData
+}
+
+var file_ingestor_v1_ingestor_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_ingestor_v1_ingestor_proto_goTypes = []any{
+	(*TriggerIngestRequest)(nil),  // 0: ingestor.v1.TriggerIngestRequest
+	(*TriggerIngestResponse)(nil), // 1: ingestor.v1.TriggerIngestResponse
+	(*GetRunStatusRequest)(nil),   // 2: ingestor.v1.GetRunStatusRequest
+	(*GetRunStatusResponse)(nil),  // 3: ingestor.v1.GetRunStatusResponse
+	(*ListRunsRequest)(nil),       // 4: ingestor.v1.ListRunsRequest
+	(*ListRunsResponse)(nil),      // 5: ingestor.v1.ListRunsResponse
+	(*CancelRunRequest)(nil),      // 6: ingestor.v1.CancelRunRequest
+	(*CancelRunResponse)(nil),     // 7: ingestor.v1.CancelRunResponse
+	(*Run)(nil),                   // 8: ingestor.v1.Run
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+}
+var file_ingestor_v1_ingestor_proto_depIdxs = []int32{
+	8, // 0: ingestor.v1.GetRunStatusResponse.run:type_name -> ingestor.v1.Run
+	8, // 1: ingestor.v1.ListRunsResponse.runs:type_name -> ingestor.v1.Run
+	9, // 2: ingestor.v1.Run.started_at:type_name -> google.protobuf.Timestamp
+	9, // 3: ingestor.v1.Run.finished_at:type_name -> google.protobuf.Timestamp
+	0, // 4: ingestor.v1.IngestorService.TriggerIngest:input_type -> ingestor.v1.TriggerIngestRequest
+	2, // 5: ingestor.v1.IngestorService.GetRunStatus:input_type -> ingestor.v1.GetRunStatusRequest
+	4, // 6: ingestor.v1.IngestorService.ListRuns:input_type -> ingestor.v1.ListRunsRequest
+	6, // 7: ingestor.v1.IngestorService.CancelRun:input_type -> ingestor.v1.CancelRunRequest
+	1, // 8: ingestor.v1.IngestorService.TriggerIngest:output_type -> ingestor.v1.TriggerIngestResponse
+	3, // 9: ingestor.v1.IngestorService.GetRunStatus:output_type -> ingestor.v1.GetRunStatusResponse
+	5, // 10: ingestor.v1.IngestorService.ListRuns:output_type -> ingestor.v1.ListRunsResponse
+	7, // 11: ingestor.v1.IngestorService.CancelRun:output_type -> ingestor.v1.CancelRunResponse
+	8, // [8:12] is the sub-list for method output_type
+	4, // [4:8] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_ingestor_v1_ingestor_proto_init() }
+func file_ingestor_v1_ingestor_proto_init() {
+	if File_ingestor_v1_ingestor_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_ingestor_v1_ingestor_proto_rawDesc), len(file_ingestor_v1_ingestor_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_ingestor_v1_ingestor_proto_goTypes,
+		DependencyIndexes: file_ingestor_v1_ingestor_proto_depIdxs,
+		MessageInfos:      file_ingestor_v1_ingestor_proto_msgTypes,
+	}.Build()
+	File_ingestor_v1_ingestor_proto = out.File
+	file_ingestor_v1_ingestor_proto_goTypes = nil
+	file_ingestor_v1_ingestor_proto_depIdxs = nil
+}
@@ -0,0 +1,243 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: ingestor/v1/ingestor.proto
+
+package ingestorv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	IngestorService_TriggerIngest_FullMethodName = "/ingestor.v1.IngestorService/TriggerIngest"
+	IngestorService_GetRunStatus_FullMethodName  = "/ingestor.v1.IngestorService/GetRunStatus"
+	IngestorService_ListRuns_FullMethodName      = "/ingestor.v1.IngestorService/ListRuns"
+	IngestorService_CancelRun_FullMethodName     = "/ingestor.v1.IngestorService/CancelRun"
+)
+
+// IngestorServiceClient is the client API for IngestorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// IngestorService controls review ingestion for internal tooling, as an
+// alternative to publishing extract requests on Kafka. Runs are tracked per
+// instance.
+type IngestorServiceClient interface {
+	TriggerIngest(ctx context.Context, in *TriggerIngestRequest, opts ...grpc.CallOption) (*TriggerIngestResponse, error)
+	GetRunStatus(ctx context.Context, in *GetRunStatusRequest, opts ...grpc.CallOption) (*GetRunStatusResponse, error)
+	ListRuns(ctx context.Context, in *ListRunsRequest, opts ...grpc.CallOption) (*ListRunsResponse, error)
+	CancelRun(ctx context.Context, in *CancelRunRequest, opts ...grpc.CallOption) (*CancelRunResponse, error)
+}
+
+type ingestorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIngestorServiceClient(cc grpc.ClientConnInterface) IngestorServiceClient {
+	return &ingestorServiceClient{cc}
+}
+
+func (c *ingestorServiceClient) TriggerIngest(ctx context.Context, in *TriggerIngestRequest, opts ...grpc.CallOption) (*TriggerIngestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TriggerIngestResponse)
+	err := c.cc.Invoke(ctx, IngestorService_TriggerIngest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ingestorServiceClient) GetRunStatus(ctx context.Context, in *GetRunStatusRequest, opts ...grpc.CallOption) (*GetRunStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRunStatusResponse)
+	err := c.cc.Invoke(ctx, IngestorService_GetRunStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ingestorServiceClient) ListRuns(ctx context.Context, in *ListRunsRequest, opts ...grpc.CallOption) (*ListRunsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRunsResponse)
+	err := c.cc.Invoke(ctx, IngestorService_ListRuns_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ingestorServiceClient) CancelRun(ctx context.Context, in *CancelRunRequest, opts ...grpc.CallOption) (*CancelRunResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelRunResponse)
+	err := c.cc.Invoke(ctx, IngestorService_CancelRun_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// IngestorServiceServer is the server API for IngestorService service.
+// All implementations must embed UnimplementedIngestorServiceServer
+// for forward compatibility.
+//
+// IngestorService controls review ingestion for internal tooling, as an
+// alternative to publishing extract requests on Kafka. Runs are tracked per
+// instance.
+type IngestorServiceServer interface {
+	TriggerIngest(context.Context, *TriggerIngestRequest) (*TriggerIngestResponse, error)
+	GetRunStatus(context.Context, *GetRunStatusRequest) (*GetRunStatusResponse, error)
+	ListRuns(context.Context, *ListRunsRequest) (*ListRunsResponse, error)
+	CancelRun(context.Context, *CancelRunRequest) (*CancelRunResponse, error)
+	mustEmbedUnimplementedIngestorServiceServer()
+}
+
+// UnimplementedIngestorServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedIngestorServiceServer struct{}
+
+func (UnimplementedIngestorServiceServer) TriggerIngest(context.Context, *TriggerIngestRequest) (*TriggerIngestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TriggerIngest not implemented")
+}
+func (UnimplementedIngestorServiceServer) GetRunStatus(context.Context, *GetRunStatusRequest) (*GetRunStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRunStatus not implemented")
+}
+func (UnimplementedIngestorServiceServer) ListRuns(context.Context, *ListRunsRequest) (*ListRunsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRuns not implemented")
+}
+func (UnimplementedIngestorServiceServer) CancelRun(context.Context, *CancelRunRequest) (*CancelRunResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelRun not implemented")
+}
+func (UnimplementedIngestorServiceServer) mustEmbedUnimplementedIngestorServiceServer() {}
+func (UnimplementedIngestorServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeIngestorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IngestorServiceServer will
+// result in compilation errors.
+type UnsafeIngestorServiceServer interface {
+	mustEmbedUnimplementedIngestorServiceServer()
+}
+
+func RegisterIngestorServiceServer(s grpc.ServiceRegistrar, srv IngestorServiceServer) {
+	// If the following call pancis, it indicates UnimplementedIngestorServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&IngestorService_ServiceDesc, srv)
+}
+
+func _IngestorService_TriggerIngest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerIngestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IngestorServiceServer).TriggerIngest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IngestorService_TriggerIngest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IngestorServiceServer).TriggerIngest(ctx, req.(*TriggerIngestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IngestorService_GetRunStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRunStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IngestorServiceServer).GetRunStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IngestorService_GetRunStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IngestorServiceServer).GetRunStatus(ctx, req.(*GetRunStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IngestorService_ListRuns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRunsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IngestorServiceServer).ListRuns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IngestorService_ListRuns_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IngestorServiceServer).ListRuns(ctx, req.(*ListRunsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IngestorService_CancelRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelRunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IngestorServiceServer).CancelRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IngestorService_CancelRun_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IngestorServiceServer).CancelRun(ctx, req.(*CancelRunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// IngestorService_ServiceDesc is the grpc.ServiceDesc for IngestorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var IngestorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ingestor.v1.IngestorService",
+	HandlerType: (*IngestorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "TriggerIngest",
+			Handler:    _IngestorService_TriggerIngest_Handler,
+		},
+		{
+			MethodName: "GetRunStatus",
+			Handler:    _IngestorService_GetRunStatus_Handler,
+		},
+		{
+			MethodName: "ListRuns",
+			Handler:    _IngestorService_ListRuns_Handler,
+		},
+		{
+			MethodName: "CancelRun",
+			Handler:    _IngestorService_CancelRun_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "ingestor/v1/ingestor.proto",
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/bench"
+	"github.com/quiby-ai/review-ingestor/internal/consumer"
+	"github.com/quiby-ai/review-ingestor/internal/export"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/service"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+const quarantineBatchSize = 100
+
+// runCommand executes a one-off operational command instead of starting the
+// consumer.
+func runCommand(ctx context.Context, deps *dependencies, name string, args []string) error {
+	logger.Info(ctx, "Running command", "command", name)
+
+	switch name {
+	case "reprocess-quarantine":
+		saved, failed, err := deps.svc.ReprocessQuarantine(ctx, quarantineBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to reprocess quarantine: %w", err)
+		}
+		fmt.Printf("reprocessed quarantine: %d saved, %d still quarantined\n", saved, failed)
+		return nil
+	case "export":
+		return runExport(ctx, deps, args)
+	case "replay":
+		return runReplay(ctx, deps, args)
+	case "rewind-offsets":
+		return runRewindOffsets(ctx, deps, args)
+	case "bench":
+		return runBench(ctx, deps, args)
+	default:
+		return fmt.Errorf("unknown command %q", name)
+	}
+}
+
+// runExport dumps stored reviews of an app to a local file or s3:// URL.
+func runExport(ctx context.Context, deps *dependencies, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	appID := fs.String("app-id", "", "app to export (required)")
+	store := fs.String("store", "", "only export this store")
+	from := fs.String("from", "", "first review date, YYYY-MM-DD")
+	to := fs.String("to", "", "end review date (exclusive), YYYY-MM-DD")
+	format := fs.String("format", export.FormatJSONL, "csv or jsonl")
+	out := fs.String("out", "", "local path or s3://bucket/key (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *appID == "" || *out == "" {
+		return fmt.Errorf("export requires -app-id and -out")
+	}
+
+	filter := storage.ExportFilter{AppID: *appID, Store: *store}
+	var err error
+	if filter.From, err = parseDateFlag(*from); err != nil {
+		return fmt.Errorf("invalid -from: %w", err)
+	}
+	if filter.To, err = parseDateFlag(*to); err != nil {
+		return fmt.Errorf("invalid -to: %w", err)
+	}
+
+	count, err := deps.exporter.Export(ctx, filter, *format, *out)
+	if err != nil {
+		return fmt.Errorf("failed to export reviews: %w", err)
+	}
+	fmt.Printf("exported %d reviews to %s\n", count, *out)
+	return nil
+}
+
+// runReplay re-parses an archive of raw App Store pages into storage without
+// contacting Apple.
+func runReplay(ctx context.Context, deps *dependencies, args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	appID := fs.String("app-id", "", "app the archived pages belong to (required)")
+	src := fs.String("from", "", "archive directory or s3://bucket/prefix (required)")
+	overwrite := fs.Bool("overwrite", false, "replace reviews that are already stored")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *appID == "" || *src == "" {
+		return fmt.Errorf("replay requires -app-id and -from")
+	}
+
+	ctx = logger.WithAppID(ctx, *appID)
+	totals, err := deps.replayer.Replay(ctx, *appID, *src, *overwrite)
+	if err != nil {
+		return fmt.Errorf("failed to replay archive: %w", err)
+	}
+	for _, stats := range totals {
+		fmt.Printf("%s: %d reviews, %d new, %d duplicates, %d failed\n", stats.Country, stats.Fetched, stats.New, stats.Duplicates, stats.Failed)
+	}
+	return nil
+}
+
+// runRewindOffsets moves a consumer group back to a point in time so the
+// requests received since then are processed again. Consumers of the group
+// must be stopped first.
+func runRewindOffsets(ctx context.Context, deps *dependencies, args []string) error {
+	fs := flag.NewFlagSet("rewind-offsets", flag.ContinueOnError)
+	topic := fs.String("topic", deps.kafka.RequestTopic, "topic to rewind")
+	group := fs.String("group", deps.kafka.GroupID, "consumer group to rewind")
+	to := fs.String("to", "", "RFC 3339 timestamp to resume from (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *to == "" {
+		return fmt.Errorf("rewind-offsets requires -to")
+	}
+	at, err := time.Parse(time.RFC3339, *to)
+	if err != nil {
+		return fmt.Errorf("invalid -to: %w", err)
+	}
+
+	offsets, err := consumer.RewindGroup(ctx, deps.kafka.Brokers, *group, *topic, at)
+	if err != nil {
+		return fmt.Errorf("failed to rewind offsets: %w", err)
+	}
+	partitions := make([]int, 0, len(offsets))
+	for partition := range offsets {
+		partitions = append(partitions, partition)
+	}
+	sort.Ints(partitions)
+	for _, partition := range partitions {
+		fmt.Printf("%s[%d] -> %d\n", *topic, partition, offsets[partition])
+	}
+	return nil
+}
+
+// runBench ingests synthetic apps from the in-process mock store and prints
+// throughput, memory and database write rates.
+func runBench(ctx context.Context, deps *dependencies, args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	apps := fs.Int("apps", 10, "synthetic apps to ingest")
+	countries := fs.String("countries", "us,gb,de", "comma-separated storefronts fetched per app")
+	reviews := fs.Int("reviews", service.Limit, "reviews per storefront (at most the per-storefront cap)")
+	concurrency := fs.Int("concurrency", 4, "sagas run at a time")
+	appIDBase := fs.Int64("app-id-base", time.Now().Unix(), "first synthetic app ID; reuse one to measure duplicates")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	report, err := deps.bencher.Run(ctx, bench.Options{
+		Apps:        *apps,
+		Countries:   strings.Split(*countries, ","),
+		Reviews:     min(*reviews, service.Limit),
+		Concurrency: *concurrency,
+		AppIDBase:   *appIDBase,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to run benchmark: %w", err)
+	}
+
+	fmt.Printf("sagas:          %d (%d failed) in %s\n", report.Sagas, report.FailedSagas, report.Duration.Round(time.Millisecond))
+	fmt.Printf("reviews:        %d fetched, %d new, %d duplicates, %d failed\n", report.Fetched, report.New, report.Duplicates, report.Failed)
+	fmt.Printf("throughput:     %.1f reviews/s, %d store requests\n", report.ReviewsPerSecond(), report.StoreRequests)
+	fmt.Printf("database:       %d rows written, %.1f rows/s\n", report.RowsWritten, report.RowsPerSecond())
+	fmt.Printf("memory:         %.1f MiB allocated, %.1f MiB peak heap, %d GC cycles\n",
+		float64(report.AllocatedBytes)/(1<<20), float64(report.PeakHeapBytes)/(1<<20), report.GCCycles)
+	return nil
+}
+
+func parseDateFlag(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
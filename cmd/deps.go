@@ -0,0 +1,417 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/quiby-ai/common/pkg/httpx"
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/admin"
+	"github.com/quiby-ai/review-ingestor/internal/appstore"
+	"github.com/quiby-ai/review-ingestor/internal/bench"
+	"github.com/quiby-ai/review-ingestor/internal/cassette"
+	"github.com/quiby-ai/review-ingestor/internal/consumer"
+	"github.com/quiby-ai/review-ingestor/internal/debugcapture"
+	"github.com/quiby-ai/review-ingestor/internal/embeddings"
+	"github.com/quiby-ai/review-ingestor/internal/export"
+	"github.com/quiby-ai/review-ingestor/internal/faults"
+	"github.com/quiby-ai/review-ingestor/internal/fetcher"
+	"github.com/quiby-ai/review-ingestor/internal/grpcapi"
+	"github.com/quiby-ai/review-ingestor/internal/httpclient"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/producer"
+	"github.com/quiby-ai/review-ingestor/internal/ratelimit"
+	"github.com/quiby-ai/review-ingestor/internal/replay"
+	"github.com/quiby-ai/review-ingestor/internal/service"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+	"github.com/quiby-ai/review-ingestor/internal/translate"
+	"github.com/quiby-ai/review-ingestor/internal/useragent"
+	"github.com/quiby-ai/review-ingestor/internal/watchlist"
+	"github.com/quiby-ai/review-ingestor/internal/webhook"
+	"github.com/redis/go-redis/v9"
+)
+
+type dependencies struct {
+	db       *sql.DB
+	svc      *service.IngestService
+	consumer *consumer.KafkaConsumer
+	producer *producer.Producer
+	limiter  *ratelimit.RedisLimiter
+	admin    *admin.Server
+	grpc     *grpcapi.Server
+	exporter *export.Exporter
+	replayer *replay.Replayer
+	bencher  *bench.Runner
+	kafka    config.KafkaConfig
+	// userAgents refreshes the user agent pool when a source is configured.
+	userAgents *useragent.Refresher
+	embedder   *embeddings.Embedder
+	// shadowDB is the secondary store review saves are mirrored to.
+	shadowDB *sql.DB
+
+	// Shared by the init steps while wiring.
+	userAgentPool  *useragent.Pool
+	appStoreHTTP   httpx.Client
+	reviewHTTP     httpx.Client
+	storeHTTP      httpx.Client
+	injector       *faults.Injector
+	tokenExtractor *appstore.TokenExtractor
+	reviewFetcher  *appstore.ReviewFetcher
+	tuner          *appstore.Tuner
+	repo           *storage.ReviewRepository
+	control        *storage.ControlRepository
+	quarantine     *storage.QuarantineRepository
+	// serviceOpts collects the options the steps before initService add for
+	// optional stores and integrations.
+	serviceOpts []service.Option
+}
+
+func (d *dependencies) cleanup(ctx context.Context) {
+	if d.db != nil {
+		logger.Debug(ctx, "Closing database connection")
+		if err := d.db.Close(); err != nil {
+			logger.Error(ctx, "Error closing database", err)
+		}
+	}
+	if d.shadowDB != nil {
+		logger.Debug(ctx, "Closing shadow database connection")
+		if err := d.shadowDB.Close(); err != nil {
+			logger.Error(ctx, "Error closing shadow database", err)
+		}
+	}
+	if d.consumer != nil {
+		logger.Debug(ctx, "Closing Kafka consumer")
+		if err := d.consumer.Close(); err != nil {
+			logger.Error(ctx, "Error closing Kafka consumer", err)
+		}
+	}
+	if d.producer != nil {
+		logger.Debug(ctx, "Closing Kafka producer")
+		if err := d.producer.Close(); err != nil {
+			logger.Error(ctx, "Error closing Kafka producer", err)
+		}
+	}
+	if d.limiter != nil {
+		logger.Debug(ctx, "Closing Redis rate limiter")
+		if err := d.limiter.Close(); err != nil {
+			logger.Error(ctx, "Error closing Redis rate limiter", err)
+		}
+	}
+}
+
+// applyTunables hands reloaded tunables to the subsystems using them. See
+// config.Config.Tunables for the settings covered.
+func (d *dependencies) applyTunables(cfg *config.Config) {
+	logger.SetLevel(cfg.Logging.Level)
+	d.svc.SetPageDelay(cfg.AppStore.PageDelay, cfg.AppStore.PageDelayJitter)
+	if d.limiter != nil {
+		d.limiter.SetRequestsPerMinute(cfg.RateLimit.RequestsPerMinute)
+	}
+	if d.tuner != nil {
+		d.tuner.SetConfig(cfg.AutoTune)
+	}
+	if d.admin != nil {
+		d.admin.SetConfig(cfg)
+	}
+}
+
+// initStep wires one subsystem into the dependencies. Steps run in the order
+// of initSteps and may use what earlier steps set up; optional subsystems
+// check their config and return early when disabled.
+type initStep func(d *dependencies, cfg *config.Config) error
+
+var initSteps = []initStep{
+	initDatabase,
+	initHTTP,
+	initAppStore,
+	initDebugCapture,
+	initResponseCache,
+	initRateLimit,
+	initStorage,
+	initResumeSpool,
+	initPublishSpool,
+	initShadow,
+	initWatchlist,
+	initTranslation,
+	initEmbeddings,
+	initWebhook,
+	initService,
+	initConsumer,
+	initAdmin,
+	initGRPC,
+	initUserAgentRefresh,
+	initCommands,
+}
+
+// initializeDependencies runs the init steps, releasing whatever was opened
+// when one fails.
+func initializeDependencies(cfg *config.Config) (*dependencies, error) {
+	d := &dependencies{kafka: cfg.Kafka}
+	for _, step := range initSteps {
+		if err := step(d, cfg); err != nil {
+			d.cleanup(context.Background())
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+func initDatabase(d *dependencies, cfg *config.Config) error {
+	db, err := storage.InitPostgres(cfg.Postgres)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	d.db = db
+	return nil
+}
+
+func initHTTP(d *dependencies, cfg *config.Config) error {
+	d.userAgentPool = useragent.NewPool(cfg.HTTP.UserAgents)
+	httpClient := httpclient.New(cfg.HTTP)
+	httpClient.SetUserAgents(d.userAgentPool)
+
+	// App Store traffic can be recorded to or replayed from a cassette.
+	d.appStoreHTTP = httpClient
+	if cfg.Debug.CassetteMode != cassette.ModeOff {
+		tape, err := cassette.New(httpClient, cfg.Debug.CassetteMode, cfg.Debug.CassettePath)
+		if err != nil {
+			return fmt.Errorf("failed to open cassette: %w", err)
+		}
+		d.appStoreHTTP = tape
+	}
+
+	// Review fetchers go through the fault injector so injected faults only
+	// hit review pages, not token extraction or lookups.
+	d.reviewHTTP, d.storeHTTP = d.appStoreHTTP, httpClient
+	if cfg.Faults.Enabled {
+		d.injector = faults.NewInjector(cfg.Faults)
+		d.reviewHTTP, d.storeHTTP = d.injector.WrapHTTP(d.appStoreHTTP), d.injector.WrapHTTP(httpClient)
+		logger.Warn(context.Background(), "Fault injection enabled, do not run this in production",
+			"http_429_rate", cfg.Faults.HTTP429Rate, "slow_rate", cfg.Faults.SlowRate, "db_error_rate", cfg.Faults.DBErrorRate)
+	}
+	return nil
+}
+
+func initAppStore(d *dependencies, cfg *config.Config) error {
+	d.tokenExtractor = appstore.NewTokenExtractor(d.appStoreHTTP)
+	if cfg.AppStore.LandingHost != "" {
+		if err := d.tokenExtractor.SetLandingHost(cfg.AppStore.LandingHost); err != nil {
+			return err
+		}
+	}
+	d.reviewFetcher = appstore.NewReviewFetcher(d.reviewHTTP, "", *cfg)
+	d.reviewFetcher.SetUserAgents(d.userAgentPool.Agents)
+	if cfg.AutoTune.Enabled {
+		d.tuner = appstore.NewTuner(cfg.AutoTune, cfg.AppStore.PageDelay)
+		d.reviewFetcher.SetTuner(d.tuner)
+	}
+	return nil
+}
+
+func initDebugCapture(d *dependencies, cfg *config.Config) error {
+	if !cfg.Debug.CaptureFailures {
+		return nil
+	}
+	capturer, err := debugcapture.NewCapturer(cfg.Debug, cfg.S3)
+	if err != nil {
+		return fmt.Errorf("failed to initialize debug capture: %w", err)
+	}
+	d.tokenExtractor.SetCapturer(capturer)
+	d.reviewFetcher.SetCapturer(capturer)
+	return nil
+}
+
+func initResponseCache(d *dependencies, cfg *config.Config) error {
+	if !cfg.Cache.Enabled {
+		return nil
+	}
+	cache := storage.NewResponseCache(d.db, cfg.Cache.TTL)
+	if _, err := cache.PurgeExpired(context.Background()); err != nil {
+		return fmt.Errorf("failed to purge response cache: %w", err)
+	}
+	d.reviewFetcher.SetCache(cache)
+	return nil
+}
+
+func initRateLimit(d *dependencies, cfg *config.Config) error {
+	if !cfg.RateLimit.Enabled {
+		return nil
+	}
+	client := redis.NewClient(&redis.Options{Addr: cfg.RateLimit.RedisAddr})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	d.limiter = ratelimit.NewRedisLimiter(client, cfg.RateLimit.KeyPrefix, cfg.RateLimit.RequestsPerMinute)
+	d.reviewFetcher.SetRateLimiter(d.limiter)
+	return nil
+}
+
+func initStorage(d *dependencies, cfg *config.Config) error {
+	d.repo = storage.NewReviewRepository(d.db, cfg.Postgres)
+	if d.injector != nil {
+		d.repo.SetFaultInjector(d.injector)
+	}
+	d.control = storage.NewControlRepository(d.db)
+	d.quarantine = storage.NewQuarantineRepository(d.db)
+	d.reviewFetcher.SetDriftRecorder(d.quarantine)
+	return nil
+}
+
+// initService runs after every step that adds service options.
+func initService(d *dependencies, cfg *config.Config) error {
+	d.producer = producer.NewProducer(cfg.Kafka)
+
+	fetchers := fetcher.NewRegistry(d.storeHTTP, *cfg)
+	fetchers.Add(fetcher.StoreAppStore, d.reviewFetcher)
+	lookup := appstore.NewLookupClient(d.appStoreHTTP, cfg.AppStore)
+	opts := []service.Option{
+		service.WithAppStoreConfig(cfg.AppStore),
+		service.WithIngestConfig(cfg.Ingest),
+		service.WithAnomalyConfig(cfg.Anomaly),
+		service.WithResponseSLAConfig(cfg.ResponseSLA),
+		service.WithAccountingConfig(cfg.Accounting),
+		service.WithFeatures(cfg.Features),
+		service.WithAppOverrides(cfg.Apps),
+		service.WithLimit(cfg.Ingest.MaxReviews),
+		service.WithConcurrency(cfg.Ingest.MaxConcurrentSagas),
+		service.WithAppLookup(lookup),
+		service.WithAppVersionStore(storage.NewAppVersionRepository(d.db)),
+		service.WithIdentifierStore(storage.NewIdentifierRepository(d.db)),
+		service.WithAggregateStore(storage.NewAggregateRepository(d.db)),
+		service.WithStorefrontHealth(storage.NewStorefrontHealthRepository(d.db)),
+		service.WithStorefrontDiscoverer(appstore.NewDiscoverer(lookup, storage.NewStorefrontRepository(d.db), cfg.AppStore)),
+	}
+	if cfg.Accounting.Enabled {
+		opts = append(opts, service.WithUsageStore(storage.NewUsageRepository(d.db)))
+	}
+	d.svc = service.NewIngestService(d.tokenExtractor, fetchers, d.repo, storage.NewIngestionLocker(d.db, cfg.Ingest.LockWait), d.control, d.quarantine, d.producer, append(opts, d.serviceOpts...)...)
+	return nil
+}
+
+func initResumeSpool(d *dependencies, cfg *config.Config) error {
+	if cfg.Ingest.ResumeDir == "" {
+		return nil
+	}
+	spool, err := storage.NewResumeSpool(cfg.Ingest.ResumeDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize resume spool: %w", err)
+	}
+	d.serviceOpts = append(d.serviceOpts, service.WithSuspendedSagaStore(spool))
+	return nil
+}
+
+func initPublishSpool(d *dependencies, cfg *config.Config) error {
+	if cfg.Ingest.PublishSpoolDir == "" {
+		return nil
+	}
+	spool, err := storage.NewPublishSpool(cfg.Ingest.PublishSpoolDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize publish spool: %w", err)
+	}
+	d.serviceOpts = append(d.serviceOpts, service.WithEventSpool(spool))
+	return nil
+}
+
+func initWatchlist(d *dependencies, cfg *config.Config) error {
+	if !cfg.Watchlist.Enabled() {
+		return nil
+	}
+	list, err := watchlist.New(cfg.Watchlist)
+	if err != nil {
+		return err
+	}
+	d.serviceOpts = append(d.serviceOpts, service.WithWatchlist(list))
+	return nil
+}
+
+// initShadow mirrors review saves to the shadow database, which is migrated
+// like the primary and written with the same retry settings.
+func initShadow(d *dependencies, cfg *config.Config) error {
+	if !cfg.Shadow.Enabled() {
+		return nil
+	}
+	shadowCfg := cfg.Postgres
+	shadowCfg.DSN = cfg.Shadow.DSN
+	db, err := storage.InitPostgres(shadowCfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize shadow database: %w", err)
+	}
+	d.shadowDB = db
+	d.serviceOpts = append(d.serviceOpts, service.WithShadowStore(storage.NewReviewRepository(db, shadowCfg), cfg.Shadow))
+	return nil
+}
+
+func initTranslation(d *dependencies, cfg *config.Config) error {
+	if cfg.Translate.Enabled {
+		d.serviceOpts = append(d.serviceOpts, service.WithTranslator(translate.NewClient(cfg.Translate)))
+	}
+	return nil
+}
+
+func initEmbeddings(d *dependencies, cfg *config.Config) error {
+	if !cfg.Embeddings.Enabled {
+		return nil
+	}
+	if err := d.repo.EnableEmbeddings(context.Background(), cfg.Embeddings.Dimensions); err != nil {
+		return err
+	}
+	d.embedder = embeddings.NewEmbedder(embeddings.NewClient(cfg.Embeddings), d.repo, cfg.Embeddings)
+	d.serviceOpts = append(d.serviceOpts, service.WithPostSaveHooks(d.embedder))
+	return nil
+}
+
+func initWebhook(d *dependencies, cfg *config.Config) error {
+	if cfg.Webhook.URL != "" {
+		d.serviceOpts = append(d.serviceOpts, service.WithNotifier(webhook.NewNotifier(cfg.Webhook)))
+	}
+	return nil
+}
+
+// initConsumer runs after initService, so the pipeline is checked with every
+// stage and post-save hook the service was built with.
+func initConsumer(d *dependencies, cfg *config.Config) error {
+	if err := d.svc.ValidatePipeline(); err != nil {
+		return err
+	}
+	kafkaConsumer, err := consumer.NewKafkaConsumer(cfg.Kafka, consumer.NewRouter(d.svc))
+	if err != nil {
+		return err
+	}
+	d.consumer = kafkaConsumer
+	return nil
+}
+
+func initAdmin(d *dependencies, cfg *config.Config) error {
+	if cfg.Admin.Enabled {
+		d.admin = admin.NewServer(cfg.Admin, d.control, d.repo, d.svc)
+		d.admin.SetBuildInfo(admin.BuildInfo{Version: version, Commit: buildCommit()})
+		d.admin.SetConfig(cfg)
+	}
+	return nil
+}
+
+func initGRPC(d *dependencies, cfg *config.Config) error {
+	if cfg.GRPC.Enabled {
+		d.grpc = grpcapi.NewServer(cfg.GRPC, d.svc)
+	}
+	return nil
+}
+
+func initUserAgentRefresh(d *dependencies, cfg *config.Config) error {
+	if cfg.HTTP.UserAgentsSource != "" {
+		d.userAgents = useragent.NewRefresher(d.userAgentPool, cfg.HTTP)
+	}
+	return nil
+}
+
+// initCommands sets up what the one-off commands need.
+func initCommands(d *dependencies, cfg *config.Config) error {
+	d.exporter = export.NewExporter(d.repo, cfg.S3)
+	d.replayer = replay.NewReplayer(d.svc, cfg.S3)
+	d.bencher = bench.NewRunner(d.svc, func(ctx context.Context) (int64, error) {
+		return storage.DatabaseWrites(ctx, d.db)
+	}, cfg.MockStore, cfg.AppStore)
+	return nil
+}
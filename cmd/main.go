@@ -3,22 +3,39 @@ package main
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"os"
 	"os/signal"
+	"runtime/debug"
 	"syscall"
 
-	"github.com/quiby-ai/common/pkg/httpx"
 	"github.com/quiby-ai/review-ingestor/config"
-	"github.com/quiby-ai/review-ingestor/internal/appstore"
-	"github.com/quiby-ai/review-ingestor/internal/consumer"
 	"github.com/quiby-ai/review-ingestor/internal/logger"
-	"github.com/quiby-ai/review-ingestor/internal/producer"
-	"github.com/quiby-ai/review-ingestor/internal/service"
-	"github.com/quiby-ai/review-ingestor/internal/storage"
+	"github.com/quiby-ai/review-ingestor/internal/mockstore"
 )
 
+var version = "1.0.0"
+
+// commit is the git SHA of the build, set with
+// -ldflags "-X main.commit=<sha>". Without it the VCS revision Go embeds in
+// builds from a checkout is used.
+var commit = ""
+
+// buildCommit returns commit, or the embedded VCS revision, or "unknown".
+func buildCommit() string {
+	if commit != "" {
+		return commit
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				return setting.Value
+			}
+		}
+	}
+	return "unknown"
+}
+
 func main() {
 	if err := run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -36,10 +53,17 @@ func run() error {
 	}
 
 	// Initialize logger
+	cfg.Logging.Version = version
 	logger.InitLogger(cfg.Logging)
 	ctx = logger.WithTraceID(ctx, "")
 
-	logger.Info(ctx, "Starting review ingestor service", "version", "1.0.0")
+	logger.Info(ctx, "Starting review ingestor service")
+
+	// The mock store stands in for Apple and needs none of the service's
+	// dependencies.
+	if len(os.Args) > 1 && os.Args[1] == "mockstore" {
+		return mockstore.NewServer(cfg.MockStore).Run(ctx)
+	}
 
 	deps, err := initializeDependencies(cfg)
 	if err != nil {
@@ -48,72 +72,51 @@ func run() error {
 	}
 	defer deps.cleanup(ctx)
 
-	logger.LogEvent(ctx, "app.startup", "success")
-
-	if err := deps.consumer.Run(ctx); err != nil {
-		logger.Error(ctx, "Consumer exited with error", err)
-		return fmt.Errorf("consumer exited with error: %w", err)
+	if len(os.Args) > 1 {
+		return runCommand(ctx, deps, os.Args[1], os.Args[2:])
 	}
 
-	logger.LogEvent(ctx, "app.shutdown", "success")
-	return nil
-}
-
-type dependencies struct {
-	db       *sql.DB
-	consumer *consumer.KafkaConsumer
-	producer *producer.Producer
-}
-
-func (d *dependencies) cleanup(ctx context.Context) {
-	if d.db != nil {
-		logger.Debug(ctx, "Closing database connection")
-		if err := d.db.Close(); err != nil {
-			logger.Error(ctx, "Error closing database", err)
-		}
+	if deps.admin != nil {
+		go func() {
+			if err := deps.admin.Run(ctx); err != nil {
+				logger.Error(ctx, "Admin server exited with error", err)
+			}
+		}()
 	}
-	if d.consumer != nil {
-		logger.Debug(ctx, "Closing Kafka consumer")
-		if err := d.consumer.Close(); err != nil {
-			logger.Error(ctx, "Error closing Kafka consumer", err)
-		}
-	}
-	if d.producer != nil {
-		logger.Debug(ctx, "Closing Kafka producer")
-		if err := d.producer.Close(); err != nil {
-			logger.Error(ctx, "Error closing Kafka producer", err)
+
+	go func() {
+		if err := deps.svc.RunResumer(ctx, deps.db.PingContext, deps.producer.Ping); err != nil {
+			logger.Error(ctx, "Saga resumer exited with error", err)
 		}
+	}()
+
+	go deps.svc.RunEventSpool(ctx)
+	go config.Watch(ctx, cfg, deps.applyTunables)
+	go deps.svc.RunTranslations(ctx)
+	go deps.svc.RunShadowWrites(ctx)
+	if deps.embedder != nil {
+		go deps.embedder.Run(ctx)
 	}
-}
-
-func initializeDependencies(cfg *config.Config) (*dependencies, error) {
-	httpClient := httpx.New(httpx.Config{
-		Timeout:        cfg.HTTP.Timeout,
-		MaxRetries:     cfg.HTTP.MaxRetries,
-		BackoffInitial: cfg.HTTP.BackoffInitial,
-		BackoffMax:     cfg.HTTP.BackoffMax,
-		UserAgents:     cfg.HTTP.UserAgents,
-	})
 
-	db, err := storage.InitPostgres(cfg.Postgres)
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	if deps.userAgents != nil {
+		go deps.userAgents.Run(ctx)
 	}
 
-	tokenExtractor := appstore.NewTokenExtractor(httpClient)
-	reviewFetcher := appstore.NewReviewFetcher(httpClient, "", *cfg)
-
-	repo := storage.NewReviewRepository(db)
-
-	prod := producer.NewProducer(cfg.Kafka)
+	if deps.grpc != nil {
+		go func() {
+			if err := deps.grpc.Run(ctx); err != nil {
+				logger.Error(ctx, "gRPC server exited with error", err)
+			}
+		}()
+	}
 
-	svc := service.NewIngestService(tokenExtractor, reviewFetcher, repo, prod)
+	logger.LogEvent(ctx, "app.startup", "success")
 
-	consumer := consumer.NewKafkaConsumer(cfg.Kafka, svc)
+	if err := deps.consumer.Run(ctx); err != nil {
+		logger.Error(ctx, "Consumer exited with error", err)
+		return fmt.Errorf("consumer exited with error: %w", err)
+	}
 
-	return &dependencies{
-		db:       db,
-		consumer: consumer,
-		producer: prod,
-	}, nil
+	logger.LogEvent(ctx, "app.shutdown", "success")
+	return nil
 }
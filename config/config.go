@@ -2,25 +2,166 @@ package config
 
 import (
 	"fmt"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/quiby-ai/common/pkg/events"
+	"github.com/quiby-ai/review-ingestor/internal/features"
 	"github.com/quiby-ai/review-ingestor/internal/logger"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	AppStore AppStoreConfig
-	HTTP     HTTPConfig
-	Kafka    KafkaConfig
-	Postgres PostgresConfig
-	Logging  logger.Config
+	AppStore       AppStoreConfig
+	AmazonStore    AmazonStoreConfig
+	Huawei         HuaweiConfig
+	Trustpilot     TrustpilotConfig
+	Steam          SteamConfig
+	MicrosoftStore MicrosoftStoreConfig
+	GalaxyStore    GalaxyStoreConfig
+	HTTP           HTTPConfig
+	Kafka          KafkaConfig
+	Postgres       PostgresConfig
+	Cache          CacheConfig
+	RateLimit      RateLimitConfig
+	Admin          AdminConfig
+	GRPC           GRPCConfig
+	Webhook        WebhookConfig
+	S3             S3Config
+	Debug          DebugConfig
+	Ingest         IngestConfig
+	AutoTune       AutoTuneConfig
+	Faults         FaultsConfig
+	MockStore      MockStoreConfig
+	Translate      TranslateConfig
+	Embeddings     EmbeddingsConfig
+	Anomaly        AnomalyConfig
+	Watchlist      WatchlistConfig
+	ResponseSLA    ResponseSLAConfig
+	Shadow         ShadowConfig
+	Reload         ReloadConfig
+	Accounting     AccountingConfig
+	Features       map[string]bool
+	Apps           AppOverrides
+	Logging        logger.Config
+}
+
+// AppOverride replaces global settings for one app, for flagship apps that
+// need different politeness settings than small ones. Unset fields keep the
+// global value. Overrides are resolved when a saga starts.
+type AppOverride struct {
+	// MaxReviews caps the reviews fetched per storefront.
+	MaxReviews      int            `mapstructure:"max_reviews"`
+	PageDelay       *time.Duration `mapstructure:"page_delay"`
+	PageDelayJitter *time.Duration `mapstructure:"page_delay_jitter"`
+	// Proxy is the URL of the HTTP or SOCKS5 proxy the app's store requests
+	// go through.
+	Proxy string `mapstructure:"proxy"`
+	// Translate and Hooks switch translation and the post-save hooks, such
+	// as embeddings, on or off for the app's reviews. Both still need the
+	// feature itself to be enabled.
+	Translate *bool `mapstructure:"translate"`
+	Hooks     *bool `mapstructure:"hooks"`
+	// Tenant is the tenant the app's usage is accounted to when requests do
+	// not name one.
+	Tenant string `mapstructure:"tenant"`
+	// Features switches feature flags on or off for the app, ahead of the
+	// global flags.
+	Features map[string]bool `mapstructure:"features"`
+}
+
+// AppOverrides holds the overrides of each app, keyed by lower-cased app ID.
+type AppOverrides map[string]AppOverride
+
+// For returns the overrides of appID and whether it has any.
+func (o AppOverrides) For(appID string) (AppOverride, bool) {
+	override, ok := o[strings.ToLower(appID)]
+	return override, ok
 }
 
 type AppStoreConfig struct {
-	Referrer string
-	APIHost  string
-	APIPath  string
+	Referrer          string
+	APIHost           string
+	APIPath           string
+	Limit             int
+	DefaultStorefront string
+	PageDelay         time.Duration
+	PageDelayJitter   time.Duration
+	// PageWindow is the number of fetched reviews held in memory before they
+	// are flushed to the repository.
+	PageWindow int
+	// Locales overrides the display locale sent as the l parameter, keyed
+	// by lower-case storefront.
+	Locales map[string]string
+	// DateLayouts are the accepted review timestamp layouts, tried in order.
+	// Empty uses normalize.DefaultTimestampLayouts.
+	DateLayouts []string
+	// LookupURL is the iTunes lookup API, used to resolve the landing page
+	// slug before token extraction. With PrecheckAvailability each storefront
+	// is also looked up before fetching, and storefronts where the app is not
+	// available are skipped.
+	LookupURL            string
+	PrecheckAvailability bool
+	// DiscoveryTTL is how long the storefronts discovered for an app are
+	// reused when a request asks for all countries; DiscoveryConcurrency
+	// bounds the lookups in flight while discovering.
+	DiscoveryTTL         time.Duration
+	DiscoveryConcurrency int
+	// LandingHost replaces the scheme and host of landing page URLs, e.g.
+	// to extract tokens from the mock store. Empty uses apps.apple.com.
+	LandingHost string
+}
+
+// AmazonStoreConfig configures the Amazon Appstore review source, used for
+// requests with store "amazon".
+type AmazonStoreConfig struct {
+	APIHost string
+	APIPath string
+	Limit   int
+}
+
+// HuaweiConfig configures the Huawei AppGallery review source. Hosts maps
+// lower-case storefronts to the region host serving them, with "default" used
+// for unlisted storefronts.
+type HuaweiConfig struct {
+	Hosts    map[string]string
 	Limit    int
+	TokenTTL time.Duration
+}
+
+// TrustpilotConfig configures the Trustpilot review source, used for requests
+// with store "trustpilot".
+type TrustpilotConfig struct {
+	APIHost string
+	APIKey  string
+	Limit   int
+}
+
+// SteamConfig configures the Steam review source, used for requests with
+// store "steam".
+type SteamConfig struct {
+	APIHost string
+	Limit   int
+}
+
+// MicrosoftStoreConfig configures the Microsoft Store review source, used
+// for requests with store "msstore". Locale is the language reviews are
+// requested in and DeviceFamily the platform whose ratings are read, e.g.
+// Windows.Desktop.
+type MicrosoftStoreConfig struct {
+	APIHost      string
+	Locale       string
+	DeviceFamily string
+	Limit        int
+}
+
+// GalaxyStoreConfig configures the Samsung Galaxy Store review source, used
+// for requests with store "galaxy". Limit is the number of reviews per page.
+type GalaxyStoreConfig struct {
+	APIHost string
+	Limit   int
 }
 
 type HTTPConfig struct {
@@ -29,15 +170,456 @@ type HTTPConfig struct {
 	BackoffInitial time.Duration
 	BackoffMax     time.Duration
 	UserAgents     []string
+	// UserAgentsSource, when set, is a URL or file path the user agent list
+	// is reloaded from every UserAgentsRefresh; UserAgents stays the
+	// fallback while it cannot be loaded.
+	UserAgentsSource  string
+	UserAgentsRefresh time.Duration
+	// MaxBodyBytes caps the decoded size of a response body; larger
+	// responses fail instead of being read into memory.
+	MaxBodyBytes int
 }
 
 type KafkaConfig struct {
-	Brokers []string
-	GroupID string
+	Brokers     []string
+	GroupID     string
+	Concurrency int
+	// RequestTopic carries extract requests and CompletedTopic and
+	// CancelledTopic the saga outcomes. They default to the shared pipeline
+	// topics; override them for staging environments or topic migrations.
+	RequestTopic        string
+	CompletedTopic      string
+	CancelledTopic      string
+	PriorityTopic       string
+	PriorityConcurrency int
+	ProgressTopic       string
+	// CancelTopic carries cancel requests keyed by saga ID. Each instance
+	// consumes it with its own group, from the end of the topic.
+	CancelTopic string
+	// CommitMode is auto (offsets committed as messages are read, every
+	// CommitInterval) or manual (committed synchronously once handled).
+	CommitMode     string
+	CommitInterval time.Duration
+	// StartFrom positions partitions the group has no committed offset for:
+	// earliest, latest or an RFC 3339 timestamp.
+	StartFrom string
+	// OffsetReset decides what happens when a committed offset is out of
+	// range: auto repositions to the nearest valid offset, none stops the
+	// consumer with an error.
+	OffsetReset string
+	// SessionTimeout is how long the group coordinator waits for a
+	// heartbeat, sent every HeartbeatInterval, before it evicts the
+	// instance. Heartbeats go out in the background while sagas run.
+	SessionTimeout    time.Duration
+	HeartbeatInterval time.Duration
+	// MaxPollInterval is how long the coordinator waits for members to
+	// rejoin during a rebalance (the rebalance timeout).
+	MaxPollInterval time.Duration
+	// MaxProcessingTime, when set, cancels a message's saga once it has run
+	// that long; it is checkpointed like a saga cancelled by an operator.
+	MaxProcessingTime time.Duration
+	// GroupBalancer assigns partitions to group members: range, roundrobin
+	// or sticky, which keeps partitions with the instance named InstanceID
+	// across rebalances so rolling deployments move as few as possible.
+	// InstanceID defaults to the host name.
+	GroupBalancer string
+	InstanceID    string
+	// DLQTopic receives messages of the shared group's topics that fail
+	// validation, with the problems in headers. Empty drops them after
+	// logging.
+	DLQTopic string
+	// ReviewTopic receives review.ingested events when
+	// IngestConfig.PublishReviews is enabled.
+	ReviewTopic string
+	// ResumeTopic carries the internal ExtractResume events that continue
+	// sagas suspended during a Postgres or Kafka outage.
+	ResumeTopic string
+	// AnomalyTopic receives RatingAnomalyDetected events when
+	// AnomalyConfig is enabled.
+	AnomalyTopic string
+	// WatchlistTopic receives review.watchlist.hit events for new reviews
+	// matching WatchlistConfig.
+	WatchlistTopic string
+	// SLATopic receives ResponseSLABreached events when ResponseSLAConfig
+	// is enabled.
+	SLATopic string
+	// Subscriptions are further topics consumed next to the ones above, each
+	// handed to a named handler of the consumer's router.
+	Subscriptions []Subscription
+}
+
+// Subscription consumes Topic with Concurrency group members and hands its
+// messages to the router handler named Handler (extract, resume, cancel or
+// one registered in code). Broadcast topics are consumed by every instance
+// with a group named after its instance ID, always from the latest offset,
+// like the cancel topic.
+type Subscription struct {
+	Topic       string `mapstructure:"topic"`
+	Handler     string `mapstructure:"handler"`
+	Concurrency int    `mapstructure:"concurrency"`
+	Broadcast   bool   `mapstructure:"broadcast"`
 }
 
+const (
+	CommitModeAuto   = "auto"
+	CommitModeManual = "manual"
+
+	StartFromEarliest = "earliest"
+	StartFromLatest   = "latest"
+
+	OffsetResetAuto = "auto"
+	OffsetResetNone = "none"
+
+	GroupBalancerRange      = "range"
+	GroupBalancerRoundRobin = "roundrobin"
+	GroupBalancerSticky     = "sticky"
+)
+
 type PostgresConfig struct {
 	DSN string
+	// StatementTimeout bounds each attempt of a review write. Writes failing
+	// with a transient error (timeout, serialization failure, dropped
+	// connection) are retried up to MaxRetries times with exponential backoff.
+	StatementTimeout time.Duration
+	MaxRetries       int
+	BackoffInitial   time.Duration
+	BackoffMax       time.Duration
+}
+
+type CacheConfig struct {
+	Enabled bool
+	TTL     time.Duration
+}
+
+const (
+	CountryOrderRequest       = "request"
+	CountryOrderLargestFirst  = "largest_first"
+	CountryOrderSmallestFirst = "smallest_first"
+)
+
+const (
+	CompletionPayloadSummary     = "summary"
+	CompletionPayloadDetailed    = "detailed"
+	CompletionPayloadWithSamples = "with_samples"
+)
+
+const (
+	PublishReviewsOff    = "off"
+	PublishReviewsReview = "review"
+	PublishReviewsBatch  = "batch"
+)
+
+const (
+	SaveErrorPolicyBestEffort = "best-effort"
+	SaveErrorPolicyFailFast   = "fail-fast"
+	SaveErrorPolicyThreshold  = "threshold"
+)
+
+const (
+	DeletionModeOff    = "off"
+	DeletionModeDetect = "detect"
+	DeletionModeMark   = "mark"
+)
+
+// Built-in review pipeline stages, in their default order.
+const (
+	StageNormalize = "normalize"
+	StageDedupe    = "dedupe"
+	StageEnrich    = "enrich"
+	StagePersist   = "persist"
+	StageEmit      = "emit"
+)
+
+// DefaultPipeline is the stage order used when ingest.pipeline is not set.
+var DefaultPipeline = []string{StageNormalize, StageDedupe, StageEnrich, StagePersist, StageEmit}
+
+type IngestConfig struct {
+	// SaveErrorPolicy decides what happens when reviews fail to save:
+	// best-effort keeps going, fail-fast aborts the country on the first
+	// failure and threshold aborts when the failed share exceeds
+	// SaveErrorThreshold (0..1).
+	SaveErrorPolicy    string
+	SaveErrorThreshold float64
+	// HeartbeatInterval is how often running sagas log their progress. When
+	// PublishHeartbeats is set the progress is also sent to the progress topic.
+	HeartbeatInterval time.Duration
+	PublishHeartbeats bool
+	// DefaultWindow is how far back requests without date_from fetch. Zero
+	// rejects such requests.
+	DefaultWindow time.Duration
+	// Lookback, when set, makes every saga refetch at least the last
+	// Lookback of reviews and update stored ones whose text, rating or
+	// developer response changed, so edits and late responses are caught.
+	Lookback time.Duration
+	// BulkLoad saves each page window with one COPY-based load instead of
+	// row by row, for backfills of large apps. Windows that fail to load are
+	// saved row by row.
+	BulkLoad bool
+	// CrossCountryDedup drops reviews whose fingerprint matches a review
+	// already seen in another country of the same saga, e.g. one author
+	// posting the same review in several storefronts. Bodies too short to
+	// tell a copy from coincidence are always kept.
+	CrossCountryDedup bool
+	// RetryFailedSaves sets reviews whose save failed with a transient
+	// error aside and saves them again once their country is fetched,
+	// before they are quarantined and counted as failed.
+	RetryFailedSaves bool
+	// PersistQueue is how many fetched page windows may wait to be saved
+	// while fetching goes on; fetching blocks when the queue is full. Zero
+	// saves each window before fetching the next.
+	PersistQueue int
+	// PublishReviews also publishes newly stored reviews to the review
+	// topic: off, review (one event per review) or batch (one event per page
+	// window).
+	PublishReviews string
+	// CompletionPayload shapes the completion event: summary (totals only),
+	// detailed (totals and per-country stats) or with_samples (also up to
+	// CompletionSamples new reviews per country).
+	CompletionPayload string
+	CompletionSamples int
+	// StoreRawPayloads keeps the raw JSON of each App Store review in
+	// raw_payloads, deduplicated by content hash, so fields can be re-derived
+	// without refetching.
+	StoreRawPayloads bool
+	// StorefrontFailureThreshold is how many consecutive failed sagas put a
+	// storefront into a StorefrontCooldown during which later sagas skip it.
+	// Zero disables the cooldown.
+	StorefrontFailureThreshold int
+	StorefrontCooldown         time.Duration
+	// MaxReviews caps the reviews fetched per storefront; zero keeps the
+	// built-in cap of 500. An app's max_reviews replaces it and a request's
+	// may only lower it.
+	MaxReviews int
+	// MaxConcurrentSagas bounds the sagas this instance runs at once, across
+	// all topics; further sagas wait for one to finish. Zero is unbounded.
+	MaxConcurrentSagas int
+	// LockWait is how long a storefront waits for another worker ingesting
+	// the same one before it is skipped and reported as locked.
+	LockWait time.Duration
+	// ResumeDir, when set, is where sagas interrupted by a Postgres or Kafka
+	// outage are checkpointed. Every ResumeCheckInterval the service checks
+	// both and, once they are reachable, emits an ExtractResume event per
+	// checkpoint.
+	ResumeDir           string
+	ResumeCheckInterval time.Duration
+	// PublishSpoolDir, when set, is where completion and progress events
+	// Kafka rejects are kept; they are retried every PublishSpoolInterval.
+	PublishSpoolDir      string
+	PublishSpoolInterval time.Duration
+	// CountryOrder is the order countries are processed in: request (as
+	// requested), largest_first or smallest_first by the number of reviews
+	// already stored per country.
+	CountryOrder string
+	// QualityMinLength is the number of letters and digits below which a
+	// review body is flagged too_short in raw_reviews.quality_flag. Zero
+	// disables the check.
+	QualityMinLength int
+	// Pipeline is the order of the stages each page window of fetched
+	// reviews runs through. It must run normalize before persist; custom
+	// stages registered with the service may be named as well.
+	Pipeline []string
+	// DeletionMode reconciles stored reviews with each fetched window once a
+	// storefront is fetched: off, detect (count the stored reviews no longer
+	// returned) or mark (also set their deleted_at).
+	DeletionMode string
+}
+
+// ReloadConfig configures reloading the tunables (see Tunables) from the
+// config file and environment without a restart. Zero Interval disables it.
+type ReloadConfig struct {
+	Interval time.Duration
+}
+
+// AdminConfig configures the operational HTTP server. Routes that change
+// state require Token as a bearer token and are refused while it is empty.
+type AdminConfig struct {
+	Enabled bool
+	Addr    string
+	Token   string
+}
+
+// WebhookConfig configures the optional webhook posted when a saga completes
+// or fails. It is disabled when URL is empty.
+type WebhookConfig struct {
+	URL            string
+	Secret         string
+	Timeout        time.Duration
+	MaxRetries     int
+	BackoffInitial time.Duration
+	BackoffMax     time.Duration
+}
+
+// S3Config is the S3-compatible object store used for s3:// destinations.
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	AccessKey string
+	SecretKey string
+	UseSSL    bool
+}
+
+// DebugConfig controls capture of failed App Store requests. Captures go to
+// CaptureDest, a local directory or an s3://bucket/prefix URL, with
+// credentials redacted and bodies truncated to CaptureMaxBody bytes.
+type DebugConfig struct {
+	CaptureFailures bool
+	CaptureDest     string
+	CaptureMaxBody  int
+	// CassetteMode records App Store requests to CassettePath (record) or
+	// answers them from it without going to the network (replay). Off by
+	// default.
+	CassetteMode string
+	CassettePath string
+}
+
+const (
+	CassetteModeOff    = "off"
+	CassetteModeRecord = "record"
+	CassetteModeReplay = "replay"
+)
+
+// AutoTuneConfig adapts the App Store page delay and the number of
+// storefronts fetched at once, across all running sagas, to the observed 429
+// rate and latency. The delay stays within [MinDelay, MaxDelay] and the
+// concurrency within [MinConcurrency, MaxConcurrency]; settings are
+// re-evaluated every Window requests.
+type AutoTuneConfig struct {
+	Enabled        bool
+	MinDelay       time.Duration
+	MaxDelay       time.Duration
+	MinConcurrency int
+	MaxConcurrency int
+	// Target429Rate is the share of rate limited requests (0..1) above which
+	// the tuner backs off; LatencyTarget, when set, does the same for the
+	// mean request latency.
+	Target429Rate float64
+	LatencyTarget time.Duration
+	Window        int
+}
+
+// FaultsConfig injects failures for resilience testing in test and staging
+// deployments: HTTP429Rate of fetcher requests get a 429, SlowRate are
+// delayed by SlowDelay and DBErrorRate of review writes fail with the
+// DBErrorCode SQLSTATE. Rates are between 0 and 1.
+type FaultsConfig struct {
+	Enabled     bool
+	HTTP429Rate float64
+	SlowRate    float64
+	SlowDelay   time.Duration
+	DBErrorRate float64
+	DBErrorCode string
+}
+
+// TranslateConfig enables translation of newly stored reviews whose language
+// is known and differs from TargetLanguage. URL is a LibreTranslate
+// compatible /translate endpoint; at most RequestsPerMinute reviews are
+// sent to it per instance.
+type TranslateConfig struct {
+	Enabled           bool
+	URL               string
+	APIKey            string
+	TargetLanguage    string
+	RequestsPerMinute int
+	Timeout           time.Duration
+}
+
+// AnomalyConfig enables rating anomaly detection after each storefront is
+// ingested: when at least MinReviews reviews were written in the last
+// RecentWindow and their average rating is DropThreshold stars or more below
+// the average of the TrailingWindow before it, a RatingAnomalyDetected event
+// is published to KafkaConfig.AnomalyTopic.
+type AnomalyConfig struct {
+	Enabled        bool
+	RecentWindow   time.Duration
+	TrailingWindow time.Duration
+	MinReviews     int
+	DropThreshold  float64
+}
+
+// AccountingConfig enables daily per-tenant usage rollups in
+// usage_accounting. Sagas whose request and app name no tenant are accounted
+// to DefaultTenant.
+type AccountingConfig struct {
+	Enabled       bool
+	DefaultTenant string
+}
+
+// WatchlistConfig lists case-insensitive regular expressions checked against
+// the title and body of every new review. Patterns apply to all apps, Apps
+// adds patterns per app ID. Matches are published to
+// KafkaConfig.WatchlistTopic; no patterns disables the check.
+type WatchlistConfig struct {
+	Patterns []string
+	Apps     map[string][]string
+}
+
+// Enabled reports whether any pattern is configured.
+func (c WatchlistConfig) Enabled() bool {
+	return len(c.Patterns) > 0 || len(c.Apps) > 0
+}
+
+// ResponseSLAConfig sets how soon developers are expected to respond to a
+// review. Stored reviews whose response came later than Threshold after the
+// review are published to KafkaConfig.SLATopic; zero disables the check.
+type ResponseSLAConfig struct {
+	Threshold time.Duration
+}
+
+// Enabled reports whether a threshold is configured.
+func (c ResponseSLAConfig) Enabled() bool {
+	return c.Threshold > 0
+}
+
+// ShadowConfig mirrors review writes to a secondary store, for migrating
+// storage without downtime. Writes are queued after the primary save and
+// applied by a background worker, so the secondary never slows or fails a
+// saga; up to QueueSize writes wait, each bounded by Timeout, and writes
+// arriving while the queue is full are dropped. An empty DSN disables it.
+type ShadowConfig struct {
+	DSN       string
+	QueueSize int
+	Timeout   time.Duration
+}
+
+// Enabled reports whether a secondary store is configured.
+func (c ShadowConfig) Enabled() bool {
+	return c.DSN != ""
+}
+
+// EmbeddingsConfig enables embedding of newly stored reviews into the
+// pgvector column raw_reviews.embedding. URL is an OpenAI compatible
+// /embeddings endpoint returning Dimensions-long vectors for Model. Reviews
+// are sent BatchSize at a time, or after FlushInterval when fewer queued.
+type EmbeddingsConfig struct {
+	Enabled       bool
+	URL           string
+	APIKey        string
+	Model         string
+	Dimensions    int
+	BatchSize     int
+	FlushInterval time.Duration
+	Timeout       time.Duration
+}
+
+// MockStoreConfig configures the local mock App Store started by the
+// mockstore command. Every storefront of every app holds
+// ReviewsPerStorefront deterministic reviews behind bearer Token.
+type MockStoreConfig struct {
+	Addr                 string
+	ReviewsPerStorefront int
+	Token                string
+}
+
+type GRPCConfig struct {
+	Enabled bool
+	Addr    string
+}
+
+type RateLimitConfig struct {
+	Enabled           bool
+	RedisAddr         string
+	KeyPrefix         string
+	RequestsPerMinute int
 }
 
 func Load() (*Config, error) {
@@ -50,21 +632,190 @@ func Load() (*Config, error) {
 	viper.BindEnv("appstore.referrer", "APP_STORE_REFERRER")
 	viper.BindEnv("appstore.api_path", "APP_STORE_API_PATH")
 	viper.BindEnv("appstore.limit", "APP_STORE_LIMIT")
+	viper.BindEnv("appstore.default_storefront", "APP_STORE_DEFAULT_STOREFRONT")
+	viper.BindEnv("appstore.page_delay", "APP_STORE_PAGE_DELAY")
+	viper.BindEnv("appstore.page_delay_jitter", "APP_STORE_PAGE_DELAY_JITTER")
+	viper.BindEnv("appstore.page_window", "APP_STORE_PAGE_WINDOW")
+	viper.BindEnv("appstore.date_layouts", "APP_STORE_DATE_LAYOUTS")
+	viper.BindEnv("appstore.lookup_url", "APP_STORE_LOOKUP_URL")
+	viper.BindEnv("appstore.landing_host", "APP_STORE_LANDING_HOST")
+	viper.BindEnv("appstore.precheck_availability", "APP_STORE_PRECHECK_AVAILABILITY")
+	viper.BindEnv("appstore.discovery_ttl", "APP_STORE_DISCOVERY_TTL")
+	viper.BindEnv("appstore.discovery_concurrency", "APP_STORE_DISCOVERY_CONCURRENCY")
+
+	viper.BindEnv("amazonstore.api_host", "AMAZON_STORE_API_HOST")
+	viper.BindEnv("amazonstore.api_path", "AMAZON_STORE_API_PATH")
+	viper.BindEnv("amazonstore.limit", "AMAZON_STORE_LIMIT")
+
+	viper.BindEnv("huawei.limit", "HUAWEI_LIMIT")
+	viper.BindEnv("huawei.token_ttl", "HUAWEI_TOKEN_TTL")
+
+	viper.BindEnv("trustpilot.api_host", "TRUSTPILOT_API_HOST")
+	viper.BindEnv("trustpilot.limit", "TRUSTPILOT_LIMIT")
+	viper.BindEnv("TRUSTPILOT_API_KEY")
+
+	viper.BindEnv("steam.api_host", "STEAM_API_HOST")
+	viper.BindEnv("steam.limit", "STEAM_LIMIT")
+
+	viper.BindEnv("msstore.api_host", "MSSTORE_API_HOST")
+	viper.BindEnv("msstore.locale", "MSSTORE_LOCALE")
+	viper.BindEnv("msstore.device_family", "MSSTORE_DEVICE_FAMILY")
+	viper.BindEnv("msstore.limit", "MSSTORE_LIMIT")
+
+	viper.BindEnv("galaxystore.api_host", "GALAXY_STORE_API_HOST")
+	viper.BindEnv("galaxystore.limit", "GALAXY_STORE_LIMIT")
 
 	viper.BindEnv("http.timeout_seconds", "HTTP_TIMEOUT_SECONDS")
 	viper.BindEnv("http.max_retries", "HTTP_MAX_RETRIES")
 	viper.BindEnv("http.backoff_initial_sec", "HTTP_BACKOFF_INITIAL_SEC")
 	viper.BindEnv("http.backoff_max_sec", "HTTP_BACKOFF_MAX_SEC")
 	viper.BindEnv("http.user_agents", "HTTP_USER_AGENTS")
+	viper.BindEnv("http.user_agents_source", "HTTP_USER_AGENTS_SOURCE")
+	viper.BindEnv("http.user_agents_refresh", "HTTP_USER_AGENTS_REFRESH")
+	viper.BindEnv("http.max_body_bytes", "HTTP_MAX_BODY_BYTES")
 
 	viper.BindEnv("kafka.brokers", "KAFKA_BROKERS")
 	viper.BindEnv("kafka.group_id", "KAFKA_GROUP_ID")
+	viper.BindEnv("kafka.concurrency", "KAFKA_CONCURRENCY")
+	viper.BindEnv("kafka.request_topic", "KAFKA_REQUEST_TOPIC")
+	viper.BindEnv("kafka.completed_topic", "KAFKA_COMPLETED_TOPIC")
+	viper.BindEnv("kafka.cancelled_topic", "KAFKA_CANCELLED_TOPIC")
+	viper.BindEnv("kafka.priority_topic", "KAFKA_PRIORITY_TOPIC")
+	viper.BindEnv("kafka.priority_concurrency", "KAFKA_PRIORITY_CONCURRENCY")
+	viper.BindEnv("kafka.progress_topic", "KAFKA_PROGRESS_TOPIC")
+	viper.BindEnv("kafka.cancel_topic", "KAFKA_CANCEL_TOPIC")
+	viper.BindEnv("kafka.commit_mode", "KAFKA_COMMIT_MODE")
+	viper.BindEnv("kafka.commit_interval", "KAFKA_COMMIT_INTERVAL")
+	viper.BindEnv("kafka.start_from", "KAFKA_START_FROM")
+	viper.BindEnv("kafka.offset_reset", "KAFKA_OFFSET_RESET")
+	viper.BindEnv("kafka.session_timeout", "KAFKA_SESSION_TIMEOUT")
+	viper.BindEnv("kafka.heartbeat_interval", "KAFKA_HEARTBEAT_INTERVAL")
+	viper.BindEnv("kafka.max_poll_interval", "KAFKA_MAX_POLL_INTERVAL")
+	viper.BindEnv("kafka.max_processing_time", "KAFKA_MAX_PROCESSING_TIME")
+	viper.BindEnv("kafka.group_balancer", "KAFKA_GROUP_BALANCER")
+	viper.BindEnv("kafka.instance_id", "KAFKA_INSTANCE_ID")
+	viper.BindEnv("kafka.dlq_topic", "KAFKA_DLQ_TOPIC")
+	viper.BindEnv("kafka.review_topic", "KAFKA_REVIEW_TOPIC")
+	viper.BindEnv("kafka.anomaly_topic", "KAFKA_ANOMALY_TOPIC")
+	viper.BindEnv("kafka.watchlist_topic", "KAFKA_WATCHLIST_TOPIC")
+	viper.BindEnv("kafka.sla_topic", "KAFKA_SLA_TOPIC")
+	viper.BindEnv("response_sla.threshold", "RESPONSE_SLA_THRESHOLD")
+	viper.BindEnv("shadow.dsn", "SHADOW_DSN")
+	viper.BindEnv("shadow.queue_size", "SHADOW_QUEUE_SIZE")
+	viper.BindEnv("shadow.timeout", "SHADOW_TIMEOUT")
+	viper.BindEnv("kafka.resume_topic", "KAFKA_RESUME_TOPIC")
+
+	viper.BindEnv("cache.enabled", "CACHE_ENABLED")
+	viper.BindEnv("cache.ttl", "CACHE_TTL")
+
+	viper.BindEnv("ratelimit.enabled", "RATELIMIT_ENABLED")
+	viper.BindEnv("ratelimit.key_prefix", "RATELIMIT_KEY_PREFIX")
+	viper.BindEnv("ratelimit.requests_per_minute", "RATELIMIT_REQUESTS_PER_MINUTE")
+	viper.BindEnv("REDIS_ADDR")
+
+	viper.BindEnv("ingest.save_error_policy", "INGEST_SAVE_ERROR_POLICY")
+	viper.BindEnv("ingest.save_error_threshold", "INGEST_SAVE_ERROR_THRESHOLD")
+	viper.BindEnv("ingest.heartbeat_interval", "INGEST_HEARTBEAT_INTERVAL")
+	viper.BindEnv("ingest.publish_heartbeats", "INGEST_PUBLISH_HEARTBEATS")
+	viper.BindEnv("ingest.default_window", "INGEST_DEFAULT_WINDOW")
+	viper.BindEnv("ingest.lookback", "INGEST_LOOKBACK")
+	viper.BindEnv("ingest.bulk_load", "INGEST_BULK_LOAD")
+	viper.BindEnv("ingest.cross_country_dedup", "INGEST_CROSS_COUNTRY_DEDUP")
+	viper.BindEnv("ingest.retry_failed_saves", "INGEST_RETRY_FAILED_SAVES")
+	viper.BindEnv("ingest.persist_queue", "INGEST_PERSIST_QUEUE")
+	viper.BindEnv("ingest.publish_reviews", "INGEST_PUBLISH_REVIEWS")
+	viper.BindEnv("ingest.completion_payload", "INGEST_COMPLETION_PAYLOAD")
+	viper.BindEnv("ingest.completion_samples", "INGEST_COMPLETION_SAMPLES")
+	viper.BindEnv("ingest.store_raw_payloads", "INGEST_STORE_RAW_PAYLOADS")
+	viper.BindEnv("ingest.storefront_failure_threshold", "INGEST_STOREFRONT_FAILURE_THRESHOLD")
+	viper.BindEnv("ingest.storefront_cooldown", "INGEST_STOREFRONT_COOLDOWN")
+	viper.BindEnv("ingest.max_reviews", "INGEST_MAX_REVIEWS")
+	viper.BindEnv("ingest.max_concurrent_sagas", "INGEST_MAX_CONCURRENT_SAGAS")
+	viper.BindEnv("ingest.lock_wait", "INGEST_LOCK_WAIT")
+	viper.BindEnv("ingest.resume_dir", "INGEST_RESUME_DIR")
+	viper.BindEnv("ingest.country_order", "INGEST_COUNTRY_ORDER")
+	viper.BindEnv("ingest.resume_check_interval", "INGEST_RESUME_CHECK_INTERVAL")
+	viper.BindEnv("ingest.publish_spool_dir", "INGEST_PUBLISH_SPOOL_DIR")
+	viper.BindEnv("ingest.publish_spool_interval", "INGEST_PUBLISH_SPOOL_INTERVAL")
+	viper.BindEnv("ingest.quality_min_length", "INGEST_QUALITY_MIN_LENGTH")
+	viper.BindEnv("ingest.pipeline", "INGEST_PIPELINE")
+	viper.BindEnv("ingest.deletion_mode", "INGEST_DELETION_MODE")
+
+	viper.BindEnv("reload.interval", "RELOAD_INTERVAL")
+	viper.BindEnv("admin.enabled", "ADMIN_ENABLED")
+	viper.BindEnv("admin.addr", "ADMIN_ADDR")
+	viper.BindEnv("ADMIN_TOKEN")
+
+	viper.BindEnv("webhook.url", "WEBHOOK_URL")
+	viper.BindEnv("webhook.timeout", "WEBHOOK_TIMEOUT")
+	viper.BindEnv("webhook.max_retries", "WEBHOOK_MAX_RETRIES")
+	viper.BindEnv("WEBHOOK_SECRET")
+
+	viper.BindEnv("s3.endpoint", "S3_ENDPOINT")
+	viper.BindEnv("s3.region", "S3_REGION")
+	viper.BindEnv("s3.use_ssl", "S3_USE_SSL")
+	viper.BindEnv("debug.capture_failures", "DEBUG_CAPTURE_FAILURES")
+	viper.BindEnv("debug.capture_dest", "DEBUG_CAPTURE_DEST")
+	viper.BindEnv("debug.capture_max_body", "DEBUG_CAPTURE_MAX_BODY")
+	viper.BindEnv("debug.cassette_mode", "DEBUG_CASSETTE_MODE")
+	viper.BindEnv("debug.cassette_path", "DEBUG_CASSETTE_PATH")
+	viper.BindEnv("autotune.enabled", "AUTOTUNE_ENABLED")
+	viper.BindEnv("autotune.min_delay", "AUTOTUNE_MIN_DELAY")
+	viper.BindEnv("autotune.max_delay", "AUTOTUNE_MAX_DELAY")
+	viper.BindEnv("autotune.min_concurrency", "AUTOTUNE_MIN_CONCURRENCY")
+	viper.BindEnv("autotune.max_concurrency", "AUTOTUNE_MAX_CONCURRENCY")
+	viper.BindEnv("autotune.target_429_rate", "AUTOTUNE_TARGET_429_RATE")
+	viper.BindEnv("autotune.latency_target", "AUTOTUNE_LATENCY_TARGET")
+	viper.BindEnv("autotune.window", "AUTOTUNE_WINDOW")
+	viper.BindEnv("mockstore.addr", "MOCKSTORE_ADDR")
+	viper.BindEnv("mockstore.reviews_per_storefront", "MOCKSTORE_REVIEWS_PER_STOREFRONT")
+	viper.BindEnv("mockstore.token", "MOCKSTORE_TOKEN")
+	viper.BindEnv("translate.enabled", "TRANSLATE_ENABLED")
+	viper.BindEnv("translate.url", "TRANSLATE_URL")
+	viper.BindEnv("translate.api_key", "TRANSLATE_API_KEY")
+	viper.BindEnv("translate.target_language", "TRANSLATE_TARGET_LANGUAGE")
+	viper.BindEnv("translate.requests_per_minute", "TRANSLATE_REQUESTS_PER_MINUTE")
+	viper.BindEnv("translate.timeout", "TRANSLATE_TIMEOUT")
+	viper.BindEnv("watchlist.patterns", "WATCHLIST_PATTERNS")
+	viper.BindEnv("feature_flags", "FEATURE_FLAGS")
+	viper.BindEnv("accounting.enabled", "ACCOUNTING_ENABLED")
+	viper.BindEnv("accounting.default_tenant", "ACCOUNTING_DEFAULT_TENANT")
+	viper.BindEnv("anomaly.enabled", "ANOMALY_ENABLED")
+	viper.BindEnv("anomaly.recent_window", "ANOMALY_RECENT_WINDOW")
+	viper.BindEnv("anomaly.trailing_window", "ANOMALY_TRAILING_WINDOW")
+	viper.BindEnv("anomaly.min_reviews", "ANOMALY_MIN_REVIEWS")
+	viper.BindEnv("anomaly.drop_threshold", "ANOMALY_DROP_THRESHOLD")
+	viper.BindEnv("embeddings.enabled", "EMBEDDINGS_ENABLED")
+	viper.BindEnv("embeddings.url", "EMBEDDINGS_URL")
+	viper.BindEnv("embeddings.api_key", "EMBEDDINGS_API_KEY")
+	viper.BindEnv("embeddings.model", "EMBEDDINGS_MODEL")
+	viper.BindEnv("embeddings.dimensions", "EMBEDDINGS_DIMENSIONS")
+	viper.BindEnv("embeddings.batch_size", "EMBEDDINGS_BATCH_SIZE")
+	viper.BindEnv("embeddings.flush_interval", "EMBEDDINGS_FLUSH_INTERVAL")
+	viper.BindEnv("embeddings.timeout", "EMBEDDINGS_TIMEOUT")
+	viper.BindEnv("faults.enabled", "FAULTS_ENABLED")
+	viper.BindEnv("faults.http_429_rate", "FAULTS_HTTP_429_RATE")
+	viper.BindEnv("faults.slow_rate", "FAULTS_SLOW_RATE")
+	viper.BindEnv("faults.slow_delay", "FAULTS_SLOW_DELAY")
+	viper.BindEnv("faults.db_error_rate", "FAULTS_DB_ERROR_RATE")
+	viper.BindEnv("faults.db_error_code", "FAULTS_DB_ERROR_CODE")
+	viper.BindEnv("S3_ACCESS_KEY")
+	viper.BindEnv("S3_SECRET_KEY")
+
+	viper.BindEnv("grpc.enabled", "GRPC_ENABLED")
+	viper.BindEnv("grpc.addr", "GRPC_ADDR")
 
 	viper.BindEnv("PG_DSN")
+	viper.BindEnv("postgres.statement_timeout", "PG_STATEMENT_TIMEOUT")
+	viper.BindEnv("postgres.max_retries", "PG_MAX_RETRIES")
+	viper.BindEnv("postgres.backoff_initial", "PG_BACKOFF_INITIAL")
+	viper.BindEnv("postgres.backoff_max", "PG_BACKOFF_MAX")
 	viper.BindEnv("APP_STORE_API_HOST")
 
 	viper.BindEnv("logging.level", "LOG_LEVEL")
 	viper.BindEnv("logging.format", "LOG_FORMAT")
+	viper.BindEnv("logging.service", "LOG_SERVICE")
+	viper.BindEnv("logging.instance", "LOG_INSTANCE", "POD_NAME")
 
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
@@ -72,31 +823,396 @@ func Load() (*Config, error) {
 
 	config := &Config{
 		AppStore: AppStoreConfig{
-			Referrer: viper.GetString("appstore.referrer"),
-			APIHost:  viper.GetString("APP_STORE_API_HOST"),
-			APIPath:  viper.GetString("appstore.api_path"),
-			Limit:    viper.GetInt("appstore.limit"),
+			Referrer:             viper.GetString("appstore.referrer"),
+			APIHost:              viper.GetString("APP_STORE_API_HOST"),
+			APIPath:              viper.GetString("appstore.api_path"),
+			Limit:                viper.GetInt("appstore.limit"),
+			DefaultStorefront:    viper.GetString("appstore.default_storefront"),
+			PageDelay:            viper.GetDuration("appstore.page_delay"),
+			PageDelayJitter:      viper.GetDuration("appstore.page_delay_jitter"),
+			PageWindow:           getIntWithDefault("appstore.page_window", 200),
+			Locales:              viper.GetStringMapString("appstore.locales"),
+			DateLayouts:          viper.GetStringSlice("appstore.date_layouts"),
+			LookupURL:            getStringWithDefault("appstore.lookup_url", "https://itunes.apple.com/lookup"),
+			LandingHost:          viper.GetString("appstore.landing_host"),
+			PrecheckAvailability: viper.GetBool("appstore.precheck_availability"),
+			DiscoveryTTL:         getDurationWithDefault("appstore.discovery_ttl", 24*time.Hour),
+			DiscoveryConcurrency: getIntWithDefault("appstore.discovery_concurrency", 8),
+		},
+		AmazonStore: AmazonStoreConfig{
+			APIHost: viper.GetString("amazonstore.api_host"),
+			APIPath: viper.GetString("amazonstore.api_path"),
+			Limit:   getIntWithDefault("amazonstore.limit", 20),
+		},
+		Huawei: HuaweiConfig{
+			Hosts:    viper.GetStringMapString("huawei.hosts"),
+			Limit:    getIntWithDefault("huawei.limit", 25),
+			TokenTTL: getDurationWithDefault("huawei.token_ttl", 10*time.Minute),
+		},
+		Trustpilot: TrustpilotConfig{
+			APIHost: getStringWithDefault("trustpilot.api_host", "https://api.trustpilot.com"),
+			APIKey:  viper.GetString("TRUSTPILOT_API_KEY"),
+			Limit:   getIntWithDefault("trustpilot.limit", 100),
+		},
+		Steam: SteamConfig{
+			APIHost: getStringWithDefault("steam.api_host", "https://store.steampowered.com"),
+			Limit:   getIntWithDefault("steam.limit", 100),
+		},
+		MicrosoftStore: MicrosoftStoreConfig{
+			APIHost:      getStringWithDefault("msstore.api_host", "https://storeedgefd.dsx.mp.microsoft.com"),
+			Locale:       getStringWithDefault("msstore.locale", "en-US"),
+			DeviceFamily: getStringWithDefault("msstore.device_family", "Windows.Desktop"),
+			Limit:        getIntWithDefault("msstore.limit", 25),
+		},
+		GalaxyStore: GalaxyStoreConfig{
+			APIHost: getStringWithDefault("galaxystore.api_host", "https://galaxystore.samsung.com"),
+			Limit:   getIntWithDefault("galaxystore.limit", 15),
 		},
 		Kafka: KafkaConfig{
-			Brokers: viper.GetStringSlice("kafka.brokers"),
-			GroupID: viper.GetString("kafka.group_id"),
+			Brokers:             viper.GetStringSlice("kafka.brokers"),
+			GroupID:             viper.GetString("kafka.group_id"),
+			Concurrency:         getIntWithDefault("kafka.concurrency", 1),
+			RequestTopic:        getStringWithDefault("kafka.request_topic", events.PipelineExtractRequest),
+			CompletedTopic:      getStringWithDefault("kafka.completed_topic", events.PipelineExtractCompleted),
+			CancelledTopic:      viper.GetString("kafka.cancelled_topic"),
+			PriorityTopic:       viper.GetString("kafka.priority_topic"),
+			PriorityConcurrency: getIntWithDefault("kafka.priority_concurrency", 1),
+			ProgressTopic:       viper.GetString("kafka.progress_topic"),
+			CancelTopic:         viper.GetString("kafka.cancel_topic"),
+			CommitMode:          getStringWithDefault("kafka.commit_mode", CommitModeAuto),
+			CommitInterval:      getDurationWithDefault("kafka.commit_interval", time.Second),
+			StartFrom:           getStringWithDefault("kafka.start_from", StartFromEarliest),
+			OffsetReset:         getStringWithDefault("kafka.offset_reset", OffsetResetAuto),
+			SessionTimeout:      getDurationWithDefault("kafka.session_timeout", 30*time.Second),
+			HeartbeatInterval:   getDurationWithDefault("kafka.heartbeat_interval", 3*time.Second),
+			MaxPollInterval:     getDurationWithDefault("kafka.max_poll_interval", 5*time.Minute),
+			MaxProcessingTime:   viper.GetDuration("kafka.max_processing_time"),
+			GroupBalancer:       getStringWithDefault("kafka.group_balancer", GroupBalancerRange),
+			InstanceID:          viper.GetString("kafka.instance_id"),
+			DLQTopic:            viper.GetString("kafka.dlq_topic"),
+			ReviewTopic:         viper.GetString("kafka.review_topic"),
+			AnomalyTopic:        viper.GetString("kafka.anomaly_topic"),
+			WatchlistTopic:      viper.GetString("kafka.watchlist_topic"),
+			SLATopic:            viper.GetString("kafka.sla_topic"),
+			ResumeTopic:         viper.GetString("kafka.resume_topic"),
 		},
 		Postgres: PostgresConfig{
-			DSN: viper.GetString("PG_DSN"),
+			DSN:              viper.GetString("PG_DSN"),
+			StatementTimeout: getDurationWithDefault("postgres.statement_timeout", 5*time.Second),
+			MaxRetries:       getIntWithDefault("postgres.max_retries", 3),
+			BackoffInitial:   getDurationWithDefault("postgres.backoff_initial", 200*time.Millisecond),
+			BackoffMax:       getDurationWithDefault("postgres.backoff_max", 2*time.Second),
+		},
+		Cache: CacheConfig{
+			Enabled: viper.GetBool("cache.enabled"),
+			TTL:     viper.GetDuration("cache.ttl"),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:           viper.GetBool("ratelimit.enabled"),
+			RedisAddr:         viper.GetString("REDIS_ADDR"),
+			KeyPrefix:         getStringWithDefault("ratelimit.key_prefix", "ingestor:ratelimit"),
+			RequestsPerMinute: viper.GetInt("ratelimit.requests_per_minute"),
+		},
+		Ingest: IngestConfig{
+			SaveErrorPolicy:            getStringWithDefault("ingest.save_error_policy", SaveErrorPolicyBestEffort),
+			SaveErrorThreshold:         viper.GetFloat64("ingest.save_error_threshold"),
+			HeartbeatInterval:          viper.GetDuration("ingest.heartbeat_interval"),
+			PublishHeartbeats:          viper.GetBool("ingest.publish_heartbeats"),
+			DefaultWindow:              viper.GetDuration("ingest.default_window"),
+			Lookback:                   viper.GetDuration("ingest.lookback"),
+			BulkLoad:                   viper.GetBool("ingest.bulk_load"),
+			CrossCountryDedup:          viper.GetBool("ingest.cross_country_dedup"),
+			RetryFailedSaves:           viper.GetBool("ingest.retry_failed_saves"),
+			PersistQueue:               viper.GetInt("ingest.persist_queue"),
+			PublishReviews:             getStringWithDefault("ingest.publish_reviews", PublishReviewsOff),
+			CompletionPayload:          getStringWithDefault("ingest.completion_payload", CompletionPayloadDetailed),
+			CompletionSamples:          getIntWithDefault("ingest.completion_samples", 3),
+			StoreRawPayloads:           viper.GetBool("ingest.store_raw_payloads"),
+			StorefrontFailureThreshold: viper.GetInt("ingest.storefront_failure_threshold"),
+			StorefrontCooldown:         getDurationWithDefault("ingest.storefront_cooldown", time.Hour),
+			MaxReviews:                 viper.GetInt("ingest.max_reviews"),
+			MaxConcurrentSagas:         viper.GetInt("ingest.max_concurrent_sagas"),
+			LockWait:                   getDurationWithDefault("ingest.lock_wait", 30*time.Second),
+			ResumeDir:                  viper.GetString("ingest.resume_dir"),
+			CountryOrder:               getStringWithDefault("ingest.country_order", CountryOrderRequest),
+			ResumeCheckInterval:        getDurationWithDefault("ingest.resume_check_interval", 15*time.Second),
+			PublishSpoolDir:            viper.GetString("ingest.publish_spool_dir"),
+			PublishSpoolInterval:       getDurationWithDefault("ingest.publish_spool_interval", 30*time.Second),
+			QualityMinLength:           viper.GetInt("ingest.quality_min_length"),
+			Pipeline:                   viper.GetStringSlice("ingest.pipeline"),
+			DeletionMode:               getStringWithDefault("ingest.deletion_mode", DeletionModeOff),
+		},
+		Reload: ReloadConfig{
+			Interval: viper.GetDuration("reload.interval"),
+		},
+		Admin: AdminConfig{
+			Enabled: viper.GetBool("admin.enabled"),
+			Addr:    getStringWithDefault("admin.addr", ":8080"),
+			Token:   viper.GetString("ADMIN_TOKEN"),
+		},
+		Webhook: WebhookConfig{
+			URL:            viper.GetString("webhook.url"),
+			Secret:         viper.GetString("WEBHOOK_SECRET"),
+			Timeout:        getDurationWithDefault("webhook.timeout", 10*time.Second),
+			MaxRetries:     viper.GetInt("webhook.max_retries"),
+			BackoffInitial: getDurationWithDefault("webhook.backoff_initial", time.Second),
+			BackoffMax:     getDurationWithDefault("webhook.backoff_max", 30*time.Second),
+		},
+		S3: S3Config{
+			Endpoint:  getStringWithDefault("s3.endpoint", "s3.amazonaws.com"),
+			Region:    viper.GetString("s3.region"),
+			AccessKey: viper.GetString("S3_ACCESS_KEY"),
+			SecretKey: viper.GetString("S3_SECRET_KEY"),
+			UseSSL:    viper.GetBool("s3.use_ssl"),
+		},
+		Debug: DebugConfig{
+			CaptureFailures: viper.GetBool("debug.capture_failures"),
+			CaptureDest:     getStringWithDefault("debug.capture_dest", "captures"),
+			CaptureMaxBody:  getIntWithDefault("debug.capture_max_body", 64<<10),
+			CassetteMode:    getStringWithDefault("debug.cassette_mode", CassetteModeOff),
+			CassettePath:    getStringWithDefault("debug.cassette_path", "cassettes/appstore.json"),
+		},
+		AutoTune: AutoTuneConfig{
+			Enabled:        viper.GetBool("autotune.enabled"),
+			MinDelay:       getDurationWithDefault("autotune.min_delay", 100*time.Millisecond),
+			MaxDelay:       getDurationWithDefault("autotune.max_delay", 10*time.Second),
+			MinConcurrency: getIntWithDefault("autotune.min_concurrency", 1),
+			MaxConcurrency: getIntWithDefault("autotune.max_concurrency", 4),
+			Target429Rate:  getFloat64WithDefault("autotune.target_429_rate", 0.02),
+			LatencyTarget:  viper.GetDuration("autotune.latency_target"),
+			Window:         getIntWithDefault("autotune.window", 20),
+		},
+		MockStore: MockStoreConfig{
+			Addr:                 getStringWithDefault("mockstore.addr", ":8089"),
+			ReviewsPerStorefront: getIntWithDefault("mockstore.reviews_per_storefront", 120),
+			Token:                getStringWithDefault("mockstore.token", "mock-token"),
+		},
+		Faults: FaultsConfig{
+			Enabled:     viper.GetBool("faults.enabled"),
+			HTTP429Rate: viper.GetFloat64("faults.http_429_rate"),
+			SlowRate:    viper.GetFloat64("faults.slow_rate"),
+			SlowDelay:   getDurationWithDefault("faults.slow_delay", 5*time.Second),
+			DBErrorRate: viper.GetFloat64("faults.db_error_rate"),
+			DBErrorCode: getStringWithDefault("faults.db_error_code", "40001"),
+		},
+		Translate: TranslateConfig{
+			Enabled:           viper.GetBool("translate.enabled"),
+			URL:               viper.GetString("translate.url"),
+			APIKey:            viper.GetString("translate.api_key"),
+			TargetLanguage:    getStringWithDefault("translate.target_language", "en"),
+			RequestsPerMinute: getIntWithDefault("translate.requests_per_minute", 60),
+			Timeout:           getDurationWithDefault("translate.timeout", 10*time.Second),
+		},
+		Watchlist: WatchlistConfig{
+			Patterns: viper.GetStringSlice("watchlist.patterns"),
+			Apps:     viper.GetStringMapStringSlice("watchlist.apps"),
+		},
+		ResponseSLA: ResponseSLAConfig{
+			Threshold: viper.GetDuration("response_sla.threshold"),
+		},
+		Shadow: ShadowConfig{
+			DSN:       viper.GetString("shadow.dsn"),
+			QueueSize: getIntWithDefault("shadow.queue_size", 1000),
+			Timeout:   getDurationWithDefault("shadow.timeout", 2*time.Second),
+		},
+		Accounting: AccountingConfig{
+			Enabled:       viper.GetBool("accounting.enabled"),
+			DefaultTenant: getStringWithDefault("accounting.default_tenant", "default"),
+		},
+		Anomaly: AnomalyConfig{
+			Enabled:        viper.GetBool("anomaly.enabled"),
+			RecentWindow:   getDurationWithDefault("anomaly.recent_window", 24*time.Hour),
+			TrailingWindow: getDurationWithDefault("anomaly.trailing_window", 30*24*time.Hour),
+			MinReviews:     getIntWithDefault("anomaly.min_reviews", 20),
+			DropThreshold:  getFloat64WithDefault("anomaly.drop_threshold", 0.5),
+		},
+		Embeddings: EmbeddingsConfig{
+			Enabled:       viper.GetBool("embeddings.enabled"),
+			URL:           viper.GetString("embeddings.url"),
+			APIKey:        viper.GetString("embeddings.api_key"),
+			Model:         viper.GetString("embeddings.model"),
+			Dimensions:    getIntWithDefault("embeddings.dimensions", 1536),
+			BatchSize:     getIntWithDefault("embeddings.batch_size", 64),
+			FlushInterval: getDurationWithDefault("embeddings.flush_interval", 5*time.Second),
+			Timeout:       getDurationWithDefault("embeddings.timeout", 30*time.Second),
+		},
+		GRPC: GRPCConfig{
+			Enabled: viper.GetBool("grpc.enabled"),
+			Addr:    getStringWithDefault("grpc.addr", ":9090"),
 		},
 		HTTP: HTTPConfig{
-			Timeout:        viper.GetDuration("http.timeout_seconds"),
-			MaxRetries:     viper.GetInt("http.max_retries"),
-			BackoffInitial: viper.GetDuration("http.backoff_initial_sec"),
-			BackoffMax:     viper.GetDuration("http.backoff_max_sec"),
-			UserAgents:     viper.GetStringSlice("http.user_agents"),
+			Timeout:           viper.GetDuration("http.timeout_seconds"),
+			MaxRetries:        viper.GetInt("http.max_retries"),
+			BackoffInitial:    viper.GetDuration("http.backoff_initial_sec"),
+			BackoffMax:        viper.GetDuration("http.backoff_max_sec"),
+			UserAgents:        viper.GetStringSlice("http.user_agents"),
+			UserAgentsSource:  viper.GetString("http.user_agents_source"),
+			UserAgentsRefresh: getDurationWithDefault("http.user_agents_refresh", time.Hour),
+			MaxBodyBytes:      getIntWithDefault("http.max_body_bytes", 10<<20),
 		},
 		Logging: logger.Config{
-			Level:  getStringWithDefault("logging.level", "info"),
-			Format: getStringWithDefault("logging.format", "json"),
+			Level:    getStringWithDefault("logging.level", "info"),
+			Format:   getStringWithDefault("logging.format", "json"),
+			Service:  getStringWithDefault("logging.service", "review-ingestor"),
+			Instance: viper.GetString("logging.instance"),
 		},
 	}
 
+	if config.Ingest.PublishHeartbeats && config.Kafka.ProgressTopic == "" {
+		return nil, fmt.Errorf("kafka.progress_topic is required when heartbeats are published")
+	}
+
+	switch config.Ingest.PublishReviews {
+	case PublishReviewsOff:
+	case PublishReviewsReview, PublishReviewsBatch:
+		if config.Kafka.ReviewTopic == "" {
+			return nil, fmt.Errorf("kafka.review_topic is required when reviews are published")
+		}
+	default:
+		return nil, fmt.Errorf("unknown ingest.publish_reviews mode %q", config.Ingest.PublishReviews)
+	}
+
+	switch config.Ingest.CompletionPayload {
+	case CompletionPayloadSummary, CompletionPayloadDetailed, CompletionPayloadWithSamples:
+	default:
+		return nil, fmt.Errorf("unknown ingest.completion_payload %q", config.Ingest.CompletionPayload)
+	}
+
+	if config.AutoTune.Enabled && (config.AutoTune.MinConcurrency < 1 || config.AutoTune.MaxConcurrency < config.AutoTune.MinConcurrency ||
+		(config.AutoTune.MaxDelay > 0 && config.AutoTune.MaxDelay < config.AutoTune.MinDelay)) {
+		return nil, fmt.Errorf("autotune bounds are inconsistent")
+	}
+
+	for name, rate := range map[string]float64{
+		"autotune.target_429_rate": config.AutoTune.Target429Rate,
+		"faults.http_429_rate":     config.Faults.HTTP429Rate,
+		"faults.slow_rate":         config.Faults.SlowRate,
+		"faults.db_error_rate":     config.Faults.DBErrorRate,
+	} {
+		if rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("%s must be between 0 and 1, got %v", name, rate)
+		}
+	}
+
+	if config.Ingest.ResumeDir != "" && config.Kafka.ResumeTopic == "" {
+		return nil, fmt.Errorf("kafka.resume_topic is required when ingest.resume_dir is set")
+	}
+
+	if config.Translate.Enabled && config.Translate.URL == "" {
+		return nil, fmt.Errorf("translate.url is required when translation is enabled")
+	}
+
+	if config.Anomaly.Enabled && config.Kafka.AnomalyTopic == "" {
+		return nil, fmt.Errorf("kafka.anomaly_topic is required when anomaly detection is enabled")
+	}
+
+	if config.Watchlist.Enabled() && config.Kafka.WatchlistTopic == "" {
+		return nil, fmt.Errorf("kafka.watchlist_topic is required when watchlist patterns are set")
+	}
+
+	if config.ResponseSLA.Enabled() && config.Kafka.SLATopic == "" {
+		return nil, fmt.Errorf("kafka.sla_topic is required when response_sla.threshold is set")
+	}
+
+	if config.Shadow.Enabled() && (config.Shadow.QueueSize <= 0 || config.Shadow.Timeout <= 0) {
+		return nil, fmt.Errorf("shadow.queue_size and shadow.timeout must be positive when shadow writes are enabled")
+	}
+
+	if config.Embeddings.Enabled && (config.Embeddings.URL == "" || config.Embeddings.Model == "") {
+		return nil, fmt.Errorf("embeddings.url and embeddings.model are required when embeddings are enabled")
+	}
+
+	switch config.Ingest.CountryOrder {
+	case CountryOrderRequest, CountryOrderLargestFirst, CountryOrderSmallestFirst:
+	default:
+		return nil, fmt.Errorf("unknown ingest.country_order %q", config.Ingest.CountryOrder)
+	}
+
+	switch config.Ingest.DeletionMode {
+	case DeletionModeOff, DeletionModeDetect, DeletionModeMark:
+	default:
+		return nil, fmt.Errorf("unknown ingest.deletion_mode %q", config.Ingest.DeletionMode)
+	}
+
+	switch config.Debug.CassetteMode {
+	case CassetteModeOff, CassetteModeRecord, CassetteModeReplay:
+	default:
+		return nil, fmt.Errorf("unknown debug.cassette_mode %q", config.Debug.CassetteMode)
+	}
+
+	switch config.Ingest.SaveErrorPolicy {
+	case SaveErrorPolicyBestEffort, SaveErrorPolicyFailFast, SaveErrorPolicyThreshold:
+	default:
+		return nil, fmt.Errorf("unknown save error policy %q", config.Ingest.SaveErrorPolicy)
+	}
+
+	if len(config.Ingest.Pipeline) == 0 {
+		config.Ingest.Pipeline = slices.Clone(DefaultPipeline)
+	}
+	normalizeAt, persistAt := slices.Index(config.Ingest.Pipeline, StageNormalize), slices.Index(config.Ingest.Pipeline, StagePersist)
+	if normalizeAt < 0 || persistAt < normalizeAt {
+		return nil, fmt.Errorf("ingest.pipeline must run %s before %s, got %v", StageNormalize, StagePersist, config.Ingest.Pipeline)
+	}
+
+	switch config.Kafka.CommitMode {
+	case CommitModeAuto, CommitModeManual:
+	default:
+		return nil, fmt.Errorf("unknown kafka commit mode %q", config.Kafka.CommitMode)
+	}
+
+	switch config.Kafka.StartFrom {
+	case StartFromEarliest, StartFromLatest:
+	default:
+		if _, err := time.Parse(time.RFC3339, config.Kafka.StartFrom); err != nil {
+			return nil, fmt.Errorf("kafka.start_from must be earliest, latest or an RFC 3339 timestamp: %w", err)
+		}
+	}
+
+	if config.Kafka.HeartbeatInterval >= config.Kafka.SessionTimeout {
+		return nil, fmt.Errorf("kafka.heartbeat_interval %s must be below kafka.session_timeout %s", config.Kafka.HeartbeatInterval, config.Kafka.SessionTimeout)
+	}
+
+	switch config.Kafka.GroupBalancer {
+	case GroupBalancerRange, GroupBalancerRoundRobin, GroupBalancerSticky:
+	default:
+		return nil, fmt.Errorf("unknown kafka.group_balancer %q", config.Kafka.GroupBalancer)
+	}
+
+	switch config.Kafka.OffsetReset {
+	case OffsetResetAuto, OffsetResetNone:
+	default:
+		return nil, fmt.Errorf("unknown kafka offset reset %q", config.Kafka.OffsetReset)
+	}
+
+	if err := viper.UnmarshalKey("kafka.subscriptions", &config.Kafka.Subscriptions); err != nil {
+		return nil, fmt.Errorf("invalid kafka.subscriptions: %w", err)
+	}
+	for i, sub := range config.Kafka.Subscriptions {
+		if sub.Topic == "" || sub.Handler == "" {
+			return nil, fmt.Errorf("kafka.subscriptions[%d] needs a topic and a handler", i)
+		}
+	}
+
+	// Global feature flags: the [features] table with the FEATURE_FLAGS list
+	// applied on top.
+	if err := viper.UnmarshalKey("features", &config.Features); err != nil {
+		return nil, fmt.Errorf("invalid [features] flags: %w", err)
+	}
+	config.Features = features.Resolve(config.Features, features.ParseList(viper.GetString("feature_flags")))
+
+	if err := viper.UnmarshalKey("apps", &config.Apps); err != nil {
+		return nil, fmt.Errorf("invalid [apps] overrides: %w", err)
+	}
+	for appID, override := range config.Apps {
+		if override.Proxy == "" {
+			continue
+		}
+		proxy, err := url.Parse(override.Proxy)
+		if err != nil || (proxy.Scheme != "http" && proxy.Scheme != "https" && proxy.Scheme != "socks5") || proxy.Host == "" {
+			return nil, fmt.Errorf("apps.%s.proxy must be an http, https or socks5 URL, got %q", appID, override.Proxy)
+		}
+	}
+
 	return config, nil
 }
 
@@ -106,3 +1222,24 @@ func getStringWithDefault(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getIntWithDefault(key string, defaultValue int) int {
+	if value := viper.GetInt(key); value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+func getDurationWithDefault(key string, defaultValue time.Duration) time.Duration {
+	if value := viper.GetDuration(key); value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+func getFloat64WithDefault(key string, defaultValue float64) float64 {
+	if value := viper.GetFloat64(key); value > 0 {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// Subsystems reports which optional subsystems the configuration enables,
+// keyed by name.
+func (c *Config) Subsystems() map[string]bool {
+	return map[string]bool{
+		"admin":          c.Admin.Enabled,
+		"grpc":           c.GRPC.Enabled,
+		"cache":          c.Cache.Enabled,
+		"ratelimit":      c.RateLimit.Enabled,
+		"autotune":       c.AutoTune.Enabled,
+		"faults":         c.Faults.Enabled,
+		"translate":      c.Translate.Enabled,
+		"embeddings":     c.Embeddings.Enabled,
+		"anomaly":        c.Anomaly.Enabled,
+		"response_sla":   c.ResponseSLA.Enabled(),
+		"shadow":         c.Shadow.Enabled(),
+		"watchlist":      c.Watchlist.Enabled(),
+		"accounting":     c.Accounting.Enabled,
+		"webhook":        c.Webhook.URL != "",
+		"resume_spool":   c.Ingest.ResumeDir != "",
+		"publish_spool":  c.Ingest.PublishSpoolDir != "",
+		"bulk_load":      c.Ingest.BulkLoad,
+		"raw_payloads":   c.Ingest.StoreRawPayloads,
+		"deletions":      c.Ingest.DeletionMode != DeletionModeOff,
+		"hot_reload":     c.Reload.Interval > 0,
+		"cassette":       c.Debug.CassetteMode != CassetteModeOff,
+		"review_publish": c.Ingest.PublishReviews != PublishReviewsOff,
+	}
+}
+
+// Hash returns a SHA-256 digest of the configuration, so operators can check
+// that replicas run the same settings without exposing them. The version and
+// instance attached to log records are left out, as they differ between
+// builds and hosts rather than configurations.
+func (c *Config) Hash() string {
+	hashed := *c
+	hashed.Logging.Version = ""
+	hashed.Logging.Instance = ""
+	encoded, err := json.Marshal(hashed)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
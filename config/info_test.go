@@ -0,0 +1,24 @@
+package config
+
+import "testing"
+
+func TestHash(t *testing.T) {
+	cfg := &Config{Ingest: IngestConfig{DeletionMode: DeletionModeOff}}
+	hash := cfg.Hash()
+	if hash == "" {
+		t.Fatal("Hash() is empty")
+	}
+
+	built := *cfg
+	built.Logging.Version = "2.0.0"
+	built.Logging.Instance = "ingestor-1"
+	if got := built.Hash(); got != hash {
+		t.Errorf("Hash() changed with version and instance: %s != %s", got, hash)
+	}
+
+	changed := *cfg
+	changed.Ingest.BulkLoad = true
+	if got := changed.Hash(); got == hash {
+		t.Error("Hash() did not change with the configuration")
+	}
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+// Tunables returns the settings that can change without a restart, keyed by
+// their config key.
+func (c *Config) Tunables() map[string]any {
+	return map[string]any{
+		"logging.level":                 c.Logging.Level,
+		"appstore.page_delay":           c.AppStore.PageDelay,
+		"appstore.page_delay_jitter":    c.AppStore.PageDelayJitter,
+		"ratelimit.requests_per_minute": c.RateLimit.RequestsPerMinute,
+		"autotune.min_delay":            c.AutoTune.MinDelay,
+		"autotune.max_delay":            c.AutoTune.MaxDelay,
+		"autotune.min_concurrency":      c.AutoTune.MinConcurrency,
+		"autotune.max_concurrency":      c.AutoTune.MaxConcurrency,
+		"autotune.target_429_rate":      c.AutoTune.Target429Rate,
+		"autotune.latency_target":       c.AutoTune.LatencyTarget,
+		"autotune.window":               c.AutoTune.Window,
+	}
+}
+
+// TunableChanges describes the tunables that differ between old and new as
+// "old -> new", keyed by config key.
+func TunableChanges(old, new *Config) map[string]string {
+	before, after := old.Tunables(), new.Tunables()
+	changes := make(map[string]string)
+	for _, key := range slices.Sorted(maps.Keys(after)) {
+		if before[key] != after[key] {
+			changes[key] = fmt.Sprintf("%v -> %v", before[key], after[key])
+		}
+	}
+	return changes
+}
+
+// Watch reloads the configuration every reload.interval until ctx is done.
+// When a tunable changed, apply is called with the new configuration and a
+// config.reloaded event lists the changes. Other settings still need a
+// restart; a configuration that fails to load is logged and ignored.
+func Watch(ctx context.Context, current *Config, apply func(*Config)) {
+	if current.Reload.Interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(current.Reload.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		next, err := Load()
+		if err != nil {
+			logger.LogEvent(ctx, "config.reloaded", "failed", "error", err.Error())
+			continue
+		}
+		changes := TunableChanges(current, next)
+		if len(changes) == 0 {
+			continue
+		}
+		apply(next)
+		current = next
+		logger.LogEvent(ctx, "config.reloaded", "success", "changes", changes)
+	}
+}
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTunableChanges(t *testing.T) {
+	old := &Config{}
+	old.Logging.Level = "info"
+	old.AppStore.PageDelay = time.Second
+	old.Kafka.Concurrency = 1
+
+	next := *old
+	next.Logging.Level = "debug"
+	next.Kafka.Concurrency = 4
+
+	changes := TunableChanges(old, &next)
+	if len(changes) != 1 || changes["logging.level"] != "info -> debug" {
+		t.Errorf("TunableChanges = %v, want only logging.level info -> debug", changes)
+	}
+}
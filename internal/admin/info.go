@@ -0,0 +1,40 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/quiby-ai/review-ingestor/config"
+)
+
+// BuildInfo identifies the running binary.
+type BuildInfo struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+}
+
+// SetBuildInfo sets the build reported by GET /info.
+func (s *Server) SetBuildInfo(build BuildInfo) {
+	s.build = build
+}
+
+// SetConfig sets the configuration GET /info reports subsystems and a hash
+// of. Call it again after reloaded settings are applied.
+func (s *Server) SetConfig(cfg *config.Config) {
+	s.cfg.Store(cfg)
+}
+
+// handleInfo reports what exactly is running: the build, the subsystems the
+// configuration enables, the global feature flags and a hash of the
+// configuration.
+func (s *Server) handleInfo(w http.ResponseWriter, r *http.Request) {
+	body := map[string]any{
+		"version": s.build.Version,
+		"commit":  s.build.Commit,
+	}
+	if cfg := s.cfg.Load(); cfg != nil {
+		body["subsystems"] = cfg.Subsystems()
+		body["features"] = cfg.Features
+		body["config_hash"] = cfg.Hash()
+	}
+	writeJSON(w, http.StatusOK, body)
+}
@@ -0,0 +1,228 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/metrics"
+	"github.com/quiby-ai/review-ingestor/internal/service"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// Server exposes operational endpoints for the ingestor.
+type Server struct {
+	srv     *http.Server
+	control *storage.ControlRepository
+	reviews *storage.ReviewRepository
+	svc     *service.IngestService
+	baseCtx context.Context
+	token   string
+	build   BuildInfo
+	cfg     atomic.Pointer[config.Config]
+}
+
+func NewServer(cfg config.AdminConfig, control *storage.ControlRepository, reviews *storage.ReviewRepository, svc *service.IngestService) *Server {
+	s := &Server{control: control, reviews: reviews, svc: svc, baseCtx: context.Background(), token: cfg.Token}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /pause", s.authorized(s.handlePause))
+	mux.HandleFunc("POST /resume", s.authorized(s.handleResume))
+	mux.HandleFunc("POST /sagas/{saga_id}/cancel", s.authorized(s.handleCancel))
+	mux.HandleFunc("POST /sagas/{saga_id}/rollback", s.authorized(s.handleRollback))
+	mux.HandleFunc("PUT /apps/{app_id}/countries/{country}/start", s.authorized(s.handleSetStart))
+	mux.HandleFunc("DELETE /apps/{app_id}/countries/{country}/start", s.authorized(s.handleClearStart))
+	mux.HandleFunc("GET /apps/{app_id}/stats", s.handleStats)
+	mux.HandleFunc("GET /reviews/search", s.handleSearch)
+	mux.HandleFunc("GET /info", s.handleInfo)
+	mux.Handle("GET /metrics", metrics.Handler())
+
+	s.srv = &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// Run serves until ctx is cancelled and then shuts the server down.
+func (s *Server) Run(ctx context.Context) error {
+	s.baseCtx = ctx
+
+	errCh := make(chan error, 1)
+	go func() {
+		logger.LogEvent(ctx, "admin.server.started", "success", "addr", s.srv.Addr)
+		if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.srv.Shutdown(shutdownCtx)
+}
+
+// authorized lets a request through to next only when it carries the
+// configured token as a bearer token. Without a token configured every
+// request is refused, so mutating routes are never open by default.
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if s.token == "" || !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			logger.LogEvent(r.Context(), "admin.request.unauthorized", "failed", "method", r.Method, "path", r.URL.Path)
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) handlePause(w http.ResponseWriter, r *http.Request) {
+	scope := scopeFromRequest(r)
+	if err := s.control.Pause(r.Context(), scope); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"scope": scope, "status": "paused"})
+}
+
+func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
+	scope := scopeFromRequest(r)
+	if err := s.control.Resume(r.Context(), scope); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	go func() {
+		if _, err := s.svc.ResumeParked(s.baseCtx, scope); err != nil {
+			logger.Error(s.baseCtx, "Failed to resume parked sagas", err, "scope", scope)
+		}
+	}()
+
+	writeJSON(w, http.StatusAccepted, map[string]any{"scope": scope, "status": "resumed"})
+}
+
+func (s *Server) handleCancel(w http.ResponseWriter, r *http.Request) {
+	sagaID := r.PathValue("saga_id")
+	err := s.svc.CancelRun(sagaID)
+	switch {
+	case errors.Is(err, service.ErrRunNotFound):
+		writeError(w, http.StatusNotFound, err)
+		return
+	case errors.Is(err, service.ErrRunNotActive):
+		writeError(w, http.StatusConflict, err)
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]any{"saga_id": sagaID, "status": service.RunStatusCancelled})
+}
+
+// handleRollback undoes the reviews a saga stored; purge=true deletes them
+// instead of flagging them as rolled back.
+func (s *Server) handleRollback(w http.ResponseWriter, r *http.Request) {
+	sagaID := r.PathValue("saga_id")
+	purge := r.URL.Query().Get("purge") == "true"
+	reviews, err := s.svc.RollbackRun(r.Context(), sagaID, purge)
+	switch {
+	case errors.Is(err, service.ErrRunActive):
+		writeError(w, http.StatusConflict, err)
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"saga_id": sagaID, "purged": purge, "reviews": reviews})
+}
+
+// Search results are capped so a broad query cannot dump a whole app.
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 500
+)
+
+// handleSearch runs a keyword search over the stored reviews of app_id. q
+// takes web search syntax; store, lang and limit are optional.
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	query := storage.SearchQuery{
+		AppID:    params.Get("app_id"),
+		Store:    params.Get("store"),
+		Query:    params.Get("q"),
+		Language: params.Get("lang"),
+		Limit:    defaultSearchLimit,
+	}
+	if query.AppID == "" || query.Query == "" {
+		writeError(w, http.StatusBadRequest, errors.New("app_id and q are required"))
+		return
+	}
+	if raw := params.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			writeError(w, http.StatusBadRequest, errors.New("limit must be a positive integer"))
+			return
+		}
+		query.Limit = min(limit, maxSearchLimit)
+	}
+
+	timer := logger.StartTimer()
+	results, err := s.reviews.SearchReviews(r.Context(), query)
+	if err != nil {
+		logger.LogEventWithLatency(r.Context(), "admin.reviews.searched", "failed", timer(), "app_id", query.AppID)
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	logger.LogEventWithLatency(r.Context(), "admin.reviews.searched", "success", timer(), "app_id", query.AppID, "results", len(results))
+
+	reviews := make([]map[string]any, 0, len(results))
+	for _, result := range results {
+		reviews = append(reviews, map[string]any{
+			"store":       result.Store,
+			"id":          result.ID,
+			"country":     result.Country,
+			"language":    result.Language,
+			"rating":      result.Rating,
+			"title":       result.Title,
+			"content":     result.Content,
+			"reviewed_at": result.ReviewedAt,
+			"rank":        result.Rank,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"app_id": query.AppID, "query": query.Query, "reviews": reviews})
+}
+
+// scopeFromRequest returns the app_id query parameter, or the global scope
+// when it is absent.
+func scopeFromRequest(r *http.Request) string {
+	if appID := r.URL.Query().Get("app_id"); appID != "" {
+		return appID
+	}
+	return storage.GlobalScope
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]any{"error": err.Error()})
+}
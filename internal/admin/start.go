@@ -0,0 +1,71 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/quiby-ai/review-ingestor/internal/service"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// startRequest is the body of PUT /apps/{app_id}/countries/{country}/start.
+type startRequest struct {
+	Offset int    `json:"offset"`
+	Before string `json:"before"`
+}
+
+// handleSetStart makes the next fetch of a storefront start at an offset or
+// date. The store query parameter defaults to the App Store.
+func (s *Server) handleSetStart(w http.ResponseWriter, r *http.Request) {
+	var body startRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid body: %w", err))
+		return
+	}
+	if body.Offset < 0 {
+		writeError(w, http.StatusBadRequest, errors.New("offset must not be negative"))
+		return
+	}
+
+	override := storage.StartOverride{
+		Store:   storeFromRequest(r),
+		AppID:   r.PathValue("app_id"),
+		Country: r.PathValue("country"),
+		Offset:  body.Offset,
+	}
+	if body.Before != "" {
+		before, err := service.ParseRequestDate(body.Before)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid before: %w", err))
+			return
+		}
+		override.Before = &before
+	}
+
+	if err := s.control.SetStartOverride(r.Context(), override); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"store": override.Store, "app_id": override.AppID, "country": strings.ToLower(override.Country),
+		"offset": override.Offset, "before": override.Before})
+}
+
+// handleClearStart removes a start override that has not been used yet.
+func (s *Server) handleClearStart(w http.ResponseWriter, r *http.Request) {
+	store, appID, country := storeFromRequest(r), r.PathValue("app_id"), r.PathValue("country")
+	if err := s.control.ClearStartOverride(r.Context(), store, appID, country); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func storeFromRequest(r *http.Request) string {
+	if store := r.URL.Query().Get("store"); store != "" {
+		return store
+	}
+	return service.StoreAppStore
+}
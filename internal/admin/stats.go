@@ -0,0 +1,102 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/service"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// defaultStatsDays is the window of the stats endpoint without from.
+const defaultStatsDays = 30
+
+// dayStats is a day of GET /apps/{app_id}/stats.
+type dayStats struct {
+	Day          string  `json:"day"`
+	Country      string  `json:"country"`
+	Reviews      int     `json:"reviews"`
+	AvgRating    float64 `json:"avg_rating"`
+	Responded    int     `json:"responded"`
+	ResponseRate float64 `json:"response_rate"`
+}
+
+// handleStats returns daily review counts, average ratings and response
+// rates of an app per storefront, so coverage can be checked without BI
+// tooling. from and to (exclusive) default to the last 30 days; store and
+// country narrow the results.
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	query := storage.StatsQuery{
+		AppID:   r.PathValue("app_id"),
+		Store:   params.Get("store"),
+		Country: params.Get("country"),
+		From:    today.AddDate(0, 0, 1-defaultStatsDays),
+		To:      today.AddDate(0, 0, 1),
+	}
+	for name, bound := range map[string]*time.Time{"from": &query.From, "to": &query.To} {
+		if raw := params.Get(name); raw != "" {
+			parsed, err := service.ParseRequestDate(raw)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("invalid %s: %w", name, err))
+				return
+			}
+			*bound = parsed
+		}
+	}
+	if !query.To.After(query.From) {
+		writeError(w, http.StatusBadRequest, errors.New("to must be after from"))
+		return
+	}
+
+	timer := logger.StartTimer()
+	stats, err := s.reviews.DailyStats(r.Context(), query)
+	if err != nil {
+		logger.LogEventWithLatency(r.Context(), "admin.stats.queried", "failed", timer(), "app_id", query.AppID)
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	logger.LogEventWithLatency(r.Context(), "admin.stats.queried", "success", timer(), "app_id", query.AppID, "days", len(stats))
+
+	days := make([]dayStats, 0, len(stats))
+	var reviews, responded int
+	var ratingSum float64
+	for _, day := range stats {
+		days = append(days, dayStats{
+			Day:          day.Day.Format("2006-01-02"),
+			Country:      day.Country,
+			Reviews:      day.Reviews,
+			AvgRating:    day.AvgRating,
+			Responded:    day.Responded,
+			ResponseRate: ratio(day.Responded, day.Reviews),
+		})
+		reviews += day.Reviews
+		responded += day.Responded
+		ratingSum += day.AvgRating * float64(day.Reviews)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"app_id": query.AppID,
+		"store":  query.Store,
+		"from":   query.From.Format("2006-01-02"),
+		"to":     query.To.Format("2006-01-02"),
+		"days":   days,
+		"totals": map[string]any{
+			"reviews":       reviews,
+			"avg_rating":    ratio(ratingSum, reviews),
+			"responded":     responded,
+			"response_rate": ratio(responded, reviews),
+		},
+	})
+}
+
+func ratio[T int | float64](n T, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
@@ -0,0 +1,158 @@
+package amazonstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/appstore"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/pager"
+
+	"github.com/quiby-ai/common/pkg/httpx"
+)
+
+// dateLayout matches the App Store review date format so reviews from both
+// stores go through the same save path.
+const dateLayout = "2006-01-02T15:04:05Z"
+
+type Review struct {
+	ID             string          `json:"reviewId"`
+	Rating         int             `json:"rating"`
+	Title          string          `json:"title"`
+	Text           string          `json:"text"`
+	SubmittedAt    time.Time       `json:"submittedAt"`
+	DeveloperReply *DeveloperReply `json:"developerReply,omitempty"`
+}
+
+type DeveloperReply struct {
+	Text      string    `json:"text"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type ReviewsResponse struct {
+	Reviews  []Review `json:"reviews"`
+	NextPage int      `json:"nextPage,omitempty"`
+}
+
+// ReviewFetcher pages through Amazon Appstore reviews. The Amazon API needs
+// no session token.
+type ReviewFetcher struct {
+	http httpx.Client
+	cfg  config.AmazonStoreConfig
+}
+
+func NewReviewFetcher(http httpx.Client, cfg config.Config) *ReviewFetcher {
+	return &ReviewFetcher{http: http, cfg: cfg.AmazonStore}
+}
+
+func (r *ReviewFetcher) FetchReviews(ctx context.Context, country, appID string, page int) (*ReviewsResponse, error) {
+	timer := logger.StartTimer()
+	requestURL, headers := r.prepareQuery(country, appID, page)
+
+	logger.Debug(ctx, "Fetching reviews from Amazon Appstore", "country", country, "page", page)
+
+	response, err := r.http.DoGET(ctx, requestURL, nil, headers)
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "amazonstore.reviews.request", "failed", timer(), "country", country, "error", "http_request_failed")
+		return nil, fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+
+	if response.Status == 404 {
+		logger.LogEventWithLatency(ctx, "amazonstore.reviews.request", "failed", timer(), "country", country, "status", 404)
+		return nil, fmt.Errorf("app not found or not available in country %s", country)
+	}
+
+	if response.Status != 200 {
+		logger.LogEventWithLatency(ctx, "amazonstore.reviews.request", "failed", timer(), "country", country, "status", response.Status)
+		return nil, fmt.Errorf("unexpected status code: %d", response.Status)
+	}
+
+	var reviewsResp ReviewsResponse
+	if err := json.Unmarshal(response.Body, &reviewsResp); err != nil {
+		logger.LogEventWithLatency(ctx, "amazonstore.reviews.request", "failed", timer(), "country", country, "error", "json_parse_failed")
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	logger.LogEventWithLatency(ctx, "amazonstore.reviews.request", "success", timer(), "country", country, "reviews_count", len(reviewsResp.Reviews))
+	return &reviewsResp, nil
+}
+
+// StreamReviews pages through reviews and hands them to flush in batches of
+// at least window reviews, converted to the App Store review shape. It
+// returns the number of reviews accepted. opts.Offset is the first page.
+func (r *ReviewFetcher) StreamReviews(ctx context.Context, country string, appID string, opts *appstore.FetchOptions, window int, flush func(context.Context, []appstore.Review) error) (int, error) {
+	first := 0
+	if opts != nil {
+		first = opts.Offset
+	}
+
+	fetch := func(ctx context.Context, cursor string) ([]appstore.Review, string, error) {
+		page, err := strconv.Atoi(cursor)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid page cursor %q: %w", cursor, err)
+		}
+		reviewsResp, err := r.FetchReviews(ctx, country, appID, page)
+		if err != nil {
+			return nil, "", err
+		}
+		reviews := make([]appstore.Review, 0, len(reviewsResp.Reviews))
+		for _, review := range reviewsResp.Reviews {
+			reviews = append(reviews, toAppStoreReview(review))
+		}
+		next := ""
+		if reviewsResp.NextPage > page {
+			next = strconv.Itoa(reviewsResp.NextPage)
+		}
+		return reviews, next, nil
+	}
+
+	return pager.Stream(ctx, strconv.Itoa(first), opts, window, fetch, flush)
+}
+
+func toAppStoreReview(review Review) appstore.Review {
+	converted := appstore.Review{
+		ID: review.ID,
+		Attributes: appstore.ReviewAttributes{
+			Date:   review.SubmittedAt.UTC().Format(dateLayout),
+			Rating: review.Rating,
+			Review: review.Text,
+			Title:  review.Title,
+		},
+	}
+	if review.DeveloperReply != nil {
+		converted.Attributes.DeveloperResponse = &appstore.DeveloperResponse{
+			Body:     review.DeveloperReply.Text,
+			Modified: review.DeveloperReply.UpdatedAt.UTC().Format(dateLayout),
+		}
+	}
+	return converted
+}
+
+func (r *ReviewFetcher) prepareQuery(country, appID string, page int) (string, map[string]string) {
+	host := strings.TrimSuffix(r.cfg.APIHost, "/")
+	path := r.cfg.APIPath
+	path = strings.ReplaceAll(path, "{country}", url.PathEscape(country))
+	path = strings.ReplaceAll(path, "{app_id}", url.PathEscape(appID))
+	path = strings.TrimPrefix(path, "/")
+	baseURL := fmt.Sprintf("%s/%s", host, path)
+
+	params := url.Values{}
+	params.Set("page", strconv.Itoa(page))
+	params.Set("pageSize", strconv.Itoa(r.cfg.Limit))
+	params.Set("sort", "recent")
+
+	requestURL := baseURL + "?" + params.Encode()
+
+	headers := map[string]string{
+		"accept":          "application/json",
+		"accept-language": "en-US,en;q=0.9",
+	}
+
+	return requestURL, headers
+}
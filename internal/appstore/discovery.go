@@ -0,0 +1,96 @@
+package appstore
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+// Storefronts lists the lower-case codes of the App Store storefronts.
+var Storefronts = []string{
+	"ae", "ag", "ai", "al", "am", "ao", "ar", "at", "au", "az", "ba", "bb", "be", "bf", "bg", "bh", "bj", "bm", "bn", "bo",
+	"br", "bs", "bt", "bw", "by", "bz", "ca", "cd", "cg", "ch", "ci", "cl", "cm", "cn", "co", "cr", "cv", "cy", "cz", "de",
+	"dk", "dm", "do", "dz", "ec", "ee", "eg", "es", "fi", "fj", "fm", "fr", "ga", "gb", "gd", "ge", "gh", "gm", "gr", "gt",
+	"gw", "gy", "hk", "hn", "hr", "hu", "id", "ie", "il", "in", "iq", "is", "it", "jm", "jo", "jp", "ke", "kg", "kh", "kn",
+	"kr", "kw", "ky", "kz", "la", "lb", "lc", "lk", "lr", "lt", "lu", "lv", "ly", "ma", "md", "me", "mg", "mk", "ml", "mn",
+	"mo", "mr", "ms", "mt", "mu", "mv", "mw", "mx", "my", "mz", "na", "ne", "ng", "ni", "nl", "no", "np", "nr", "nz", "om",
+	"pa", "pe", "pg", "ph", "pk", "pl", "pt", "pw", "py", "qa", "ro", "rs", "ru", "rw", "sa", "sb", "sc", "se", "sg", "si",
+	"sk", "sl", "sn", "sr", "st", "sv", "sz", "tc", "td", "th", "tj", "tm", "tn", "to", "tr", "tt", "tw", "tz", "ua", "ug",
+	"us", "uy", "uz", "vc", "ve", "vg", "vn", "vu", "xk", "ye", "za", "zm", "zw",
+}
+
+// StorefrontCache keeps discovered storefronts per app.
+type StorefrontCache interface {
+	GetStorefronts(ctx context.Context, appID string, maxAge time.Duration) ([]string, bool, error)
+	SaveStorefronts(ctx context.Context, appID string, storefronts []string) error
+}
+
+// Discoverer finds the storefronts an app is available in by looking it up in
+// every storefront, and caches the result.
+type Discoverer struct {
+	lookup *LookupClient
+	cache  StorefrontCache
+	cfg    config.AppStoreConfig
+}
+
+func NewDiscoverer(lookup *LookupClient, cache StorefrontCache, cfg config.AppStoreConfig) *Discoverer {
+	return &Discoverer{lookup: lookup, cache: cache, cfg: cfg}
+}
+
+// Discover returns the sorted storefronts appID is available in. Cached
+// results younger than DiscoveryTTL are reused. Storefronts whose lookup
+// fails are included, so a flaky lookup never hides a market.
+func (d *Discoverer) Discover(ctx context.Context, appID string) ([]string, error) {
+	if storefronts, ok, err := d.cache.GetStorefronts(ctx, appID, d.cfg.DiscoveryTTL); err != nil {
+		logger.Warn(ctx, "Failed to read cached storefronts", "error", err.Error())
+	} else if ok {
+		logger.LogEvent(ctx, "appstore.storefronts.discovered", "cached", "storefronts", len(storefronts))
+		return storefronts, nil
+	}
+
+	timer := logger.StartTimer()
+	sem := make(chan struct{}, max(d.cfg.DiscoveryConcurrency, 1))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var available []string
+	failed := 0
+	for _, storefront := range Storefronts {
+		wg.Add(1)
+		go func(storefront string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			info, err := d.lookup.Lookup(ctx, storefront, appID)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed++
+			}
+			if err != nil || info != nil {
+				available = append(available, storefront)
+			}
+		}(storefront)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(available)
+
+	logger.LogEventWithLatency(ctx, "appstore.storefronts.discovered", "success", timer(), "storefronts", len(available), "lookup_failures", failed)
+	if failed == 0 {
+		if err := d.cache.SaveStorefronts(ctx, appID, available); err != nil {
+			logger.Warn(ctx, "Failed to cache discovered storefronts", "error", err.Error())
+		}
+	}
+	return available, nil
+}
@@ -0,0 +1,47 @@
+package appstore
+
+import (
+	"github.com/quiby-ai/common/pkg/httpx"
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/pkg/appstorereviews"
+)
+
+// The review fetcher and token extractor live in pkg/appstorereviews so other
+// tools can use them; these aliases keep the ingestor's packages on the
+// names they have always used.
+type (
+	Review            = appstorereviews.Review
+	ReviewAttributes  = appstorereviews.ReviewAttributes
+	DeveloperResponse = appstorereviews.DeveloperResponse
+	ReviewsResponse   = appstorereviews.ReviewsResponse
+	FetchOptions      = appstorereviews.FetchOptions
+	ResponseCache     = appstorereviews.ResponseCache
+	RateLimiter       = appstorereviews.RateLimiter
+	DriftRecorder     = appstorereviews.DriftRecorder
+	DriftSample       = appstorereviews.DriftSample
+	FailureCapturer   = appstorereviews.FailureCapturer
+	ReviewFetcher     = appstorereviews.Fetcher
+	TokenExtractor    = appstorereviews.TokenExtractor
+)
+
+var (
+	ErrBlocked       = appstorereviews.ErrBlocked
+	ErrTokenNotFound = appstorereviews.ErrTokenNotFound
+)
+
+// NewReviewFetcher returns a fetcher configured from the appstore and http
+// sections of cfg.
+func NewReviewFetcher(http httpx.Client, token string, cfg config.Config) *ReviewFetcher {
+	return appstorereviews.New(http, token, appstorereviews.Options{
+		APIHost:     cfg.AppStore.APIHost,
+		APIPath:     cfg.AppStore.APIPath,
+		Referrer:    cfg.AppStore.Referrer,
+		Locales:     cfg.AppStore.Locales,
+		DateLayouts: cfg.AppStore.DateLayouts,
+		UserAgents:  cfg.HTTP.UserAgents,
+	})
+}
+
+func NewTokenExtractor(http httpx.Client) *TokenExtractor {
+	return appstorereviews.NewTokenExtractor(http)
+}
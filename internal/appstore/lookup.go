@@ -0,0 +1,99 @@
+package appstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/quiby-ai/common/pkg/httpx"
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+// AppInfo is what the iTunes lookup API reports for an app in a storefront.
+type AppInfo struct {
+	TrackID      int64  `json:"trackId"`
+	TrackName    string `json:"trackName"`
+	TrackViewURL string `json:"trackViewUrl"`
+	BundleID     string `json:"bundleId"`
+	// Version is the current version, released at
+	// CurrentVersionReleaseDate (RFC 3339) with ReleaseNotes.
+	Version                   string `json:"version"`
+	CurrentVersionReleaseDate string `json:"currentVersionReleaseDate"`
+	ReleaseNotes              string `json:"releaseNotes"`
+}
+
+// Slug returns the landing page slug from TrackViewURL, e.g. "instagram" for
+// https://apps.apple.com/us/app/instagram/id389801252, or "" when the URL has
+// no app path.
+func (a AppInfo) Slug() string {
+	parsed, err := url.Parse(a.TrackViewURL)
+	if err != nil {
+		return ""
+	}
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] == "app" && !strings.HasPrefix(segments[i+1], "id") {
+			return segments[i+1]
+		}
+	}
+	return ""
+}
+
+type lookupResponse struct {
+	ResultCount int       `json:"resultCount"`
+	Results     []AppInfo `json:"results"`
+}
+
+// LookupClient queries the iTunes lookup API, a cheap way to learn whether an
+// app is available in a storefront.
+type LookupClient struct {
+	http httpx.Client
+	cfg  config.AppStoreConfig
+}
+
+func NewLookupClient(http httpx.Client, cfg config.AppStoreConfig) *LookupClient {
+	return &LookupClient{http: http, cfg: cfg}
+}
+
+// Lookup returns the app's listing in country, or nil when the app is not
+// available there.
+func (l *LookupClient) Lookup(ctx context.Context, country, appID string) (*AppInfo, error) {
+	return l.lookup(ctx, country, map[string]string{"id": appID, "country": strings.ToLower(country), "entity": "software"})
+}
+
+// LookupBundle returns the listing of the app with bundleID in country, or
+// nil when there is none.
+func (l *LookupClient) LookupBundle(ctx context.Context, country, bundleID string) (*AppInfo, error) {
+	return l.lookup(ctx, country, map[string]string{"bundleId": bundleID, "country": strings.ToLower(country), "entity": "software"})
+}
+
+func (l *LookupClient) lookup(ctx context.Context, country string, query map[string]string) (*AppInfo, error) {
+	timer := logger.StartTimer()
+
+	response, err := l.http.DoGET(ctx, l.cfg.LookupURL, query, nil)
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "appstore.lookup.request", "failed", timer(), "country", country, "error", "http_request_failed")
+		return nil, fmt.Errorf("app lookup failed: %w", err)
+	}
+	if response.Status != http.StatusOK {
+		logger.LogEventWithLatency(ctx, "appstore.lookup.request", "failed", timer(), "country", country, "status", response.Status)
+		return nil, fmt.Errorf("app lookup returned status %d", response.Status)
+	}
+
+	var parsed lookupResponse
+	if err := json.Unmarshal(response.Body, &parsed); err != nil {
+		logger.LogEventWithLatency(ctx, "appstore.lookup.request", "failed", timer(), "country", country, "error", "json_parse_failed")
+		return nil, fmt.Errorf("failed to parse app lookup response: %w", err)
+	}
+	if len(parsed.Results) == 0 {
+		logger.LogEventWithLatency(ctx, "appstore.lookup.request", "success", timer(), "country", country, "available", false)
+		return nil, nil
+	}
+
+	logger.LogEventWithLatency(ctx, "appstore.lookup.request", "success", timer(), "country", country, "available", true)
+	return &parsed.Results[0], nil
+}
@@ -0,0 +1,20 @@
+package appstore
+
+import "testing"
+
+func TestAppInfoSlug(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://apps.apple.com/us/app/instagram/id389801252?uo=4", "instagram"},
+		{"https://apps.apple.com/jp/app/%E3%83%A9%E3%82%A4%E3%83%B3/id443904275", "ライン"},
+		{"https://apps.apple.com/us/app/id389801252", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := (AppInfo{TrackViewURL: tt.url}).Slug(); got != tt.want {
+			t.Errorf("Slug(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package appstore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+// Tuner adapts the page delay and the number of storefronts fetched at once
+// to how the App Store responds. Every Window requests it backs off
+// (doubling the delay, halving the concurrency) when the share of rate
+// limited requests exceeds Target429Rate or the mean latency exceeds
+// LatencyTarget, and otherwise speeds up (delay down by a quarter,
+// concurrency up by one), always within the configured bounds.
+type Tuner struct {
+	cfg config.AutoTuneConfig
+
+	mu       sync.Mutex
+	delay    time.Duration
+	limit    int
+	inFlight int
+	released chan struct{}
+
+	requests int
+	limited  int
+	latency  time.Duration
+}
+
+func NewTuner(cfg config.AutoTuneConfig, initialDelay time.Duration) *Tuner {
+	t := &Tuner{cfg: cfg, limit: max(cfg.MinConcurrency, 1), released: make(chan struct{})}
+	t.delay = t.clampDelay(initialDelay)
+	return t
+}
+
+// SetConfig replaces the tuning bounds and targets, pulling the current delay
+// and concurrency into the new bounds.
+func (t *Tuner) SetConfig(cfg config.AutoTuneConfig) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cfg = cfg
+	t.delay = t.clampDelay(t.delay)
+	t.limit = min(max(t.limit, cfg.MinConcurrency, 1), max(cfg.MaxConcurrency, cfg.MinConcurrency, 1))
+	t.wake()
+}
+
+// Delay is the current delay between pages.
+func (t *Tuner) Delay() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.delay
+}
+
+// Acquire waits until fewer storefronts than the current concurrency are
+// being fetched. Every successful Acquire must be paired with Release.
+func (t *Tuner) Acquire(ctx context.Context) error {
+	for {
+		t.mu.Lock()
+		if t.inFlight < t.limit {
+			t.inFlight++
+			t.mu.Unlock()
+			return nil
+		}
+		released := t.released
+		t.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-released:
+		}
+	}
+}
+
+func (t *Tuner) Release() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.inFlight--
+	t.wake()
+}
+
+// wake lets waiting Acquire calls recheck the limit. The caller holds mu.
+func (t *Tuner) wake() {
+	close(t.released)
+	t.released = make(chan struct{})
+}
+
+// Observe records one request and adjusts the settings once a window of
+// requests has been seen.
+func (t *Tuner) Observe(ctx context.Context, latency time.Duration, rateLimited bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.requests++
+	t.latency += latency
+	if rateLimited {
+		t.limited++
+	}
+	if t.requests < max(t.cfg.Window, 1) {
+		return
+	}
+
+	limitedShare := float64(t.limited) / float64(t.requests)
+	meanLatency := t.latency / time.Duration(t.requests)
+	t.requests, t.limited, t.latency = 0, 0, 0
+
+	outcome := "faster"
+	if limitedShare > t.cfg.Target429Rate || (t.cfg.LatencyTarget > 0 && meanLatency > t.cfg.LatencyTarget) {
+		outcome = "slower"
+		t.delay = t.clampDelay(max(t.delay*2, t.cfg.MinDelay, 100*time.Millisecond))
+		t.limit = max(t.limit/2, t.cfg.MinConcurrency, 1)
+	} else {
+		t.delay = t.clampDelay(t.delay - t.delay/4)
+		t.limit = min(t.limit+1, max(t.cfg.MaxConcurrency, t.cfg.MinConcurrency, 1))
+		t.wake()
+	}
+
+	logger.LogEvent(ctx, "appstore.autotune.adjusted", outcome, "delay_ms", t.delay.Milliseconds(), "concurrency", t.limit,
+		"rate_limited_share", limitedShare, "mean_latency_ms", meanLatency.Milliseconds())
+}
+
+func (t *Tuner) clampDelay(delay time.Duration) time.Duration {
+	delay = max(delay, t.cfg.MinDelay)
+	if t.cfg.MaxDelay > 0 {
+		delay = min(delay, t.cfg.MaxDelay)
+	}
+	return delay
+}
@@ -0,0 +1,56 @@
+package appstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/config"
+)
+
+func TestTunerBacksOffAndRecovers(t *testing.T) {
+	ctx := context.Background()
+	tuner := NewTuner(config.AutoTuneConfig{
+		MinDelay:       200 * time.Millisecond,
+		MaxDelay:       4 * time.Second,
+		MinConcurrency: 1,
+		MaxConcurrency: 4,
+		Target429Rate:  0.1,
+		Window:         4,
+	}, 500*time.Millisecond)
+
+	for range 3 {
+		for range 4 {
+			tuner.Observe(ctx, 100*time.Millisecond, false)
+		}
+	}
+	if got := tuner.limit; got != 4 {
+		t.Errorf("concurrency after healthy windows = %d, want 4", got)
+	}
+	if got := tuner.Delay(); got != 210937500*time.Nanosecond {
+		t.Errorf("delay after healthy windows = %v, want 210.9375ms", got)
+	}
+
+	tuner.Observe(ctx, 100*time.Millisecond, true)
+	for range 3 {
+		tuner.Observe(ctx, 100*time.Millisecond, false)
+	}
+	if got := tuner.limit; got != 2 {
+		t.Errorf("concurrency after rate limits = %d, want 2", got)
+	}
+	if got := tuner.Delay(); got != 421875*time.Microsecond {
+		t.Errorf("delay after rate limits = %v, want 421.875ms", got)
+	}
+
+	for range 5 {
+		for range 4 {
+			tuner.Observe(ctx, 100*time.Millisecond, true)
+		}
+	}
+	if got := tuner.Delay(); got != 4*time.Second {
+		t.Errorf("delay = %v, want capped at 4s", got)
+	}
+	if got := tuner.limit; got != 1 {
+		t.Errorf("concurrency = %d, want floored at 1", got)
+	}
+}
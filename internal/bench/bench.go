@@ -0,0 +1,284 @@
+// Package bench measures ingestion capacity by running synthetic sagas
+// against an in-process mock App Store and reporting throughput, memory use
+// and database write rates.
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quiby-ai/common/pkg/events"
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/mockstore"
+	"github.com/quiby-ai/review-ingestor/internal/producer"
+	"github.com/quiby-ai/review-ingestor/internal/service"
+)
+
+// memorySampleInterval is how often the heap is sampled for its peak.
+const memorySampleInterval = 100 * time.Millisecond
+
+// Ingester runs a saga without publishing its completion.
+type Ingester interface {
+	Ingest(ctx context.Context, req service.Request, sagaID string) ([]producer.CountryStats, error)
+}
+
+// WriteCounter returns a monotonic count of rows written to the database.
+type WriteCounter func(ctx context.Context) (int64, error)
+
+// Options sets the scale of a run: Apps synthetic apps, each fetched in
+// Countries with Reviews reviews per storefront, Concurrency sagas at a
+// time. App IDs are numbered from AppIDBase, so a new base stores new
+// reviews and a repeated one measures the duplicate path.
+type Options struct {
+	Apps        int
+	Countries   []string
+	Reviews     int
+	Concurrency int
+	AppIDBase   int64
+}
+
+// Report is the outcome of a run.
+type Report struct {
+	Sagas       int
+	FailedSagas int
+	Duration    time.Duration
+	// StoreRequests counts the requests the mock store answered: landing
+	// pages, lookups and review pages.
+	StoreRequests int64
+	Fetched       int
+	New           int
+	Duplicates    int
+	Failed        int
+	// RowsWritten is the database's own count of inserted, updated and
+	// deleted rows during the run, including rows other than reviews.
+	RowsWritten int64
+	// AllocatedBytes is the total heap allocated during the run and
+	// PeakHeapBytes the largest heap in use that was sampled.
+	AllocatedBytes uint64
+	PeakHeapBytes  uint64
+	GCCycles       uint32
+}
+
+// ReviewsPerSecond is the fetch throughput.
+func (r Report) ReviewsPerSecond() float64 {
+	return perSecond(float64(r.Fetched), r.Duration)
+}
+
+// RowsPerSecond is the database write rate.
+func (r Report) RowsPerSecond() float64 {
+	return perSecond(float64(r.RowsWritten), r.Duration)
+}
+
+func perSecond(n float64, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return n / d.Seconds()
+}
+
+type Runner struct {
+	ingester Ingester
+	writes   WriteCounter
+	mockCfg  config.MockStoreConfig
+	appStore config.AppStoreConfig
+}
+
+func NewRunner(ingester Ingester, writes WriteCounter, mockCfg config.MockStoreConfig, appStore config.AppStoreConfig) *Runner {
+	return &Runner{ingester: ingester, writes: writes, mockCfg: mockCfg, appStore: appStore}
+}
+
+// Run serves the mock store on mockstore.addr and ingests the synthetic
+// apps of opts through it. The App Store settings must point at that
+// address, so a benchmark never reaches Apple.
+func (r *Runner) Run(ctx context.Context, opts Options) (Report, error) {
+	if opts.Apps <= 0 || len(opts.Countries) == 0 || opts.Reviews <= 0 || opts.Concurrency <= 0 {
+		return Report{}, fmt.Errorf("apps, countries, reviews and concurrency must be positive")
+	}
+	if err := r.checkTarget(); err != nil {
+		return Report{}, err
+	}
+
+	mockCfg := r.mockCfg
+	mockCfg.ReviewsPerStorefront = opts.Reviews
+	var requests atomic.Int64
+	handler := mockstore.NewServer(mockCfg).Handler()
+	srv := &http.Server{
+		Addr: mockCfg.Addr,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			requests.Add(1)
+			handler.ServeHTTP(w, req)
+		}),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	listener, err := net.Listen("tcp", mockCfg.Addr)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to serve mock store on %s: %w", mockCfg.Addr, err)
+	}
+	go srv.Serve(listener)
+	defer srv.Close()
+
+	writesBefore, err := r.writes(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+	var memBefore runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&memBefore)
+	peakHeap, stopSampling := sampleHeap()
+
+	logger.LogEvent(ctx, "bench.run.started", "in_progress", "apps", opts.Apps, "countries", len(opts.Countries),
+		"reviews", opts.Reviews, "concurrency", opts.Concurrency)
+	start := time.Now()
+	report := r.ingest(ctx, opts)
+	report.Duration = time.Since(start)
+
+	stopSampling()
+	var memAfter runtime.MemStats
+	runtime.ReadMemStats(&memAfter)
+	report.AllocatedBytes = memAfter.TotalAlloc - memBefore.TotalAlloc
+	report.PeakHeapBytes = peakHeap.Load()
+	report.GCCycles = memAfter.NumGC - memBefore.NumGC
+	report.StoreRequests = requests.Load()
+
+	writesAfter, err := r.writes(ctx)
+	if err != nil {
+		return report, err
+	}
+	report.RowsWritten = writesAfter - writesBefore
+
+	logger.LogEventWithLatency(ctx, "bench.run.completed", "success", report.Duration, "sagas", report.Sagas,
+		"failed_sagas", report.FailedSagas, "fetched", report.Fetched, "rows_written", report.RowsWritten)
+	return report, nil
+}
+
+// ingest runs the sagas of opts on opts.Concurrency workers.
+func (r *Runner) ingest(ctx context.Context, opts Options) Report {
+	appIDs := make(chan string)
+	go func() {
+		defer close(appIDs)
+		for i := range opts.Apps {
+			select {
+			case appIDs <- strconv.FormatInt(opts.AppIDBase+int64(i), 10):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var mu sync.Mutex
+	var report Report
+	var wg sync.WaitGroup
+	for range opts.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for appID := range appIDs {
+				stats, err := r.ingestApp(ctx, appID, opts.Countries)
+
+				mu.Lock()
+				report.Sagas++
+				if err != nil {
+					report.FailedSagas++
+				}
+				for _, country := range stats {
+					report.Fetched += country.Fetched
+					report.New += country.New
+					report.Duplicates += country.Duplicates
+					report.Failed += country.Failed
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+func (r *Runner) ingestApp(ctx context.Context, appID string, countries []string) ([]producer.CountryStats, error) {
+	sagaID := "bench-" + uuid.NewString()
+	ctx = logger.WithAppID(logger.WithSagaID(ctx, sagaID), appID)
+
+	req := service.NewRequest(events.ExtractRequest{
+		AppID:     appID,
+		AppName:   "Bench App " + appID,
+		Countries: countries,
+		DateFrom:  "2000-01-01",
+	}, service.StoreAppStore)
+	stats, err := r.ingester.Ingest(ctx, req, sagaID)
+	if err != nil {
+		logger.Error(ctx, "Benchmark saga failed", err)
+	}
+	return stats, err
+}
+
+// checkTarget reports an error unless the review, landing page and lookup
+// URLs all point at the mock store's port on this host.
+func (r *Runner) checkTarget() error {
+	_, port, err := net.SplitHostPort(r.mockCfg.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid mockstore.addr %q: %w", r.mockCfg.Addr, err)
+	}
+
+	targets := map[string]string{
+		"appstore.api_host":     r.appStore.APIHost,
+		"appstore.landing_host": r.appStore.LandingHost,
+		"appstore.lookup_url":   r.appStore.LookupURL,
+	}
+	var errs []error
+	for key, target := range targets {
+		u, err := url.Parse(target)
+		if err != nil || u.Port() != port || !isLocalHost(u.Hostname()) {
+			errs = append(errs, fmt.Errorf("%s %q does not point at the mock store on port %s", key, target, port))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func isLocalHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// sampleHeap records the largest heap in use until the returned stop
+// function is called.
+func sampleHeap() (*atomic.Uint64, func()) {
+	var peak atomic.Uint64
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(memorySampleInterval)
+		defer ticker.Stop()
+		var mem runtime.MemStats
+		for {
+			runtime.ReadMemStats(&mem)
+			if mem.HeapInuse > peak.Load() {
+				peak.Store(mem.HeapInuse)
+			}
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return &peak, func() {
+		close(done)
+		wg.Wait()
+	}
+}
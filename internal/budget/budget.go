@@ -0,0 +1,103 @@
+// Package budget counts what a saga costs the stores it fetches from: the
+// requests sent, how many were rate limited, the time spent backing off and
+// the bytes received.
+package budget
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Budget accumulates request usage. It is safe for concurrent use, and its
+// methods do nothing on a nil Budget, so code without one attached to its
+// context needs no checks.
+type Budget struct {
+	requests    atomic.Int64
+	rateLimited atomic.Int64
+	backoff     atomic.Int64
+	bytes       atomic.Int64
+}
+
+type budgetKey struct{}
+
+// With attaches b to ctx, so requests made with the returned context are
+// counted in it.
+func With(ctx context.Context, b *Budget) context.Context {
+	return context.WithValue(ctx, budgetKey{}, b)
+}
+
+// From returns the budget attached to ctx, or nil.
+func From(ctx context.Context) *Budget {
+	b, _ := ctx.Value(budgetKey{}).(*Budget)
+	return b
+}
+
+// Request counts a request that got status, or 0 when it failed without a
+// response. 429 responses count as rate limited.
+func (b *Budget) Request(status int) {
+	if b == nil {
+		return
+	}
+	b.requests.Add(1)
+	if status == http.StatusTooManyRequests {
+		b.rateLimited.Add(1)
+	}
+}
+
+// RateLimited counts a response that was not a 429 but is a rate limit all
+// the same, such as a block page.
+func (b *Budget) RateLimited() {
+	if b == nil {
+		return
+	}
+	b.rateLimited.Add(1)
+}
+
+// Received adds n response body bytes.
+func (b *Budget) Received(n int) {
+	if b == nil {
+		return
+	}
+	b.bytes.Add(int64(n))
+}
+
+// Backoff adds d to the time spent waiting on retries and rate limits.
+func (b *Budget) Backoff(d time.Duration) {
+	if b == nil || d <= 0 {
+		return
+	}
+	b.backoff.Add(int64(d))
+}
+
+// Usage is a snapshot of a Budget.
+type Usage struct {
+	Requests       int64   `json:"requests"`
+	RateLimited    int64   `json:"rate_limited"`
+	BackoffSeconds float64 `json:"backoff_seconds"`
+	Bytes          int64   `json:"bytes"`
+}
+
+// Usage returns what b has counted so far.
+func (b *Budget) Usage() Usage {
+	if b == nil {
+		return Usage{}
+	}
+	return Usage{
+		Requests:       b.requests.Load(),
+		RateLimited:    b.rateLimited.Load(),
+		BackoffSeconds: time.Duration(b.backoff.Load()).Seconds(),
+		Bytes:          b.bytes.Load(),
+	}
+}
+
+// Add returns the sum of u and other.
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		Requests:       u.Requests + other.Requests,
+		RateLimited:    u.RateLimited + other.RateLimited,
+		BackoffSeconds: u.BackoffSeconds + other.BackoffSeconds,
+		Bytes:          u.Bytes + other.Bytes,
+	}
+}
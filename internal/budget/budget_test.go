@@ -0,0 +1,31 @@
+package budget
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBudget(t *testing.T) {
+	b := &Budget{}
+	ctx := With(context.Background(), b)
+
+	From(ctx).Request(200)
+	From(ctx).Request(429)
+	From(ctx).Request(0)
+	From(ctx).RateLimited()
+	From(ctx).Backoff(1500 * time.Millisecond)
+	From(ctx).Backoff(-time.Second)
+	From(ctx).Received(2048)
+
+	want := Usage{Requests: 3, RateLimited: 2, BackoffSeconds: 1.5, Bytes: 2048}
+	if got := b.Usage(); got != want {
+		t.Errorf("Usage() = %+v, want %+v", got, want)
+	}
+
+	// Contexts without a budget are not counted and do not panic.
+	From(context.Background()).Request(429)
+	if got := From(context.Background()).Usage(); got != (Usage{}) {
+		t.Errorf("Usage() of nil budget = %+v, want zero", got)
+	}
+}
@@ -0,0 +1,164 @@
+// Package cassette records HTTP interactions to a fixture file and replays
+// them later, so pagination and rate-limit edge cases seen against the live
+// App Store can be reproduced offline.
+package cassette
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/quiby-ai/common/pkg/httpx"
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+const (
+	ModeOff    = config.CassetteModeOff
+	ModeRecord = config.CassetteModeRecord
+	ModeReplay = config.CassetteModeReplay
+)
+
+// ErrNotRecorded is returned in replay mode for requests the cassette has no
+// (more) interactions for.
+var ErrNotRecorded = errors.New("request not recorded in cassette")
+
+// Interaction is one recorded request and its response. Request headers are
+// not kept, so credentials never end up in fixtures.
+type Interaction struct {
+	URL    string `json:"url"`
+	Status int    `json:"status"`
+	Body   string `json:"body"`
+}
+
+type file struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Client wraps an httpx.Client. In record mode every response is appended to
+// the cassette file; in replay mode responses come from the file in recorded
+// order per URL and next is never called.
+type Client struct {
+	next   httpx.Client
+	mode   string
+	path   string
+	mu     sync.Mutex
+	tape   file
+	played map[string]int
+}
+
+var _ httpx.Client = (*Client)(nil)
+
+// New opens the cassette at path. Replay requires the file to exist; record
+// starts a new cassette, replacing an existing one.
+func New(next httpx.Client, mode, path string) (*Client, error) {
+	c := &Client{next: next, mode: mode, path: path, played: make(map[string]int)}
+	switch mode {
+	case ModeRecord:
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create cassette directory: %w", err)
+		}
+		if err := c.save(); err != nil {
+			return nil, err
+		}
+	case ModeReplay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &c.tape); err != nil {
+			return nil, fmt.Errorf("failed to decode cassette %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown cassette mode %q", mode)
+	}
+	return c, nil
+}
+
+func (c *Client) DoGET(ctx context.Context, rawURL string, query map[string]string, headers map[string]string) (httpx.Response, error) {
+	return c.Do(ctx, httpx.Request{Method: http.MethodGet, URL: rawURL, Params: query, Headers: headers})
+}
+
+// Do records or replays req. Requests other than GET are keyed by their
+// method as well; their bodies are neither recorded nor compared.
+func (c *Client) Do(ctx context.Context, req httpx.Request) (httpx.Response, error) {
+	key := requestKey(req.URL, req.Params)
+	if req.Method != "" && req.Method != http.MethodGet {
+		key = req.Method + " " + key
+	}
+	if c.mode == ModeReplay {
+		return c.replay(ctx, key)
+	}
+
+	resp, err := c.next.Do(ctx, req)
+	if err != nil {
+		return httpx.Response{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tape.Interactions = append(c.tape.Interactions, Interaction{URL: key, Status: resp.Status, Body: string(resp.Body)})
+	if err := c.save(); err != nil {
+		logger.Warn(ctx, "Failed to write cassette", "path", c.path, "error", err.Error())
+	}
+	return resp, nil
+}
+
+// replay returns the next unplayed interaction recorded for key.
+func (c *Client) replay(ctx context.Context, key string) (httpx.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seen := 0
+	for _, interaction := range c.tape.Interactions {
+		if interaction.URL != key {
+			continue
+		}
+		if seen == c.played[key] {
+			c.played[key]++
+			logger.Debug(ctx, "Replaying cassette interaction", "url", key, "status", interaction.Status)
+			return httpx.Response{Status: interaction.Status, Body: []byte(interaction.Body)}, nil
+		}
+		seen++
+	}
+	logger.LogEvent(ctx, "cassette.replay.missed", "failed", "url", key, "played", c.played[key])
+	return httpx.Response{}, fmt.Errorf("%w: %s", ErrNotRecorded, key)
+}
+
+func (c *Client) save() error {
+	data, err := json.MarshalIndent(c.tape, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// requestKey is rawURL with query merged into its query string in sorted
+// order, so the same request always maps to the same key.
+func requestKey(rawURL string, query map[string]string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	values := parsed.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		values.Set(k, query[k])
+	}
+	parsed.RawQuery = values.Encode()
+	return parsed.String()
+}
@@ -0,0 +1,57 @@
+package cassette
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/quiby-ai/common/pkg/httpx"
+)
+
+type scripted struct {
+	responses []httpx.Response
+}
+
+func (s *scripted) Do(context.Context, httpx.Request) (httpx.Response, error) {
+	resp := s.responses[0]
+	s.responses = s.responses[1:]
+	return resp, nil
+}
+
+func (s *scripted) DoGET(ctx context.Context, rawURL string, query, headers map[string]string) (httpx.Response, error) {
+	return s.Do(ctx, httpx.Request{URL: rawURL, Params: query, Headers: headers})
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "reviews.json")
+	live := &scripted{responses: []httpx.Response{{Status: 429}, {Status: 200, Body: []byte(`{"data":[]}`)}}}
+
+	recorder, err := New(live, ModeRecord, path)
+	if err != nil {
+		t.Fatalf("New(record) error = %v", err)
+	}
+	for range 2 {
+		if _, err := recorder.DoGET(ctx, "https://example.com/reviews?offset=0", map[string]string{"limit": "20"}, nil); err != nil {
+			t.Fatalf("record DoGET error = %v", err)
+		}
+	}
+
+	player, err := New(nil, ModeReplay, path)
+	if err != nil {
+		t.Fatalf("New(replay) error = %v", err)
+	}
+	for _, want := range []int{429, 200} {
+		resp, err := player.DoGET(ctx, "https://example.com/reviews?limit=20&offset=0", nil, nil)
+		if err != nil {
+			t.Fatalf("replay DoGET error = %v", err)
+		}
+		if resp.Status != want {
+			t.Errorf("replayed status = %d, want %d", resp.Status, want)
+		}
+	}
+	if _, err := player.DoGET(ctx, "https://example.com/reviews?offset=0&limit=20", nil, nil); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("third replay error = %v, want ErrNotRecorded", err)
+	}
+}
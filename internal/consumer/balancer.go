@@ -0,0 +1,126 @@
+package consumer
+
+import (
+	"cmp"
+	"hash/fnv"
+	"os"
+	"sort"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/segmentio/kafka-go"
+)
+
+// groupBalancers lists the assignment strategies the reader offers the
+// group, preferred first. Range comes last so members offering only range,
+// such as instances still on the previous release during a rolling
+// deployment, can still agree on one.
+func groupBalancers(cfg config.KafkaConfig) []kafka.GroupBalancer {
+	switch cfg.GroupBalancer {
+	case config.GroupBalancerRoundRobin:
+		return []kafka.GroupBalancer{kafka.RoundRobinGroupBalancer{}, kafka.RangeGroupBalancer{}}
+	case config.GroupBalancerSticky:
+		hostname, _ := os.Hostname()
+		return []kafka.GroupBalancer{stickyBalancer{instanceID: cmp.Or(cfg.InstanceID, hostname)}, kafka.RangeGroupBalancer{}}
+	default:
+		return []kafka.GroupBalancer{kafka.RangeGroupBalancer{}}
+	}
+}
+
+// stickyBalancer assigns partitions by rendezvous hashing of the members'
+// instance IDs, which they send as user data. Unlike member IDs, which the
+// coordinator hands out anew on every join and range assignment orders
+// members by, instance IDs survive restarts: an instance restarted during a
+// rolling deployment gets the same partitions back, and the others keep
+// theirs, so sagas running on them are not handed to another instance.
+// Instances joining or leaving move few partitions beyond their own share.
+type stickyBalancer struct {
+	instanceID string
+}
+
+func (b stickyBalancer) ProtocolName() string {
+	return "ingestor-sticky"
+}
+
+func (b stickyBalancer) UserData() ([]byte, error) {
+	return []byte(b.instanceID), nil
+}
+
+// AssignGroups gives every member of a topic at most its even share of the
+// topic's partitions, rounded up, preferring for each partition the members
+// with the highest hash of instance ID and partition.
+func (b stickyBalancer) AssignGroups(members []kafka.GroupMember, partitions []kafka.Partition) kafka.GroupMemberAssignments {
+	assignments := make(kafka.GroupMemberAssignments, len(members))
+	for _, member := range members {
+		assignments[member.ID] = make(map[string][]int)
+	}
+
+	byTopic := make(map[string][]int)
+	for _, partition := range partitions {
+		byTopic[partition.Topic] = append(byTopic[partition.Topic], partition.ID)
+	}
+
+	for topic, ids := range byTopic {
+		var subscribed []kafka.GroupMember
+		for _, member := range members {
+			for _, t := range member.Topics {
+				if t == topic {
+					subscribed = append(subscribed, member)
+					break
+				}
+			}
+		}
+		if len(subscribed) == 0 {
+			continue
+		}
+
+		// Pairs are taken best first, so a partition goes to the member
+		// ranking it highest among those with room.
+		type pair struct {
+			member kafka.GroupMember
+			id     int
+			score  uint64
+		}
+		pairs := make([]pair, 0, len(ids)*len(subscribed))
+		for _, id := range ids {
+			for _, member := range subscribed {
+				pairs = append(pairs, pair{member: member, id: id, score: rendezvousScore(member, topic, id)})
+			}
+		}
+		sort.Slice(pairs, func(i, j int) bool {
+			if pairs[i].score != pairs[j].score {
+				return pairs[i].score > pairs[j].score
+			}
+			return pairs[i].member.ID < pairs[j].member.ID
+		})
+
+		capacity := (len(ids) + len(subscribed) - 1) / len(subscribed)
+		assigned := make(map[int]bool, len(ids))
+		for _, p := range pairs {
+			owned := assignments[p.member.ID][topic]
+			if assigned[p.id] || len(owned) >= capacity {
+				continue
+			}
+			assignments[p.member.ID][topic] = append(owned, p.id)
+			assigned[p.id] = true
+		}
+		for _, owned := range assignments {
+			sort.Ints(owned[topic])
+		}
+	}
+	return assignments
+}
+
+// rendezvousScore ranks member for a partition. Members without user data,
+// which should not happen, are ranked by member ID.
+func rendezvousScore(member kafka.GroupMember, topic string, partition int) uint64 {
+	instanceID := string(member.UserData)
+	if instanceID == "" {
+		instanceID = member.ID
+	}
+	h := fnv.New64a()
+	h.Write([]byte(instanceID))
+	h.Write([]byte{0})
+	h.Write([]byte(topic))
+	h.Write([]byte{0, byte(partition >> 24), byte(partition >> 16), byte(partition >> 8), byte(partition)})
+	return h.Sum64()
+}
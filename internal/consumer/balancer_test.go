@@ -0,0 +1,77 @@
+package consumer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func stickyMembers(instances ...string) []kafka.GroupMember {
+	members := make([]kafka.GroupMember, len(instances))
+	for i, instance := range instances {
+		// Member IDs change on every join; instance IDs do not.
+		members[i] = kafka.GroupMember{ID: fmt.Sprintf("member-%d-%s", len(instances), instance), Topics: []string{"requests"}, UserData: []byte(instance)}
+	}
+	return members
+}
+
+// owners maps each partition to the instance it was assigned to.
+func owners(t *testing.T, members []kafka.GroupMember, assignments kafka.GroupMemberAssignments) map[int]string {
+	t.Helper()
+	owned := make(map[int]string)
+	for _, member := range members {
+		for _, id := range assignments[member.ID]["requests"] {
+			if _, ok := owned[id]; ok {
+				t.Fatalf("partition %d assigned twice", id)
+			}
+			owned[id] = string(member.UserData)
+		}
+	}
+	return owned
+}
+
+func TestStickyBalancerKeepsPartitionsAcrossRebalances(t *testing.T) {
+	var partitions []kafka.Partition
+	for id := 0; id < 12; id++ {
+		partitions = append(partitions, kafka.Partition{Topic: "requests", ID: id})
+	}
+	balancer := stickyBalancer{}
+
+	three := stickyMembers("pod-a", "pod-b", "pod-c")
+	before := owners(t, three, balancer.AssignGroups(three, partitions))
+	if len(before) != 12 {
+		t.Fatalf("assigned %d partitions, want 12", len(before))
+	}
+	counts := make(map[string]int)
+	for _, instance := range before {
+		counts[instance]++
+	}
+	for instance, n := range counts {
+		if n != 4 {
+			t.Errorf("%s got %d partitions, want 4", instance, n)
+		}
+	}
+
+	// pod-c restarts under a new member ID: nothing moves.
+	restarted := stickyMembers("pod-c", "pod-a", "pod-b")
+	after := owners(t, restarted, balancer.AssignGroups(restarted, partitions))
+	for id, instance := range before {
+		if after[id] != instance {
+			t.Errorf("partition %d moved from %s to %s", id, instance, after[id])
+		}
+	}
+
+	// pod-d joins: besides its own share, few partitions move.
+	four := stickyMembers("pod-a", "pod-b", "pod-c", "pod-d")
+	joined := owners(t, four, balancer.AssignGroups(four, partitions))
+	moved := 0
+	for id, instance := range joined {
+		if instance != before[id] {
+			moved++
+		}
+	}
+	if moved > len(partitions)/2 {
+		t.Errorf("%d of %d partitions moved", moved, len(partitions))
+	}
+}
@@ -0,0 +1,50 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// deadLetterWriter forwards messages that cannot be processed to the
+// dead-letter topic unchanged, with the reason in headers.
+type deadLetterWriter struct {
+	writer *kafka.Writer
+}
+
+func newDeadLetterWriter(brokers []string, topic string) *deadLetterWriter {
+	return &deadLetterWriter{writer: &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+	}}
+}
+
+// Send publishes msg with its source position, the rejection reason and,
+// when set, the validation problems.
+func (d *deadLetterWriter) Send(ctx context.Context, msg kafka.Message, reason string, problems []string) error {
+	headers := append([]kafka.Header{}, msg.Headers...)
+	headers = append(headers,
+		kafka.Header{Key: "dlq-source-topic", Value: []byte(msg.Topic)},
+		kafka.Header{Key: "dlq-source-partition", Value: []byte(strconv.Itoa(msg.Partition))},
+		kafka.Header{Key: "dlq-source-offset", Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		kafka.Header{Key: "dlq-reason", Value: []byte(reason)},
+	)
+	if len(problems) > 0 {
+		headers = append(headers, kafka.Header{Key: "dlq-errors", Value: []byte(strings.Join(problems, "; "))})
+	}
+
+	err := d.writer.WriteMessages(ctx, kafka.Message{Key: msg.Key, Value: msg.Value, Headers: headers})
+	if err != nil {
+		return fmt.Errorf("failed to dead-letter message from %s: %w", msg.Topic, err)
+	}
+	return nil
+}
+
+func (d *deadLetterWriter) Close() error {
+	return d.writer.Close()
+}
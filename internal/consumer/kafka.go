@@ -1,12 +1,16 @@
 package consumer
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"sync"
 
-	"github.com/quiby-ai/common/pkg/events"
 	"github.com/quiby-ai/review-ingestor/config"
 	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/producer"
 	"github.com/quiby-ai/review-ingestor/internal/service"
 )
 
@@ -19,11 +23,11 @@ func (p *IngestServiceProcessor) Handle(ctx context.Context, payload any, sagaID
 
 	logger.Debug(ctx, "Kafka message received", "saga_id", sagaID)
 
-	if evt, ok := payload.(events.ExtractRequest); ok {
-		ctx = logger.WithAppID(ctx, evt.AppID)
-		logger.LogEvent(ctx, "kafka.message.decoded", "success", "app_id", evt.AppID)
+	if req, ok := payload.(service.Request); ok {
+		ctx = logger.WithAppID(ctx, req.AppID)
+		logger.LogEvent(ctx, "kafka.message.decoded", "success", "app_id", req.AppID)
 
-		err := p.svc.Handle(ctx, evt, sagaID)
+		err := p.svc.Handle(ctx, req, sagaID)
 		if err != nil {
 			logger.LogEvent(ctx, "kafka.message.processed", "failed")
 			return err
@@ -37,21 +41,184 @@ func (p *IngestServiceProcessor) Handle(ctx context.Context, payload any, sagaID
 	return fmt.Errorf("invalid payload type for preprocess service")
 }
 
+// CancelProcessor cancels the saga named by the message's saga ID when it is
+// running on this instance. The payload is ignored.
+type CancelProcessor struct {
+	svc *service.IngestService
+}
+
+func (p *CancelProcessor) Handle(ctx context.Context, _ any, sagaID string) error {
+	ctx = logger.WithSagaID(ctx, sagaID)
+
+	err := p.svc.CancelRun(sagaID)
+	if errors.Is(err, service.ErrRunNotFound) || errors.Is(err, service.ErrRunNotActive) {
+		logger.Debug(ctx, "Ignoring cancel request for saga not running here", "reason", err.Error())
+		return nil
+	}
+	if err != nil {
+		logger.LogEvent(ctx, "kafka.cancel.processed", "failed")
+		return err
+	}
+
+	logger.LogEvent(ctx, "kafka.cancel.processed", "success")
+	return nil
+}
+
+// RollbackRequest is the payload of a rollback message; the saga to roll
+// back is the envelope's saga ID.
+type RollbackRequest struct {
+	// Purge deletes the saga's reviews instead of flagging them.
+	Purge bool `json:"purge"`
+}
+
+// RollbackProcessor undoes what the message's saga stored, for saga
+// compensation. Rollback topics are consumed by the shared group, so each
+// request is applied once.
+type RollbackProcessor struct {
+	svc *service.IngestService
+}
+
+func (p *RollbackProcessor) Handle(ctx context.Context, payload any, sagaID string) error {
+	ctx = logger.WithSagaID(ctx, sagaID)
+
+	req, ok := payload.(RollbackRequest)
+	if !ok {
+		logger.LogEvent(ctx, "kafka.message.decoded", "failed", "reason", "invalid_payload_type")
+		return fmt.Errorf("invalid payload type for rollback")
+	}
+	if _, err := p.svc.RollbackRun(ctx, sagaID, req.Purge); err != nil {
+		logger.LogEvent(ctx, "kafka.rollback.processed", "failed")
+		return err
+	}
+	logger.LogEvent(ctx, "kafka.rollback.processed", "success")
+	return nil
+}
+
+// ResumeProcessor continues sagas suspended during an outage. The resume
+// topic is consumed by the shared group, so each saga resumes on one instance.
+type ResumeProcessor struct {
+	svc *service.IngestService
+}
+
+func (p *ResumeProcessor) Handle(ctx context.Context, payload any, sagaID string) error {
+	ctx = logger.WithSagaID(ctx, sagaID)
+
+	event, ok := payload.(producer.ExtractResume)
+	if !ok {
+		logger.LogEvent(ctx, "kafka.message.decoded", "failed", "reason", "invalid_payload_type")
+		return fmt.Errorf("invalid payload type for resume")
+	}
+
+	if err := p.svc.Resume(ctx, event, sagaID); err != nil {
+		logger.LogEvent(ctx, "kafka.message.processed", "failed")
+		return err
+	}
+	logger.LogEvent(ctx, "kafka.message.processed", "success")
+	return nil
+}
+
+// KafkaConsumer runs a pool of group members per topic. The priority topic gets
+// its own workers so urgent requests never wait behind bulk backfills.
 type KafkaConsumer struct {
-	consumer *events.KafkaConsumer
+	consumers   []*groupReader
+	deadLetters *deadLetterWriter
 }
 
-func NewKafkaConsumer(cfg config.KafkaConfig, svc *service.IngestService) *KafkaConsumer {
-	consumer := events.NewKafkaConsumer(cfg.Brokers, events.PipelineExtractRequest, cfg.GroupID)
-	processor := &IngestServiceProcessor{svc: svc}
-	consumer.SetProcessor(processor)
-	return &KafkaConsumer{consumer: consumer}
+// NewKafkaConsumer subscribes to the request topics, the resume and cancel
+// topics when set, and kafka.subscriptions, handing each topic's messages to
+// its handler in router.
+func NewKafkaConsumer(cfg config.KafkaConfig, router *Router) (*KafkaConsumer, error) {
+	kc := &KafkaConsumer{}
+	if cfg.DLQTopic != "" {
+		kc.deadLetters = newDeadLetterWriter(cfg.Brokers, cfg.DLQTopic)
+	}
+
+	subscriptions := []config.Subscription{{Topic: cfg.RequestTopic, Handler: HandlerExtract, Concurrency: cfg.Concurrency}}
+	if cfg.PriorityTopic != "" {
+		subscriptions = append(subscriptions, config.Subscription{Topic: cfg.PriorityTopic, Handler: HandlerExtract, Concurrency: cfg.PriorityConcurrency})
+	}
+	if cfg.ResumeTopic != "" {
+		subscriptions = append(subscriptions, config.Subscription{Topic: cfg.ResumeTopic, Handler: HandlerResume})
+	}
+	if cfg.CancelTopic != "" {
+		subscriptions = append(subscriptions, config.Subscription{Topic: cfg.CancelTopic, Handler: HandlerCancel, Broadcast: true})
+	}
+	subscriptions = append(subscriptions, cfg.Subscriptions...)
+
+	for _, sub := range subscriptions {
+		if err := kc.subscribe(cfg, sub, router); err != nil {
+			kc.Close()
+			return nil, err
+		}
+	}
+	return kc, nil
 }
 
+// subscribe adds the group members consuming one topic.
+func (kc *KafkaConsumer) subscribe(cfg config.KafkaConfig, sub config.Subscription, router *Router) error {
+	rt, err := router.route(sub.Handler)
+	if err != nil {
+		return fmt.Errorf("subscription to %s: %w", sub.Topic, err)
+	}
+	if sub.Broadcast {
+		// Every instance must see every message, e.g. a cancel request only
+		// the instance running the saga can act on. The group is named after
+		// the instance so restarts reuse it, and it skips whatever was sent
+		// while the instance was down: a cancel for a saga that is no longer
+		// running has nothing to act on.
+		reader := newGroupReader(cfg, sub.Topic, instanceGroupID(cfg, sub.Handler), rt, nil)
+		reader.skipBacklog = true
+		kc.consumers = append(kc.consumers, reader)
+		return nil
+	}
+	for i := 0; i < max(sub.Concurrency, 1); i++ {
+		kc.consumers = append(kc.consumers, newGroupReader(cfg, sub.Topic, cfg.GroupID, rt, kc.deadLetters))
+	}
+	return nil
+}
+
+// instanceGroupID names the consumer group of this instance for a broadcast
+// handler after its instance ID, like the sticky balancer does.
+func instanceGroupID(cfg config.KafkaConfig, handler string) string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s-%s-%s", cfg.GroupID, handler, cmp.Or(cfg.InstanceID, hostname))
+}
+
+// Run blocks until every worker has stopped. The first worker error cancels
+// the others and is returned.
 func (kc *KafkaConsumer) Run(ctx context.Context) error {
-	return kc.consumer.Run(ctx)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, len(kc.consumers))
+	var wg sync.WaitGroup
+	for _, consumer := range kc.consumers {
+		wg.Add(1)
+		go func(c *groupReader) {
+			defer wg.Done()
+			if err := c.Run(ctx); err != nil {
+				errs <- err
+				cancel()
+			}
+		}(consumer)
+	}
+	wg.Wait()
+	close(errs)
+
+	return <-errs
 }
 
 func (kc *KafkaConsumer) Close() error {
-	return kc.consumer.Close()
+	var errs []error
+	for _, consumer := range kc.consumers {
+		if err := consumer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if kc.deadLetters != nil {
+		if err := kc.deadLetters.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
@@ -0,0 +1,146 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/segmentio/kafka-go"
+)
+
+// RewindGroup commits, for every partition of topic, the offset of the first
+// message at or after at, so the group reprocesses everything from then on.
+// Partitions with nothing newer are moved to their end. The group must have
+// no active members, otherwise the broker rejects the commit.
+func RewindGroup(ctx context.Context, brokers []string, groupID, topic string, at time.Time) (map[int]int64, error) {
+	client := &kafka.Client{Addr: kafka.TCP(brokers...)}
+
+	offsets, err := offsetsAt(ctx, client, topic, at)
+	if err != nil {
+		return nil, err
+	}
+	if err := commitOffsets(ctx, client, groupID, topic, offsets); err != nil {
+		return nil, err
+	}
+
+	logger.LogEvent(ctx, "kafka.offsets.rewound", "success", "topic", topic, "group_id", groupID, "at", at.Format(time.RFC3339), "partitions", len(offsets))
+	return offsets, nil
+}
+
+// seedOffsets positions partitions the group has never committed to at the
+// first message at or after at. Partitions with a committed offset keep it.
+func seedOffsets(ctx context.Context, brokers []string, groupID, topic string, at time.Time) error {
+	client := &kafka.Client{Addr: kafka.TCP(brokers...)}
+
+	offsets, err := offsetsAt(ctx, client, topic, at)
+	if err != nil {
+		return err
+	}
+
+	partitions := make([]int, 0, len(offsets))
+	for partition := range offsets {
+		partitions = append(partitions, partition)
+	}
+	committed, err := client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{
+		GroupID: groupID,
+		Topics:  map[string][]int{topic: partitions},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch committed offsets: %w", err)
+	}
+	if committed.Error != nil {
+		return fmt.Errorf("failed to fetch committed offsets: %w", committed.Error)
+	}
+	for _, p := range committed.Topics[topic] {
+		if p.Error == nil && p.CommittedOffset >= 0 {
+			delete(offsets, p.Partition)
+		}
+	}
+	if len(offsets) == 0 {
+		return nil
+	}
+
+	if err := commitOffsets(ctx, client, groupID, topic, offsets); err != nil {
+		return err
+	}
+	logger.LogEvent(ctx, "kafka.offsets.seeded", "success", "topic", topic, "group_id", groupID, "at", at.Format(time.RFC3339), "partitions", len(offsets))
+	return nil
+}
+
+// skipBacklog moves the group to the end of every partition of topic, so
+// whatever was sent while the group had no members is never delivered.
+func skipBacklog(ctx context.Context, brokers []string, groupID, topic string) error {
+	client := &kafka.Client{Addr: kafka.TCP(brokers...)}
+
+	offsets, err := offsetsAt(ctx, client, topic, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := commitOffsets(ctx, client, groupID, topic, offsets); err != nil {
+		return err
+	}
+	logger.LogEvent(ctx, "kafka.offsets.skipped", "success", "topic", topic, "group_id", groupID, "partitions", len(offsets))
+	return nil
+}
+
+func offsetsAt(ctx context.Context, client *kafka.Client, topic string, at time.Time) (map[int]int64, error) {
+	meta, err := client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{topic}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load topic metadata: %w", err)
+	}
+	if len(meta.Topics) != 1 {
+		return nil, fmt.Errorf("topic %s not found", topic)
+	}
+	if meta.Topics[0].Error != nil {
+		return nil, fmt.Errorf("failed to load topic metadata: %w", meta.Topics[0].Error)
+	}
+
+	requests := make([]kafka.OffsetRequest, 0, 2*len(meta.Topics[0].Partitions))
+	for _, p := range meta.Topics[0].Partitions {
+		requests = append(requests, kafka.TimeOffsetOf(p.ID, at), kafka.LastOffsetOf(p.ID))
+	}
+	listed, err := client.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics: map[string][]kafka.OffsetRequest{topic: requests},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list offsets: %w", err)
+	}
+
+	offsets := make(map[int]int64, len(meta.Topics[0].Partitions))
+	for _, p := range listed.Topics[topic] {
+		if p.Error != nil {
+			return nil, fmt.Errorf("failed to list offsets of partition %d: %w", p.Partition, p.Error)
+		}
+		offset := p.LastOffset
+		for o := range p.Offsets {
+			if o >= 0 {
+				offset = o
+			}
+		}
+		offsets[p.Partition] = offset
+	}
+	return offsets, nil
+}
+
+func commitOffsets(ctx context.Context, client *kafka.Client, groupID, topic string, offsets map[int]int64) error {
+	commits := make([]kafka.OffsetCommit, 0, len(offsets))
+	for partition, offset := range offsets {
+		commits = append(commits, kafka.OffsetCommit{Partition: partition, Offset: offset})
+	}
+
+	resp, err := client.OffsetCommit(ctx, &kafka.OffsetCommitRequest{
+		GroupID:      groupID,
+		GenerationID: -1,
+		Topics:       map[string][]kafka.OffsetCommit{topic: commits},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to commit offsets: %w", err)
+	}
+	for _, p := range resp.Topics[topic] {
+		if p.Error != nil {
+			return fmt.Errorf("failed to commit offset of partition %d: %w", p.Partition, p.Error)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/quiby-ai/common/pkg/events"
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/producer"
+	"github.com/quiby-ai/review-ingestor/internal/service"
+	"github.com/segmentio/kafka-go"
+)
+
+// payloadDecoder turns an envelope payload of the given schema version into
+// the value handed to the processor.
+type payloadDecoder func(raw json.RawMessage, version int) (any, error)
+
+// decodeExtractRequest validates and decodes the shared extract request
+// together with the ingestor-specific fields of service.Request. Version 2
+// payloads are converted to the same request. A request without a store is
+// for the App Store.
+func decodeExtractRequest(raw json.RawMessage, version int) (any, error) {
+	var req service.Request
+	switch version {
+	case 2:
+		var payload extractRequestV2
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return nil, schemaMismatch(err)
+		}
+		req = payload.request()
+		if err := validateRequest(req); err != nil {
+			return nil, err
+		}
+	default:
+		if err := validateExtractRequest(raw); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &req); err != nil {
+			return nil, err
+		}
+	}
+	if req.Store == "" {
+		req.Store = service.StoreAppStore
+	}
+	return req, nil
+}
+
+func decodeExtractResume(raw json.RawMessage, _ int) (any, error) {
+	var event producer.ExtractResume
+	if err := json.Unmarshal(raw, &event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func decodeRollbackRequest(raw json.RawMessage, _ int) (any, error) {
+	var req RollbackRequest
+	if len(raw) == 0 || string(raw) == "null" {
+		return req, nil
+	}
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func ignorePayload(json.RawMessage, int) (any, error) {
+	return nil, nil
+}
+
+// groupReader is one consumer group member for a topic. It commits offsets
+// and picks its start position according to KafkaConfig, or starts at the end
+// of the topic when skipBacklog is set. Messages that cannot be decoded go to
+// deadLetters when set and are dropped otherwise.
+type groupReader struct {
+	reader      *kafka.Reader
+	cfg         config.KafkaConfig
+	topic       string
+	groupID     string
+	skipBacklog bool
+	decode      payloadDecoder
+	processor   events.SagaMessageProcessor
+	deadLetters *deadLetterWriter
+}
+
+func newGroupReader(cfg config.KafkaConfig, topic, groupID string, rt route, deadLetters *deadLetterWriter) *groupReader {
+	readerCfg := kafka.ReaderConfig{
+		Brokers:               cfg.Brokers,
+		Topic:                 topic,
+		GroupID:               groupID,
+		StartOffset:           kafka.FirstOffset,
+		OffsetOutOfRangeError: cfg.OffsetReset == config.OffsetResetNone,
+		SessionTimeout:        cfg.SessionTimeout,
+		HeartbeatInterval:     cfg.HeartbeatInterval,
+		RebalanceTimeout:      cfg.MaxPollInterval,
+		GroupBalancers:        groupBalancers(cfg),
+	}
+	if cfg.StartFrom == config.StartFromLatest {
+		readerCfg.StartOffset = kafka.LastOffset
+	}
+	if cfg.CommitMode == config.CommitModeAuto {
+		readerCfg.CommitInterval = cfg.CommitInterval
+	}
+
+	return &groupReader{
+		reader:      kafka.NewReader(readerCfg),
+		cfg:         cfg,
+		topic:       topic,
+		groupID:     groupID,
+		decode:      rt.decode,
+		processor:   rt.processor,
+		deadLetters: deadLetters,
+	}
+}
+
+// Run reads messages until ctx is cancelled. Handler failures are logged and
+// the message is still committed; read, commit and dead-letter failures stop
+// the reader.
+func (r *groupReader) Run(ctx context.Context) error {
+	if r.skipBacklog {
+		if err := skipBacklog(ctx, r.cfg.Brokers, r.groupID, r.topic); err != nil {
+			return fmt.Errorf("failed to position %s at its end: %w", r.topic, err)
+		}
+	} else if at, err := time.Parse(time.RFC3339, r.cfg.StartFrom); err == nil {
+		if err := seedOffsets(ctx, r.cfg.Brokers, r.groupID, r.topic, at); err != nil {
+			return fmt.Errorf("failed to position %s at %s: %w", r.topic, r.cfg.StartFrom, err)
+		}
+	}
+
+	for {
+		msg, err := r.next(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to read from %s: %w", r.topic, err)
+		}
+
+		if err := r.handle(ctx, msg); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		if r.cfg.CommitMode == config.CommitModeManual {
+			if err := r.reader.CommitMessages(ctx, msg); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				if isRebalance(err) {
+					// The partition moved while the message was handled; its
+					// new owner receives it again.
+					logger.LogEvent(ctx, "kafka.offset.committed", "skipped", "topic", r.topic, "partition", msg.Partition, "offset", msg.Offset, "reason", "rebalance")
+					continue
+				}
+				logger.LogEvent(ctx, "kafka.offset.committed", "failed", "topic", r.topic, "partition", msg.Partition, "offset", msg.Offset)
+				return fmt.Errorf("failed to commit offset on %s: %w", r.topic, err)
+			}
+		}
+	}
+}
+
+// next reads the next message. In auto mode the offset is committed in the
+// background as the message is read; in manual mode it is left to Run.
+func (r *groupReader) next(ctx context.Context) (kafka.Message, error) {
+	if r.cfg.CommitMode == config.CommitModeManual {
+		return r.reader.FetchMessage(ctx)
+	}
+	return r.reader.ReadMessage(ctx)
+}
+
+// handle processes one message. It only fails when a message that cannot be
+// decoded could not be dead-lettered either.
+func (r *groupReader) handle(ctx context.Context, msg kafka.Message) error {
+	var envelope events.Envelope[json.RawMessage]
+	if err := json.Unmarshal(msg.Value, &envelope); err != nil {
+		logger.LogEvent(ctx, "kafka.message.decoded", "failed", "topic", r.topic, "reason", "invalid_envelope")
+		return r.reject(ctx, msg, "invalid_envelope", err)
+	}
+	ctx = logger.WithMessageID(ctx, envelope.MessageID)
+
+	version, err := payloadVersion(msg)
+	if err != nil {
+		logger.LogEvent(ctx, "kafka.message.decoded", "failed", "topic", r.topic, "reason", "unsupported_version", "error", err.Error())
+		return r.reject(ctx, msg, "unsupported_version", err)
+	}
+	payload, err := r.decode(envelope.Payload, version)
+	if err != nil {
+		logger.LogEvent(ctx, "kafka.message.decoded", "failed", "topic", r.topic, "reason", "invalid_payload", "error", err.Error())
+		return r.reject(ctx, msg, "invalid_payload", err)
+	}
+
+	if r.cfg.MaxProcessingTime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, r.cfg.MaxProcessingTime, errProcessingTimeout)
+		defer cancel()
+	}
+	if err := r.processor.Handle(ctx, payload, envelope.SagaID); err != nil {
+		logger.Error(ctx, "Failed to handle Kafka message", err, "topic", r.topic, "saga_id", envelope.SagaID)
+	}
+	return nil
+}
+
+// errProcessingTimeout cancels a saga that ran past kafka.max_processing_time.
+// It wraps service.ErrRunCancelled, so the saga is checkpointed and reported
+// as cancelled.
+var errProcessingTimeout = fmt.Errorf("%w: exceeded kafka.max_processing_time", service.ErrRunCancelled)
+
+// isRebalance reports whether a commit failed because the group rebalanced.
+func isRebalance(err error) bool {
+	return errors.Is(err, kafka.RebalanceInProgress) || errors.Is(err, kafka.IllegalGeneration) || errors.Is(err, kafka.UnknownMemberId)
+}
+
+// reject sends an undecodable message to the dead-letter topic, if any.
+func (r *groupReader) reject(ctx context.Context, msg kafka.Message, reason string, cause error) error {
+	if r.deadLetters == nil {
+		return nil
+	}
+
+	problems := []string{cause.Error()}
+	var validationErr *ValidationError
+	if errors.As(cause, &validationErr) {
+		problems = validationErr.Problems
+	}
+
+	if err := r.deadLetters.Send(ctx, msg, reason, problems); err != nil {
+		logger.LogEvent(ctx, "kafka.message.dead_lettered", "failed", "topic", r.topic, "reason", reason)
+		return err
+	}
+	logger.LogEvent(ctx, "kafka.message.dead_lettered", "success", "topic", r.topic, "reason", reason, "partition", msg.Partition, "offset", msg.Offset)
+	return nil
+}
+
+func (r *groupReader) Close() error {
+	if err := r.reader.Close(); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package consumer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/quiby-ai/common/pkg/events"
+	"github.com/quiby-ai/review-ingestor/internal/service"
+)
+
+// Handlers built into every router, usable in kafka.subscriptions.
+const (
+	HandlerExtract  = "extract"
+	HandlerResume   = "resume"
+	HandlerCancel   = "cancel"
+	HandlerRollback = "rollback"
+)
+
+// route is how messages for one handler are decoded and processed.
+type route struct {
+	decode    payloadDecoder
+	processor events.SagaMessageProcessor
+}
+
+// Router maps handler names to the decoder and processor of their messages,
+// so new event types only need a handler and a kafka.subscriptions entry
+// instead of a separate deployment.
+type Router struct {
+	routes map[string]route
+}
+
+// NewRouter returns a router with the built-in extract, resume, cancel and
+// rollback handlers.
+func NewRouter(svc *service.IngestService) *Router {
+	r := &Router{routes: make(map[string]route)}
+	r.Register(HandlerExtract, decodeExtractRequest, &IngestServiceProcessor{svc: svc})
+	r.Register(HandlerResume, decodeExtractResume, &ResumeProcessor{svc: svc})
+	r.Register(HandlerCancel, ignorePayload, &CancelProcessor{svc: svc})
+	r.Register(HandlerRollback, decodeRollbackRequest, &RollbackProcessor{svc: svc})
+	return r
+}
+
+// Register adds or replaces the handler called name. decode turns the
+// envelope payload, of the schema version given by the message (see
+// SchemaVersionHeader), into the value passed to processor; a decode error
+// sends the message to the dead-letter topic.
+func (r *Router) Register(name string, decode func(raw json.RawMessage, version int) (any, error), processor events.SagaMessageProcessor) {
+	r.routes[name] = route{decode: decode, processor: processor}
+}
+
+func (r *Router) route(name string) (route, error) {
+	rt, ok := r.routes[name]
+	if !ok {
+		return route{}, fmt.Errorf("unknown consumer handler %q", name)
+	}
+	return rt, nil
+}
@@ -0,0 +1,134 @@
+package consumer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/service"
+)
+
+// ValidationError lists why a payload was rejected before processing.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid payload: " + strings.Join(e.Problems, "; ")
+}
+
+// validateExtractRequest checks the structure of an extract request payload
+// so malformed messages never reach the service: app_id is required and
+// numeric or a bundle ID for the App Store, countries are ISO 3166 alpha-2
+// codes (or "all" for the App Store, and a single one for Steam) and the
+// dates, when set, parse and are ordered.
+func validateExtractRequest(raw json.RawMessage) error {
+	var req service.Request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return schemaMismatch(err)
+	}
+	return validateRequest(req)
+}
+
+func schemaMismatch(err error) *ValidationError {
+	return &ValidationError{Problems: []string{fmt.Sprintf("payload does not match schema: %v", err)}}
+}
+
+// validateRequest checks a request decoded from any payload version.
+func validateRequest(req service.Request) error {
+	var problems []string
+	// A multi-store request is checked for each of its stores.
+	legs := []service.Request{req}
+	if len(req.Stores) > 0 {
+		legs = legs[:0]
+		for _, store := range req.Stores {
+			legs = append(legs, req.ForStore(store))
+		}
+	}
+	for _, leg := range legs {
+		switch {
+		case strings.TrimSpace(leg.AppID) == "":
+			problems = append(problems, "app_id is required")
+		case (leg.Store == "" || leg.Store == service.StoreAppStore) && !isDigits(leg.AppID) && !isBundleID(leg.AppID):
+			problems = append(problems, fmt.Sprintf("app_id %q is neither numeric nor a bundle ID", leg.AppID))
+		}
+		if leg.WantsAllCountries() && leg.Store != "" && leg.Store != service.StoreAppStore {
+			problems = append(problems, fmt.Sprintf("countries %q is only supported for the App Store", service.AllCountries))
+		}
+		if service.IgnoresCountry(leg.Store) && len(leg.Countries) > 1 {
+			problems = append(problems, fmt.Sprintf("store %s is not split by country and takes a single country", leg.Store))
+		}
+	}
+
+	if len(req.Countries) == 0 {
+		problems = append(problems, "countries must not be empty")
+	}
+	for _, country := range req.Countries {
+		if !isCountryCode(country) && !req.WantsAllCountries() {
+			problems = append(problems, fmt.Sprintf("country %q is not an ISO 3166 alpha-2 code", country))
+		}
+	}
+
+	// A missing date_from is left to the service, which may apply its
+	// default window.
+	var from, to time.Time
+	var fromErr, toErr error
+	if req.DateFrom != "" {
+		if from, fromErr = service.ParseRequestDate(req.DateFrom); fromErr != nil {
+			problems = append(problems, fmt.Sprintf("date_from %q is not a YYYY-MM-DD or RFC 3339 date", req.DateFrom))
+		}
+	}
+	if req.DateTo != "" {
+		if to, toErr = service.ParseRequestDate(req.DateTo); toErr != nil {
+			problems = append(problems, fmt.Sprintf("date_to %q is not a YYYY-MM-DD or RFC 3339 date", req.DateTo))
+		}
+	}
+	if req.DateFrom != "" && req.DateTo != "" && fromErr == nil && toErr == nil && to.Before(from) {
+		problems = append(problems, "date_to is before date_from")
+	}
+
+	if req.MaxReviews < 0 {
+		problems = append(problems, "max_reviews must not be negative")
+	}
+	if req.StartOffset < 0 {
+		problems = append(problems, "start_offset must not be negative")
+	}
+	if req.StartBefore != "" {
+		if _, err := service.ParseRequestDate(req.StartBefore); err != nil {
+			problems = append(problems, fmt.Sprintf("start_before %q is not a YYYY-MM-DD or RFC 3339 date", req.StartBefore))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// isBundleID reports whether s looks like a reverse-DNS bundle ID such as
+// com.burbn.instagram.
+func isBundleID(s string) bool {
+	return strings.Contains(s, ".") && !strings.ContainsAny(s, " /")
+}
+
+func isCountryCode(s string) bool {
+	if len(s) != 2 {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
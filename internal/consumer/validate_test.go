@@ -0,0 +1,44 @@
+package consumer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateExtractRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  string
+		problems int
+	}{
+		{"valid", `{"app_id":"123","countries":["us","GB"],"date_from":"2025-01-01","date_to":"2025-02-01"}`, 0},
+		{"other store keeps non-numeric id", `{"app_id":"example.com","store":"trustpilot","countries":["us"],"date_from":"2025-01-01"}`, 0},
+		{"app store bundle id", `{"app_id":"com.burbn.instagram","countries":["us"],"date_from":"2025-01-01"}`, 0},
+		{"non-numeric app store id", `{"app_id":"abc","countries":["us"],"date_from":"2025-01-01"}`, 1},
+		{"missing fields", `{}`, 2},
+		{"rfc 3339 dates", `{"app_id":"1","countries":["us"],"date_from":"2025-01-01T00:00:00+02:00","date_to":"2025-01-02T00:00:00Z"}`, 0},
+		{"bad country and dates", `{"app_id":"1","countries":["usa"],"date_from":"01/02/2025","date_to":"x"}`, 3},
+		{"dates out of order", `{"app_id":"1","countries":["us"],"date_from":"2025-02-01","date_to":"2025-01-01"}`, 1},
+		{"multi-store with per-store ids", `{"app_id":"389801252","stores":["appstore","googleplay"],"app_ids":{"googleplay":"com.instagram.android"},"countries":["us"],"date_from":"2025-01-01"}`, 0},
+		{"multi-store with non-numeric app store id", `{"app_id":"instagram","stores":["appstore","googleplay"],"countries":["us"],"date_from":"2025-01-01"}`, 1},
+		{"wrong types", `{"app_id":1,"countries":"us"}`, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateExtractRequest([]byte(tt.payload))
+			if tt.problems == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("error = %v, want *ValidationError", err)
+			}
+			if len(validationErr.Problems) != tt.problems {
+				t.Errorf("problems = %q, want %d", validationErr.Problems, tt.problems)
+			}
+		})
+	}
+}
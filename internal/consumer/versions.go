@@ -0,0 +1,102 @@
+package consumer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/quiby-ai/common/pkg/events"
+	"github.com/quiby-ai/review-ingestor/internal/service"
+	"github.com/segmentio/kafka-go"
+)
+
+// SchemaVersionHeader is the Kafka header naming the payload schema version
+// of a message, e.g. "2" or "v2". Without it the envelope's version field
+// is used, and without that the payload is version 1.
+const SchemaVersionHeader = "schema-version"
+
+// latestSchemaVersion is the newest payload version the consumer decodes.
+const latestSchemaVersion = 2
+
+// payloadVersion returns the schema version of msg's payload.
+func payloadVersion(msg kafka.Message) (int, error) {
+	for _, header := range msg.Headers {
+		if strings.EqualFold(header.Key, SchemaVersionHeader) {
+			return parseSchemaVersion(string(header.Value))
+		}
+	}
+
+	var envelope struct {
+		Version json.RawMessage
+	}
+	if err := json.Unmarshal(msg.Value, &envelope); err != nil || len(envelope.Version) == 0 {
+		return 1, nil
+	}
+	return parseSchemaVersion(strings.Trim(string(envelope.Version), `"`))
+}
+
+func parseSchemaVersion(value string) (int, error) {
+	version, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(value)), "v"))
+	if err != nil || version < 1 || version > latestSchemaVersion {
+		return 0, &ValidationError{Problems: []string{fmt.Sprintf("unsupported schema version %q", value)}}
+	}
+	return version, nil
+}
+
+// extractRequestV2 is version 2 of the extract request payload. It groups
+// the app, the date window and the start position and adds max_reviews:
+//
+//	{"app": {"id": "389801252", "name": "Instagram", "store": "appstore"},
+//	 "countries": ["us"], "window": {"from": "2025-01-01", "to": "2025-02-01"},
+//	 "start": {"offset": 200, "before": "2025-01-20"},
+//	 "versions": {"min": "300.0"}, "max_reviews": 1000}
+//
+// A multi-store request lists "stores" in app and maps them to their app IDs
+// in "ids".
+type extractRequestV2 struct {
+	App struct {
+		ID     string            `json:"id"`
+		Name   string            `json:"name"`
+		Store  string            `json:"store"`
+		Stores []string          `json:"stores"`
+		IDs    map[string]string `json:"ids"`
+	} `json:"app"`
+	Countries []string `json:"countries"`
+	Window    struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	} `json:"window"`
+	Start struct {
+		Offset int    `json:"offset"`
+		Before string `json:"before"`
+	} `json:"start"`
+	Versions struct {
+		Min string `json:"min"`
+		Max string `json:"max"`
+	} `json:"versions"`
+	MaxReviews int    `json:"max_reviews"`
+	Tenant     string `json:"tenant"`
+}
+
+// request converts the payload to the version 1 request the service takes.
+func (p extractRequestV2) request() service.Request {
+	return service.Request{
+		ExtractRequest: events.ExtractRequest{
+			AppID:     p.App.ID,
+			AppName:   p.App.Name,
+			Countries: p.Countries,
+			DateFrom:  p.Window.From,
+			DateTo:    p.Window.To,
+		},
+		Store:       p.App.Store,
+		MinVersion:  p.Versions.Min,
+		MaxVersion:  p.Versions.Max,
+		MaxReviews:  p.MaxReviews,
+		Tenant:      p.Tenant,
+		Stores:      p.App.Stores,
+		AppIDs:      p.App.IDs,
+		StartOffset: p.Start.Offset,
+		StartBefore: p.Start.Before,
+	}
+}
@@ -0,0 +1,73 @@
+package consumer
+
+import (
+	"testing"
+
+	"github.com/quiby-ai/review-ingestor/internal/service"
+	"github.com/segmentio/kafka-go"
+)
+
+func TestPayloadVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     kafka.Message
+		want    int
+		wantErr bool
+	}{
+		{"default", kafka.Message{Value: []byte(`{"Payload":{}}`)}, 1, false},
+		{"header", kafka.Message{Headers: []kafka.Header{{Key: "Schema-Version", Value: []byte("v2")}}, Value: []byte(`{}`)}, 2, false},
+		{"envelope field", kafka.Message{Value: []byte(`{"Version":"2","Payload":{}}`)}, 2, false},
+		{"unsupported", kafka.Message{Value: []byte(`{"version":7}`)}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := payloadVersion(tt.msg)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("payloadVersion = %d, %v; want %d, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeExtractRequestV2(t *testing.T) {
+	raw := []byte(`{"app":{"id":"example.com","store":"trustpilot"},"countries":["us"],"window":{"from":"2025-01-01"},"max_reviews":100}`)
+	decoded, err := decodeExtractRequest(raw, 2)
+	if err != nil {
+		t.Fatalf("decodeExtractRequest: %v", err)
+	}
+	req := decoded.(service.Request)
+	if req.AppID != "example.com" || req.Store != service.StoreTrustpilot || req.DateFrom != "2025-01-01" || req.MaxReviews != 100 {
+		t.Errorf("decoded request = %+v", req)
+	}
+}
+
+func TestDecodeExtractRequestV2Start(t *testing.T) {
+	raw := []byte(`{"app":{"id":"389801252"},"countries":["us"],"window":{"from":"2025-01-01"},"start":{"offset":200,"before":"2025-01-20"}}`)
+	decoded, err := decodeExtractRequest(raw, 2)
+	if err != nil {
+		t.Fatalf("decodeExtractRequest: %v", err)
+	}
+	req := decoded.(service.Request)
+	if req.StartOffset != 200 || req.StartBefore != "2025-01-20" {
+		t.Errorf("start = %d, %q; want 200, %q", req.StartOffset, req.StartBefore, "2025-01-20")
+	}
+}
+
+func TestDecodeExtractRequestStore(t *testing.T) {
+	cases := map[string]struct {
+		raw  string
+		want string
+	}{
+		"store":    {raw: `{"app_id":"B00ZV9PXP2","countries":["us"],"date_from":"2025-01-01","store":"amazon"}`, want: service.StoreAmazon},
+		"no store": {raw: `{"app_id":"389801252","countries":["us"],"date_from":"2025-01-01"}`, want: service.StoreAppStore},
+	}
+	for name, tc := range cases {
+		decoded, err := decodeExtractRequest([]byte(tc.raw), 1)
+		if err != nil {
+			t.Fatalf("%s: decodeExtractRequest: %v", name, err)
+		}
+		if got := decoded.(service.Request).Store; got != tc.want {
+			t.Errorf("%s: store = %q, want %q", name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package debugcapture
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/objectstore"
+)
+
+const redacted = "[redacted]"
+
+// sensitiveHeaderParts marks headers whose values are never written out.
+var sensitiveHeaderParts = []string{"authorization", "cookie", "token", "key", "secret", "interface-code"}
+
+// Capture is the JSON document written for one failed exchange.
+type Capture struct {
+	CapturedAt time.Time `json:"captured_at"`
+	Reason     string    `json:"reason"`
+	Request    struct {
+		Method  string            `json:"method"`
+		URL     string            `json:"url"`
+		Headers map[string]string `json:"headers"`
+	} `json:"request"`
+	Response struct {
+		Status        int    `json:"status,omitempty"`
+		Body          string `json:"body,omitempty"`
+		BodyBytes     int    `json:"body_bytes"`
+		BodyTruncated bool   `json:"body_truncated,omitempty"`
+	} `json:"response"`
+}
+
+// Capturer writes failed requests and their responses to a local directory
+// or an S3 prefix for diagnosis.
+type Capturer struct {
+	cfg    config.DebugConfig
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+func NewCapturer(cfg config.DebugConfig, s3Cfg config.S3Config) (*Capturer, error) {
+	c := &Capturer{cfg: cfg}
+	if bucket, prefix, ok := objectstore.ParseURL(cfg.CaptureDest); ok {
+		client, err := objectstore.NewClient(s3Cfg)
+		if err != nil {
+			return nil, err
+		}
+		c.client, c.bucket, c.prefix = client, bucket, prefix
+		return c, nil
+	}
+	if err := os.MkdirAll(cfg.CaptureDest, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create capture directory: %w", err)
+	}
+	return c, nil
+}
+
+// CaptureFailure records one failed GET. status is 0 when no response was
+// received. Failures to write the capture are logged, never returned.
+func (c *Capturer) CaptureFailure(ctx context.Context, requestURL string, headers map[string]string, status int, body []byte, reason string) {
+	capture := Capture{CapturedAt: time.Now().UTC(), Reason: reason}
+	capture.Request.Method = "GET"
+	capture.Request.URL = requestURL
+	capture.Request.Headers = RedactHeaders(headers)
+	capture.Response.Status = status
+	capture.Response.BodyBytes = len(body)
+	if c.cfg.CaptureMaxBody > 0 && len(body) > c.cfg.CaptureMaxBody {
+		body = body[:c.cfg.CaptureMaxBody]
+		capture.Response.BodyTruncated = true
+	}
+	capture.Response.Body = string(body)
+
+	data, err := json.MarshalIndent(capture, "", "  ")
+	if err != nil {
+		logger.Warn(ctx, "Failed to encode debug capture", "error", err.Error())
+		return
+	}
+
+	name := fmt.Sprintf("%s-%s.json", capture.CapturedAt.Format("20060102T150405.000Z"), uuid.NewString()[:8])
+	dest, err := c.write(ctx, name, data)
+	if err != nil {
+		logger.LogEvent(ctx, "debug.capture.written", "failed", "reason", reason, "error", err.Error())
+		return
+	}
+	logger.LogEvent(ctx, "debug.capture.written", "success", "reason", reason, "status", status, "dest", dest)
+}
+
+func (c *Capturer) write(ctx context.Context, name string, data []byte) (string, error) {
+	if c.client == nil {
+		dest := filepath.Join(c.cfg.CaptureDest, name)
+		return dest, os.WriteFile(dest, data, 0o644)
+	}
+
+	key := path.Join(c.prefix, name)
+	_, err := c.client.PutObject(ctx, c.bucket, key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{ContentType: "application/json"})
+	return fmt.Sprintf("s3://%s/%s", c.bucket, key), err
+}
+
+// RedactHeaders returns a copy of headers with credentials replaced.
+func RedactHeaders(headers map[string]string) map[string]string {
+	out := make(map[string]string, len(headers))
+	for name, value := range headers {
+		out[name] = value
+		lower := strings.ToLower(name)
+		for _, part := range sensitiveHeaderParts {
+			if strings.Contains(lower, part) {
+				out[name] = redacted
+				break
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,33 @@
+package debugcapture
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedactHeaders(t *testing.T) {
+	headers := map[string]string{
+		"Authorization":  "Bearer abc",
+		"apikey":         "k",
+		"Cookie":         "session=1",
+		"Interface-Code": "code_1",
+		"User-Agent":     "Mozilla/5.0",
+		"referer":        "https://apps.apple.com/",
+	}
+	want := map[string]string{
+		"Authorization":  redacted,
+		"apikey":         redacted,
+		"Cookie":         redacted,
+		"Interface-Code": redacted,
+		"User-Agent":     "Mozilla/5.0",
+		"referer":        "https://apps.apple.com/",
+	}
+
+	got := RedactHeaders(headers)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RedactHeaders() = %v, want %v", got, want)
+	}
+	if headers["Authorization"] != "Bearer abc" {
+		t.Error("RedactHeaders modified its input")
+	}
+}
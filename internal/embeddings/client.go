@@ -0,0 +1,87 @@
+package embeddings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+// Client requests embeddings from an OpenAI compatible /embeddings
+// endpoint.
+type Client struct {
+	client *http.Client
+	cfg    config.EmbeddingsConfig
+}
+
+func NewClient(cfg config.EmbeddingsConfig) *Client {
+	return &Client{client: &http.Client{Timeout: cfg.Timeout}, cfg: cfg}
+}
+
+type request struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions,omitempty"`
+}
+
+type response struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed returns one vector per text, in the order of texts.
+func (c *Client) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(request{Model: c.cfg.Model, Input: texts, Dimensions: c.cfg.Dimensions})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode embeddings request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build embeddings request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.APIKey)
+	}
+
+	timer := logger.StartTimer()
+	resp, err := c.client.Do(req)
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "embeddings.request", "failed", timer(), "texts", len(texts), "error", err.Error())
+		return nil, fmt.Errorf("embeddings request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		logger.LogEventWithLatency(ctx, "embeddings.request", "failed", timer(), "texts", len(texts), "status", resp.StatusCode)
+		return nil, fmt.Errorf("embeddings endpoint returned status %d", resp.StatusCode)
+	}
+	var decoded response
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		logger.LogEventWithLatency(ctx, "embeddings.request", "failed", timer(), "texts", len(texts), "error", err.Error())
+		return nil, fmt.Errorf("failed to decode embeddings response: %w", err)
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, item := range decoded.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return nil, fmt.Errorf("embeddings response has unexpected index %d", item.Index)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	for i, vector := range vectors {
+		if len(vector) != c.cfg.Dimensions {
+			return nil, fmt.Errorf("embedding %d has %d dimensions, want %d", i, len(vector), c.cfg.Dimensions)
+		}
+	}
+	logger.LogEventWithLatency(ctx, "embeddings.request", "success", timer(), "texts", len(texts))
+	return vectors, nil
+}
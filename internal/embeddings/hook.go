@@ -0,0 +1,105 @@
+package embeddings
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// queueSize bounds how many stored reviews wait for an embedding. Reviews
+// arriving while the queue is full are left without one.
+const queueSize = 10000
+
+// Store keeps the vectors of stored reviews.
+type Store interface {
+	SaveEmbeddings(ctx context.Context, keys []storage.ReviewKey, vectors [][]float32) error
+}
+
+// Embedder is a post-save hook that embeds newly stored reviews. Reviews are
+// queued as they are saved and sent in batches of BatchSize, or whatever has
+// queued after FlushInterval, by Run.
+type Embedder struct {
+	embed func(ctx context.Context, texts []string) ([][]float32, error)
+	store Store
+	cfg   config.EmbeddingsConfig
+	queue chan item
+}
+
+type item struct {
+	key  storage.ReviewKey
+	text string
+}
+
+func NewEmbedder(client *Client, store Store, cfg config.EmbeddingsConfig) *Embedder {
+	return &Embedder{embed: client.Embed, store: store, cfg: cfg, queue: make(chan item, queueSize)}
+}
+
+// AfterSave queues the reviews that have any text.
+func (e *Embedder) AfterSave(ctx context.Context, rows []storage.RawReview) {
+	for _, row := range rows {
+		text := Text(row)
+		if text == "" {
+			continue
+		}
+		select {
+		case e.queue <- item{key: row.Key(), text: text}:
+		default:
+			logger.LogEvent(ctx, "embeddings.batch.saved", "skipped", "reason", "queue_full")
+			return
+		}
+	}
+}
+
+// Text is what is embedded for a review: its title and plain-text body.
+func Text(row storage.RawReview) string {
+	return strings.TrimSpace(row.Title + "\n\n" + row.ContentPlain)
+}
+
+// Run embeds queued reviews until ctx is cancelled. A failed batch is
+// logged and dropped; the reviews stay without an embedding.
+func (e *Embedder) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]item, 0, e.cfg.BatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case next := <-e.queue:
+			batch = append(batch, next)
+			if len(batch) < e.cfg.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		e.flush(ctx, batch)
+		batch = batch[:0]
+	}
+}
+
+func (e *Embedder) flush(ctx context.Context, batch []item) {
+	keys := make([]storage.ReviewKey, len(batch))
+	texts := make([]string, len(batch))
+	for i, queued := range batch {
+		keys[i], texts[i] = queued.key, queued.text
+	}
+
+	timer := logger.StartTimer()
+	vectors, err := e.embed(ctx, texts)
+	if err == nil {
+		err = e.store.SaveEmbeddings(ctx, keys, vectors)
+	}
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "embeddings.batch.saved", "failed", timer(), "reviews", len(batch), "error", err.Error())
+		return
+	}
+	logger.LogEventWithLatency(ctx, "embeddings.batch.saved", "success", timer(), "reviews", len(batch))
+}
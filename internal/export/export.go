@@ -0,0 +1,176 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/objectstore"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// ReviewSource streams stored reviews.
+type ReviewSource interface {
+	EachReview(ctx context.Context, filter storage.ExportFilter, fn func(storage.StoredReview) error) (int, error)
+}
+
+// Record is the exported shape of a review, shared by every format.
+type Record struct {
+	Store           string `json:"store"`
+	ID              string `json:"id"`
+	AppID           string `json:"app_id"`
+	Country         string `json:"country"`
+	Territory       string `json:"territory,omitempty"`
+	Rating          int32  `json:"rating"`
+	Title           string `json:"title"`
+	Content         string `json:"content"`
+	ReviewedAt      string `json:"reviewed_at"`
+	ResponseDate    string `json:"response_date,omitempty"`
+	ResponseContent string `json:"response_content,omitempty"`
+}
+
+func newRecord(review storage.StoredReview) Record {
+	record := Record{
+		Store:      review.Store,
+		ID:         review.ID,
+		AppID:      review.AppID,
+		Country:    review.Country,
+		Rating:     int32(review.Rating),
+		Title:      review.Title,
+		Content:    review.Content,
+		ReviewedAt: review.ReviewedAt.UTC().Format(time.RFC3339),
+	}
+	if review.Territory != nil {
+		record.Territory = *review.Territory
+	}
+	if review.ResponseDate != nil {
+		record.ResponseDate = review.ResponseDate.UTC().Format(time.RFC3339)
+	}
+	if review.ResponseContent != nil {
+		record.ResponseContent = *review.ResponseContent
+	}
+	return record
+}
+
+// Exporter writes stored reviews to a local path or an s3://bucket/key URL.
+type Exporter struct {
+	source ReviewSource
+	s3Cfg  config.S3Config
+}
+
+func NewExporter(source ReviewSource, s3Cfg config.S3Config) *Exporter {
+	return &Exporter{source: source, s3Cfg: s3Cfg}
+}
+
+// Export writes the reviews matching filter in format to dest and returns the
+// number of reviews exported.
+func (e *Exporter) Export(ctx context.Context, filter storage.ExportFilter, format, dest string) (int, error) {
+	switch format {
+	case FormatCSV, FormatJSONL:
+	default:
+		return 0, fmt.Errorf("unsupported export format %q", format)
+	}
+
+	timer := logger.StartTimer()
+
+	bucket, key, isS3 := objectstore.ParseURL(dest)
+	path := dest
+	if isS3 {
+		tmp, err := os.CreateTemp("", "review-export-*"+filepath.Ext(key))
+		if err != nil {
+			return 0, fmt.Errorf("failed to create temp file: %w", err)
+		}
+		tmp.Close()
+		defer os.Remove(tmp.Name())
+		path = tmp.Name()
+	}
+
+	count, err := e.writeFile(ctx, filter, format, path)
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "export.reviews.written", "failed", timer(), "format", format)
+		return count, err
+	}
+
+	if isS3 {
+		if err := e.upload(ctx, bucket, key, path); err != nil {
+			logger.LogEventWithLatency(ctx, "export.reviews.written", "failed", timer(), "format", format, "error", "upload_failed")
+			return count, err
+		}
+	}
+
+	logger.LogEventWithLatency(ctx, "export.reviews.written", "success", timer(), "format", format, "count", count, "dest", dest)
+	return count, nil
+}
+
+func (e *Exporter) writeFile(ctx context.Context, filter storage.ExportFilter, format, path string) (int, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var count int
+	switch format {
+	case FormatCSV:
+		count, err = e.writeCSV(ctx, filter, f)
+	case FormatJSONL:
+		count, err = e.writeJSONL(ctx, filter, f)
+	default:
+		return 0, fmt.Errorf("unsupported export format %q", format)
+	}
+	if err != nil {
+		return count, err
+	}
+	return count, f.Close()
+}
+
+var csvHeader = []string{"store", "id", "app_id", "country", "territory", "rating", "title", "content", "reviewed_at", "response_date", "response_content"}
+
+func (e *Exporter) writeCSV(ctx context.Context, filter storage.ExportFilter, w io.Writer) (int, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return 0, err
+	}
+
+	count, err := e.source.EachReview(ctx, filter, func(review storage.StoredReview) error {
+		r := newRecord(review)
+		return cw.Write([]string{r.Store, r.ID, r.AppID, r.Country, r.Territory, strconv.Itoa(int(r.Rating)), r.Title, r.Content, r.ReviewedAt, r.ResponseDate, r.ResponseContent})
+	})
+	if err != nil {
+		return count, err
+	}
+	cw.Flush()
+	return count, cw.Error()
+}
+
+func (e *Exporter) writeJSONL(ctx context.Context, filter storage.ExportFilter, w io.Writer) (int, error) {
+	enc := json.NewEncoder(w)
+	return e.source.EachReview(ctx, filter, func(review storage.StoredReview) error {
+		return enc.Encode(newRecord(review))
+	})
+}
+
+func (e *Exporter) upload(ctx context.Context, bucket, key, path string) error {
+	client, err := objectstore.NewClient(e.s3Cfg)
+	if err != nil {
+		return err
+	}
+	if _, err := client.FPutObject(ctx, bucket, key, path, minio.PutObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to upload export to s3://%s/%s: %w", bucket, key, err)
+	}
+	return nil
+}
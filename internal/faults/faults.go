@@ -0,0 +1,93 @@
+// Package faults injects controlled failures into the HTTP and storage layers
+// so retries and partial-failure handling can be exercised end to end. It is
+// meant for test and staging deployments only.
+package faults
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/quiby-ai/common/pkg/httpx"
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/budget"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+// Injector decides at random, with the configured rates, whether to inject a
+// fault.
+type Injector struct {
+	cfg  config.FaultsConfig
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func NewInjector(cfg config.FaultsConfig) *Injector {
+	return &Injector{cfg: cfg, rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (i *Injector) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rand.Float64() < rate
+}
+
+// DBError returns an injected Postgres error with the configured SQLSTATE,
+// or nil.
+func (i *Injector) DBError(ctx context.Context) error {
+	if !i.roll(i.cfg.DBErrorRate) {
+		return nil
+	}
+	logger.LogEvent(ctx, "faults.injected", "success", "kind", "db_error", "code", i.cfg.DBErrorCode)
+	return &pq.Error{Code: pq.ErrorCode(i.cfg.DBErrorCode), Message: "injected fault"}
+}
+
+// WrapHTTP returns next with injected 429 responses and slow responses.
+func (i *Injector) WrapHTTP(next httpx.Client) httpx.Client {
+	return &httpClient{next: next, faults: i}
+}
+
+type httpClient struct {
+	next   httpx.Client
+	faults *Injector
+}
+
+var _ httpx.Client = (*httpClient)(nil)
+
+func (c *httpClient) Do(ctx context.Context, req httpx.Request) (httpx.Response, error) {
+	if resp, injected, err := c.inject(ctx); injected {
+		return resp, err
+	}
+	return c.next.Do(ctx, req)
+}
+
+func (c *httpClient) DoGET(ctx context.Context, url string, query map[string]string, headers map[string]string) (httpx.Response, error) {
+	if resp, injected, err := c.inject(ctx); injected {
+		return resp, err
+	}
+	return c.next.DoGET(ctx, url, query, headers)
+}
+
+// inject delays the request when a slow response is rolled and reports
+// whether it answers the request itself, with a 429 or the context's error.
+func (c *httpClient) inject(ctx context.Context) (httpx.Response, bool, error) {
+	if c.faults.roll(c.faults.cfg.SlowRate) {
+		logger.LogEvent(ctx, "faults.injected", "success", "kind", "slow_response", "delay_ms", c.faults.cfg.SlowDelay.Milliseconds())
+		select {
+		case <-ctx.Done():
+			return httpx.Response{}, true, ctx.Err()
+		case <-time.After(c.faults.cfg.SlowDelay):
+		}
+	}
+	if c.faults.roll(c.faults.cfg.HTTP429Rate) {
+		logger.LogEvent(ctx, "faults.injected", "success", "kind", "http_429")
+		budget.From(ctx).Request(429)
+		return httpx.Response{Status: 429, Body: []byte(`{"errors":[{"status":"429","title":"injected fault"}]}`)}, true, nil
+	}
+	return httpx.Response{}, false, nil
+}
@@ -0,0 +1,75 @@
+// Package features is a lightweight feature-flag layer for risky ingestion
+// behaviors, such as new scraping strategies, so they can be rolled out app
+// by app. Flags are resolved per saga from the configuration and the app's
+// overrides and travel in the saga's context, so code deep in a fetcher can
+// check them without extra plumbing.
+package features
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// Set holds feature flags by lower-cased name. Flags it does not name are
+// off.
+type Set map[string]bool
+
+// Resolve merges flag layers, later ones taking precedence, e.g. the global
+// flags followed by an app's overrides.
+func Resolve(layers ...map[string]bool) Set {
+	set := make(Set)
+	for _, layer := range layers {
+		for name, on := range layer {
+			set[strings.ToLower(name)] = on
+		}
+	}
+	return set
+}
+
+// ParseList parses a comma-separated flag list such as
+// "parallel_pagination,-rss_fallback": names are switched on, names with a
+// leading '-' off.
+func ParseList(list string) map[string]bool {
+	flags := make(map[string]bool)
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		name, off := strings.CutPrefix(item, "-")
+		if name == "" {
+			continue
+		}
+		flags[strings.ToLower(name)] = !off
+	}
+	return flags
+}
+
+// Enabled reports whether the flag name is on.
+func (s Set) Enabled(name string) bool {
+	return s[strings.ToLower(name)]
+}
+
+// Names returns the flags that are on, sorted.
+func (s Set) Names() []string {
+	var names []string
+	for name, on := range s {
+		if on {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+type setKey struct{}
+
+// With attaches set to ctx.
+func With(ctx context.Context, set Set) context.Context {
+	return context.WithValue(ctx, setKey{}, set)
+}
+
+// Enabled reports whether the flag name is on in the set attached to ctx.
+// Without a set every flag is off.
+func Enabled(ctx context.Context, name string) bool {
+	set, _ := ctx.Value(setKey{}).(Set)
+	return set.Enabled(name)
+}
@@ -0,0 +1,25 @@
+package features
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	global := ParseList(" Parallel_Pagination, rss_fallback ,,-proxy_rotation")
+	app := map[string]bool{"rss_fallback": false, "proxy_rotation": true}
+
+	set := Resolve(global, app)
+	if want := []string{"parallel_pagination", "proxy_rotation"}; !slices.Equal(set.Names(), want) {
+		t.Errorf("Names() = %v, want %v", set.Names(), want)
+	}
+
+	ctx := With(context.Background(), set)
+	if !Enabled(ctx, "PARALLEL_PAGINATION") || Enabled(ctx, "rss_fallback") || Enabled(ctx, "unknown") {
+		t.Errorf("Enabled() does not match the resolved set %v", set)
+	}
+	if Enabled(context.Background(), "parallel_pagination") {
+		t.Error("Enabled() without a set should be off")
+	}
+}
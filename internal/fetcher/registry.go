@@ -0,0 +1,103 @@
+// Package fetcher resolves the review fetcher for the store an extract
+// request targets.
+package fetcher
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/appstore"
+
+	"github.com/quiby-ai/common/pkg/httpx"
+)
+
+// Fetcher streams an app's reviews from one store.
+type Fetcher interface {
+	StreamReviews(ctx context.Context, country, appID string, opts *appstore.FetchOptions, window int, flush func(context.Context, []appstore.Review) error) (int, error)
+}
+
+// Factory builds the fetcher of a store.
+type Factory func(http httpx.Client, cfg config.Config) Fetcher
+
+// Registry maps store identifiers to fetchers. Stores are registered with a
+// factory and built on first use, so unused sources cost nothing; fetchers
+// that need more wiring than a factory gets can be added ready-made.
+type Registry struct {
+	http httpx.Client
+	cfg  config.Config
+
+	mu        sync.Mutex
+	factories map[string]Factory
+	fetchers  map[string]Fetcher
+}
+
+// NewRegistry returns a registry holding the built-in stores. Their
+// fetchers are built with http and cfg.
+func NewRegistry(http httpx.Client, cfg config.Config) *Registry {
+	r := &Registry{http: http, cfg: cfg, factories: make(map[string]Factory), fetchers: make(map[string]Fetcher)}
+	for store, factory := range builtin {
+		r.factories[store] = factory
+	}
+	return r
+}
+
+// Register makes a store available under store, replacing any fetcher
+// registered for it before.
+func (r *Registry) Register(store string, factory Factory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[store] = factory
+	delete(r.fetchers, store)
+}
+
+// Add makes a store available with an already built fetcher.
+func (r *Registry) Add(store string, fetcher Fetcher) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.factories, store)
+	r.fetchers[store] = fetcher
+}
+
+// Has reports whether store is registered, without building its fetcher.
+func (r *Registry) Has(store string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, built := r.fetchers[store]
+	_, registered := r.factories[store]
+	return built || registered
+}
+
+// Resolve returns the fetcher of store, building it on first use. Later
+// calls share the same fetcher.
+func (r *Registry) Resolve(store string) (Fetcher, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if fetcher, ok := r.fetchers[store]; ok {
+		return fetcher, true
+	}
+	factory, ok := r.factories[store]
+	if !ok {
+		return nil, false
+	}
+	fetcher := factory(r.http, r.cfg)
+	r.fetchers[store] = fetcher
+	delete(r.factories, store)
+	return fetcher, true
+}
+
+// Stores returns the registered store identifiers in alphabetical order.
+func (r *Registry) Stores() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stores := make([]string, 0, len(r.factories)+len(r.fetchers))
+	for store := range r.factories {
+		stores = append(stores, store)
+	}
+	for store := range r.fetchers {
+		stores = append(stores, store)
+	}
+	sort.Strings(stores)
+	return stores
+}
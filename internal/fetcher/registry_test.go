@@ -0,0 +1,41 @@
+package fetcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/appstore"
+
+	"github.com/quiby-ai/common/pkg/httpx"
+)
+
+type stubFetcher struct{}
+
+func (stubFetcher) StreamReviews(context.Context, string, string, *appstore.FetchOptions, int, func(context.Context, []appstore.Review) error) (int, error) {
+	return 0, nil
+}
+
+func TestRegistryBuildsOnce(t *testing.T) {
+	r := NewRegistry(nil, config.Config{})
+	builds := 0
+	r.Register("rss", func(httpx.Client, config.Config) Fetcher {
+		builds++
+		return stubFetcher{}
+	})
+
+	if !r.Has("rss") || builds != 0 {
+		t.Fatalf("Has built the fetcher or missed the store: has=%v builds=%d", r.Has("rss"), builds)
+	}
+	for range 2 {
+		if _, ok := r.Resolve("rss"); !ok {
+			t.Fatal("Resolve(rss) found no fetcher")
+		}
+	}
+	if builds != 1 {
+		t.Errorf("fetcher built %d times, want 1", builds)
+	}
+	if _, ok := r.Resolve("googleplay"); ok {
+		t.Error("Resolve of an unregistered store succeeded")
+	}
+}
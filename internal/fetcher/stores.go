@@ -0,0 +1,52 @@
+package fetcher
+
+import (
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/amazonstore"
+	"github.com/quiby-ai/review-ingestor/internal/galaxystore"
+	"github.com/quiby-ai/review-ingestor/internal/huawei"
+	"github.com/quiby-ai/review-ingestor/internal/msstore"
+	"github.com/quiby-ai/review-ingestor/internal/steam"
+	"github.com/quiby-ai/review-ingestor/internal/webreviews"
+
+	"github.com/quiby-ai/common/pkg/httpx"
+)
+
+// Store identifiers, as sent in the store field of extract requests.
+const (
+	StoreAppStore = "appstore"
+	StoreAmazon   = "amazon"
+	StoreHuawei   = "huawei"
+	StoreSteam    = "steam"
+	// StoreTrustpilot requests use the business domain as the app ID.
+	StoreTrustpilot = "trustpilot"
+	// StoreMicrosoft requests use the Store product ID as the app ID.
+	StoreMicrosoft = "msstore"
+	// StoreGalaxy requests use the Galaxy Store content ID as the app ID.
+	StoreGalaxy = "galaxy"
+)
+
+// builtin holds the stores every registry starts with. A new source only
+// needs an entry here. The App Store is not listed: its fetcher shares the
+// token extraction, rate limiting and caching set up in main and is added
+// ready-made.
+var builtin = map[string]Factory{
+	StoreAmazon: func(http httpx.Client, cfg config.Config) Fetcher {
+		return amazonstore.NewReviewFetcher(http, cfg)
+	},
+	StoreHuawei: func(http httpx.Client, cfg config.Config) Fetcher {
+		return huawei.NewReviewFetcher(http, cfg)
+	},
+	StoreSteam: func(http httpx.Client, cfg config.Config) Fetcher {
+		return steam.NewReviewFetcher(http, cfg)
+	},
+	StoreTrustpilot: func(http httpx.Client, cfg config.Config) Fetcher {
+		return webreviews.NewTrustpilotFetcher(http, cfg)
+	},
+	StoreMicrosoft: func(http httpx.Client, cfg config.Config) Fetcher {
+		return msstore.NewReviewFetcher(http, cfg)
+	},
+	StoreGalaxy: func(http httpx.Client, cfg config.Config) Fetcher {
+		return galaxystore.NewReviewFetcher(http, cfg)
+	},
+}
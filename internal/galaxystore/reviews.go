@@ -0,0 +1,177 @@
+package galaxystore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/appstore"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/pager"
+
+	"github.com/quiby-ai/common/pkg/httpx"
+)
+
+const (
+	dateLayout  = "2006-01-02T15:04:05Z"
+	galaxyDate  = "2006.01.02 15:04:05"
+	galaxyShort = "2006.01.02"
+)
+
+type Review struct {
+	ID        string `json:"commentID"`
+	Rating    string `json:"ratingValue"`
+	Text      string `json:"commentText"`
+	Created   string `json:"createDate"`
+	Updated   string `json:"updateDate,omitempty"`
+	Version   string `json:"productVersion,omitempty"`
+	Reply     string `json:"sellerCommentText,omitempty"`
+	ReplyDate string `json:"sellerCommentDate,omitempty"`
+}
+
+type ReviewsResponse struct {
+	CommentList []Review `json:"commentList"`
+	TotalCount  int      `json:"totalCount"`
+}
+
+// ReviewFetcher pages through Samsung Galaxy Store reviews. The app ID is
+// the Galaxy Store content ID (e.g. 000005403424) and the country selects
+// the store the reviews are read from.
+type ReviewFetcher struct {
+	http httpx.Client
+	cfg  config.GalaxyStoreConfig
+}
+
+func NewReviewFetcher(http httpx.Client, cfg config.Config) *ReviewFetcher {
+	return &ReviewFetcher{http: http, cfg: cfg.GalaxyStore}
+}
+
+// FetchReviews fetches the reviews starting at the 1-based position start.
+func (r *ReviewFetcher) FetchReviews(ctx context.Context, country, appID string, start int) (*ReviewsResponse, error) {
+	timer := logger.StartTimer()
+	requestURL := r.prepareQuery(country, appID, start)
+	headers := map[string]string{
+		"accept": "application/json",
+	}
+
+	logger.Debug(ctx, "Fetching reviews from Galaxy Store", "country", country, "start", start)
+
+	response, err := r.http.DoGET(ctx, requestURL, nil, headers)
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "galaxystore.reviews.request", "failed", timer(), "country", country, "error", "http_request_failed")
+		return nil, fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+
+	if response.Status != 200 {
+		logger.LogEventWithLatency(ctx, "galaxystore.reviews.request", "failed", timer(), "country", country, "status", response.Status)
+		return nil, fmt.Errorf("unexpected status code: %d", response.Status)
+	}
+
+	var reviewsResp ReviewsResponse
+	if err := json.Unmarshal(response.Body, &reviewsResp); err != nil {
+		logger.LogEventWithLatency(ctx, "galaxystore.reviews.request", "failed", timer(), "country", country, "error", "json_parse_failed")
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	logger.LogEventWithLatency(ctx, "galaxystore.reviews.request", "success", timer(), "country", country, "reviews_count", len(reviewsResp.CommentList))
+	return &reviewsResp, nil
+}
+
+// StreamReviews pages through reviews newest first and hands them to flush in
+// batches of at least window reviews, converted to the App Store review
+// shape. The API pages by position; opts.Offset is the first page, counted
+// from 0.
+func (r *ReviewFetcher) StreamReviews(ctx context.Context, country string, appID string, opts *appstore.FetchOptions, window int, flush func(context.Context, []appstore.Review) error) (int, error) {
+	first := 1
+	if opts != nil && opts.Offset > 0 {
+		first = opts.Offset*r.cfg.Limit + 1
+	}
+
+	fetch := func(ctx context.Context, cursor string) ([]appstore.Review, string, error) {
+		start, err := strconv.Atoi(cursor)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid start cursor %q: %w", cursor, err)
+		}
+		reviewsResp, err := r.FetchReviews(ctx, country, appID, start)
+		if err != nil {
+			return nil, "", err
+		}
+		reviews := make([]appstore.Review, 0, len(reviewsResp.CommentList))
+		for _, review := range reviewsResp.CommentList {
+			converted, err := toAppStoreReview(review)
+			if err != nil {
+				logger.Warn(ctx, "Skipping malformed Galaxy Store review", "review_id", review.ID, "error", err.Error())
+				continue
+			}
+			reviews = append(reviews, converted)
+		}
+		next := ""
+		if len(reviewsResp.CommentList) > 0 && start+len(reviewsResp.CommentList) <= reviewsResp.TotalCount {
+			next = strconv.Itoa(start + len(reviewsResp.CommentList))
+		}
+		return reviews, next, nil
+	}
+
+	return pager.Stream(ctx, strconv.Itoa(first), opts, window, fetch, flush)
+}
+
+// parseDate reads the store's dates, which come with or without a time.
+func parseDate(value string) (time.Time, error) {
+	if date, err := time.Parse(galaxyDate, value); err == nil {
+		return date, nil
+	}
+	return time.Parse(galaxyShort, value)
+}
+
+func toAppStoreReview(review Review) (appstore.Review, error) {
+	rating, err := strconv.ParseFloat(review.Rating, 64)
+	if err != nil {
+		return appstore.Review{}, fmt.Errorf("invalid rating %q: %w", review.Rating, err)
+	}
+	created, err := parseDate(review.Created)
+	if err != nil {
+		return appstore.Review{}, fmt.Errorf("invalid date %q: %w", review.Created, err)
+	}
+
+	converted := appstore.Review{
+		ID: review.ID,
+		Attributes: appstore.ReviewAttributes{
+			Date:       created.UTC().Format(dateLayout),
+			Rating:     int(rating),
+			Review:     review.Text,
+			AppVersion: review.Version,
+		},
+	}
+	if updated, err := parseDate(review.Updated); err == nil && updated.After(created) {
+		converted.Attributes.IsEdited = true
+		converted.Attributes.Modified = updated.UTC().Format(dateLayout)
+	}
+	if review.Reply != "" {
+		modified := ""
+		if replyDate, err := parseDate(review.ReplyDate); err == nil {
+			modified = replyDate.UTC().Format(dateLayout)
+		}
+		converted.Attributes.DeveloperResponse = &appstore.DeveloperResponse{
+			Body:     review.Reply,
+			Modified: modified,
+		}
+	}
+	return converted, nil
+}
+
+func (r *ReviewFetcher) prepareQuery(country, appID string, start int) string {
+	host := strings.TrimSuffix(r.cfg.APIHost, "/")
+
+	params := url.Values{}
+	params.Set("contentId", appID)
+	params.Set("startNum", strconv.Itoa(start))
+	params.Set("endNum", strconv.Itoa(start+r.cfg.Limit-1))
+	params.Set("countryCode", strings.ToUpper(country))
+
+	return host + "/api/commentList?" + params.Encode()
+}
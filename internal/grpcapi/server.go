@@ -0,0 +1,125 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/quiby-ai/common/pkg/events"
+	ingestorv1 "github.com/quiby-ai/review-ingestor/api/ingestor/v1"
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server exposes ingestion control over gRPC, sharing the IngestService used
+// by the Kafka consumer.
+type Server struct {
+	ingestorv1.UnimplementedIngestorServiceServer
+
+	addr string
+	srv  *grpc.Server
+	svc  *service.IngestService
+}
+
+func NewServer(cfg config.GRPCConfig, svc *service.IngestService) *Server {
+	s := &Server{addr: cfg.Addr, srv: grpc.NewServer(), svc: svc}
+	ingestorv1.RegisterIngestorServiceServer(s.srv, s)
+	return s
+}
+
+// Run serves until ctx is cancelled and then stops the server gracefully.
+func (s *Server) Run(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		logger.LogEvent(ctx, "grpc.server.started", "success", "addr", s.addr)
+		if err := s.srv.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.srv.GracefulStop()
+	return nil
+}
+
+func (s *Server) TriggerIngest(ctx context.Context, in *ingestorv1.TriggerIngestRequest) (*ingestorv1.TriggerIngestResponse, error) {
+	req := service.NewRequest(events.ExtractRequest{
+		AppID:     in.GetAppId(),
+		AppName:   in.GetAppName(),
+		Countries: in.GetCountries(),
+		DateFrom:  in.GetDateFrom(),
+		DateTo:    in.GetDateTo(),
+	}, in.GetStore())
+
+	sagaID, err := s.svc.Trigger(ctx, req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	logger.LogEvent(ctx, "grpc.ingest.triggered", "success", "saga_id", sagaID, "app_id", req.AppID, "store", req.Store)
+	return &ingestorv1.TriggerIngestResponse{SagaId: sagaID}, nil
+}
+
+func (s *Server) GetRunStatus(_ context.Context, in *ingestorv1.GetRunStatusRequest) (*ingestorv1.GetRunStatusResponse, error) {
+	run, ok := s.svc.GetRun(in.GetSagaId())
+	if !ok {
+		return nil, status.Error(codes.NotFound, service.ErrRunNotFound.Error())
+	}
+	return &ingestorv1.GetRunStatusResponse{Run: toProtoRun(run)}, nil
+}
+
+func (s *Server) ListRuns(_ context.Context, in *ingestorv1.ListRunsRequest) (*ingestorv1.ListRunsResponse, error) {
+	runs := s.svc.ListRuns(in.GetAppId())
+	resp := &ingestorv1.ListRunsResponse{Runs: make([]*ingestorv1.Run, 0, len(runs))}
+	for _, run := range runs {
+		resp.Runs = append(resp.Runs, toProtoRun(run))
+	}
+	return resp, nil
+}
+
+func (s *Server) CancelRun(ctx context.Context, in *ingestorv1.CancelRunRequest) (*ingestorv1.CancelRunResponse, error) {
+	err := s.svc.CancelRun(in.GetSagaId())
+	switch {
+	case errors.Is(err, service.ErrRunNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrRunNotActive):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	logger.LogEvent(ctx, "grpc.run.cancelled", "success", "saga_id", in.GetSagaId())
+	return &ingestorv1.CancelRunResponse{}, nil
+}
+
+func toProtoRun(run service.Run) *ingestorv1.Run {
+	out := &ingestorv1.Run{
+		SagaId:    run.SagaID,
+		AppId:     run.AppID,
+		Store:     run.Store,
+		Countries: run.Countries,
+		Status:    run.Status,
+		Error:     run.Error,
+		Country:   run.Country,
+		Fetched:   int64(run.Fetched),
+		StartedAt: timestamppb.New(run.StartedAt),
+	}
+	if !run.FinishedAt.IsZero() {
+		out.FinishedAt = timestamppb.New(run.FinishedAt)
+	}
+	return out
+}
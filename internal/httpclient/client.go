@@ -0,0 +1,230 @@
+package httpclient
+
+import (
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/quiby-ai/common/pkg/httpx"
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/budget"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/useragent"
+)
+
+// ErrBodyTooLarge is returned when a response body, after decompression,
+// exceeds the configured limit.
+var ErrBodyTooLarge = errors.New("response body exceeds size limit")
+
+// Client implements httpx.Client on net/http. It negotiates gzip and brotli
+// itself so the size limit applies to the decoded body, which keeps
+// compression bombs and runaway endpoints from exhausting memory.
+type Client struct {
+	client     *http.Client
+	cfg        config.HTTPConfig
+	userAgents *useragent.Pool
+}
+
+var _ httpx.Client = (*Client)(nil)
+
+func New(cfg config.HTTPConfig) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// Decoding is done by readBody so it can be bounded.
+	transport.DisableCompression = true
+	transport.Proxy = proxyFor
+	return &Client{client: &http.Client{Timeout: cfg.Timeout, Transport: transport}, cfg: cfg, userAgents: useragent.NewPool(cfg.UserAgents)}
+}
+
+type proxyKey struct{}
+
+// WithProxy routes the requests made with the returned context through
+// proxy instead of the proxy configured in the environment.
+func WithProxy(ctx context.Context, proxy *url.URL) context.Context {
+	return context.WithValue(ctx, proxyKey{}, proxy)
+}
+
+// proxyFor picks the proxy of a request: the one set with WithProxy, or
+// the environment's.
+func proxyFor(req *http.Request) (*url.URL, error) {
+	if proxy, ok := req.Context().Value(proxyKey{}).(*url.URL); ok {
+		return proxy, nil
+	}
+	return http.ProxyFromEnvironment(req)
+}
+
+// SetUserAgents replaces the pool requests without a User-Agent header draw
+// from, so a refreshed list reaches every store.
+func (c *Client) SetUserAgents(pool *useragent.Pool) {
+	c.userAgents = pool
+}
+
+// DoGET fetches rawURL with query appended. See Do.
+func (c *Client) DoGET(ctx context.Context, rawURL string, query map[string]string, headers map[string]string) (httpx.Response, error) {
+	return c.Do(ctx, httpx.Request{Method: http.MethodGet, URL: rawURL, Params: query, Headers: headers})
+}
+
+// Do sends req with its Params appended to the URL, retrying with
+// exponential backoff on transport errors and 5xx responses. Other responses
+// are returned as is for the caller to interpret. A request body is read
+// once up front so that retries resend it.
+func (c *Client) Do(ctx context.Context, req httpx.Request) (httpx.Response, error) {
+	requestURL, err := withQuery(req.URL, req.Params)
+	if err != nil {
+		return httpx.Response{}, err
+	}
+	method := cmp.Or(req.Method, http.MethodGet)
+	var body []byte
+	if req.Body != nil {
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return httpx.Response{}, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
+	backoff := c.cfg.BackoffInitial
+	var lastErr error
+	for attempt := 0; attempt <= c.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			budget.From(ctx).Backoff(backoff)
+			select {
+			case <-ctx.Done():
+				return httpx.Response{}, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff = time.Duration(math.Min(float64(backoff*2), float64(c.cfg.BackoffMax)))
+		}
+
+		resp, retry, err := c.send(ctx, method, requestURL, body, req.Headers)
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+		logger.Debug(ctx, "Retrying HTTP request", "attempt", attempt+1, "error", err.Error())
+	}
+	return httpx.Response{}, lastErr
+}
+
+// send performs one request and reports whether a failure is worth retrying.
+func (c *Client) send(ctx context.Context, method, requestURL string, body []byte, headers map[string]string) (httpx.Response, bool, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, bodyReader)
+	if err != nil {
+		return httpx.Response{}, false, err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	if req.Header.Get("User-Agent") == "" {
+		if agent := c.userAgents.Random(); agent != "" {
+			req.Header.Set("User-Agent", agent)
+		}
+	}
+	req.Header.Set("Accept-Encoding", "gzip, br")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		budget.From(ctx).Request(0)
+		return httpx.Response{}, true, err
+	}
+	defer resp.Body.Close()
+	budget.From(ctx).Request(resp.StatusCode)
+
+	if resp.StatusCode >= 500 {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		return httpx.Response{}, true, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	data, err := readBody(resp, int64(c.cfg.MaxBodyBytes))
+	if err != nil {
+		logger.LogEvent(ctx, "http.response.rejected", "failed", "host", req.URL.Host, "error", err.Error())
+		return httpx.Response{}, false, err
+	}
+	budget.From(ctx).Received(len(data))
+	return httpx.Response{Status: resp.StatusCode, Body: data, Headers: resp.Header.Clone(), URL: requestURL}, false, nil
+}
+
+// readBody decodes resp according to its Content-Encoding and reads at most
+// limit bytes of the result. Both the raw and the decoded stream are capped.
+func readBody(resp *http.Response, limit int64) ([]byte, error) {
+	if limit > 0 && resp.ContentLength > limit {
+		return nil, fmt.Errorf("%w: content length %d above %d", ErrBodyTooLarge, resp.ContentLength, limit)
+	}
+
+	body := capped(resp.Body, limit)
+	switch encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))); encoding {
+	case "", "identity":
+	case "gzip":
+		zr, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode gzip body: %w", err)
+		}
+		defer zr.Close()
+		body = capped(zr, limit)
+	case "br":
+		body = capped(brotli.NewReader(body), limit)
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+	return data, nil
+}
+
+// capReader fails with ErrBodyTooLarge once more than n bytes were read.
+type capReader struct {
+	r io.Reader
+	n int64
+}
+
+func capped(r io.Reader, limit int64) io.Reader {
+	if limit <= 0 {
+		return r
+	}
+	return &capReader{r: r, n: limit}
+}
+
+func (c *capReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > c.n+1 {
+		p = p[:c.n+1]
+	}
+	n, err := c.r.Read(p)
+	c.n -= int64(n)
+	if c.n < 0 {
+		return n, ErrBodyTooLarge
+	}
+	return n, err
+}
+
+func withQuery(rawURL string, query map[string]string) (string, error) {
+	if len(query) == 0 {
+		return rawURL, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid url: %w", err)
+	}
+	values := u.Query()
+	for key, value := range query {
+		values.Set(key, value)
+	}
+	u.RawQuery = values.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,78 @@
+package httpclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func response(encoding string, body []byte) *http.Response {
+	header := http.Header{}
+	if encoding != "" {
+		header.Set("Content-Encoding", encoding)
+	}
+	return &http.Response{Header: header, ContentLength: -1, Body: io.NopCloser(bytes.NewReader(body))}
+}
+
+func gzipped(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+func brotlied(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	bw := brotli.NewWriter(&buf)
+	if _, err := bw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	bw.Close()
+	return buf.Bytes()
+}
+
+func TestReadBody(t *testing.T) {
+	small := []byte(`{"data":[]}`)
+	bomb := []byte(strings.Repeat("a", 1<<20))
+
+	tests := []struct {
+		name    string
+		resp    *http.Response
+		want    []byte
+		wantErr error
+	}{
+		{name: "plain", resp: response("", small), want: small},
+		{name: "gzip", resp: response("gzip", gzipped(t, small)), want: small},
+		{name: "brotli", resp: response("br", brotlied(t, small)), want: small},
+		{name: "plain too large", resp: response("", bomb), wantErr: ErrBodyTooLarge},
+		{name: "gzip bomb", resp: response("gzip", gzipped(t, bomb)), wantErr: ErrBodyTooLarge},
+		{name: "brotli bomb", resp: response("br", brotlied(t, bomb)), wantErr: ErrBodyTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readBody(tt.resp, 1024)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package huawei
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/appstore"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/pager"
+
+	"github.com/quiby-ai/common/pkg/httpx"
+)
+
+const (
+	dateLayout  = "2006-01-02T15:04:05Z"
+	huaweiDate  = "2006/01/02 15:04"
+	regionOther = "default"
+)
+
+type Review struct {
+	ID       string `json:"commentId"`
+	Rating   string `json:"rating"`
+	Title    string `json:"title"`
+	Content  string `json:"commentInfo"`
+	OperTime string `json:"operTime"`
+	Version  string `json:"versionName,omitempty"`
+	Reply    *Reply `json:"replyComment,omitempty"`
+}
+
+type Reply struct {
+	Content  string `json:"commentInfo"`
+	OperTime string `json:"operTime"`
+}
+
+type ReviewsResponse struct {
+	List       []Review `json:"list"`
+	TotalPages int      `json:"totalPages"`
+}
+
+// ReviewFetcher pages through Huawei AppGallery reviews. AppGallery serves
+// each region from its own host and requires an interface code, fetched from
+// that host and refreshed when it expires or is rejected.
+type ReviewFetcher struct {
+	http httpx.Client
+	cfg  config.HuaweiConfig
+
+	mu    sync.Mutex
+	codes map[string]interfaceCode
+}
+
+type interfaceCode struct {
+	value     string
+	fetchedAt time.Time
+}
+
+func NewReviewFetcher(http httpx.Client, cfg config.Config) *ReviewFetcher {
+	return &ReviewFetcher{http: http, cfg: cfg.Huawei, codes: make(map[string]interfaceCode)}
+}
+
+// host maps a storefront to the AppGallery region host serving it.
+func (r *ReviewFetcher) host(country string) string {
+	if host, ok := r.cfg.Hosts[strings.ToLower(country)]; ok {
+		return strings.TrimSuffix(host, "/")
+	}
+	return strings.TrimSuffix(r.cfg.Hosts[regionOther], "/")
+}
+
+func (r *ReviewFetcher) interfaceCode(ctx context.Context, host string) (string, error) {
+	r.mu.Lock()
+	code, ok := r.codes[host]
+	r.mu.Unlock()
+	if ok && time.Since(code.fetchedAt) < r.cfg.TokenTTL {
+		return code.value, nil
+	}
+
+	timer := logger.StartTimer()
+	response, err := r.http.DoGET(ctx, host+"/webedge/getInterfaceCode", nil, r.headers(""))
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "huawei.token.extracted", "failed", timer(), "error", "http_request_failed")
+		return "", fmt.Errorf("failed to fetch interface code: %w", err)
+	}
+	if response.Status != 200 {
+		logger.LogEventWithLatency(ctx, "huawei.token.extracted", "failed", timer(), "status", response.Status)
+		return "", fmt.Errorf("unexpected status code fetching interface code: %d", response.Status)
+	}
+
+	value := strings.Trim(strings.TrimSpace(string(response.Body)), `"`)
+	if value == "" {
+		logger.LogEventWithLatency(ctx, "huawei.token.extracted", "failed", timer(), "error", "empty_interface_code")
+		return "", fmt.Errorf("empty interface code")
+	}
+
+	r.mu.Lock()
+	r.codes[host] = interfaceCode{value: value, fetchedAt: time.Now()}
+	r.mu.Unlock()
+
+	logger.LogEventWithLatency(ctx, "huawei.token.extracted", "success", timer())
+	return value, nil
+}
+
+func (r *ReviewFetcher) invalidate(host string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.codes, host)
+}
+
+func (r *ReviewFetcher) FetchReviews(ctx context.Context, country, appID string, page int) (*ReviewsResponse, error) {
+	host := r.host(country)
+	if host == "" {
+		return nil, fmt.Errorf("no AppGallery host configured for country %s", country)
+	}
+
+	for attempt := 0; ; attempt++ {
+		code, err := r.interfaceCode(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		timer := logger.StartTimer()
+		requestURL := r.prepareQuery(host, appID, page)
+
+		logger.Debug(ctx, "Fetching reviews from Huawei AppGallery", "country", country, "page", page)
+
+		response, err := r.http.DoGET(ctx, requestURL, nil, r.headers(code))
+		if err != nil {
+			logger.LogEventWithLatency(ctx, "huawei.reviews.request", "failed", timer(), "country", country, "error", "http_request_failed")
+			return nil, fmt.Errorf("failed to fetch reviews: %w", err)
+		}
+
+		if (response.Status == 401 || response.Status == 403) && attempt == 0 {
+			logger.LogEventWithLatency(ctx, "huawei.reviews.request", "retrying", timer(), "country", country, "status", response.Status)
+			r.invalidate(host)
+			continue
+		}
+
+		if response.Status != 200 {
+			logger.LogEventWithLatency(ctx, "huawei.reviews.request", "failed", timer(), "country", country, "status", response.Status)
+			return nil, fmt.Errorf("unexpected status code: %d", response.Status)
+		}
+
+		var reviewsResp ReviewsResponse
+		if err := json.Unmarshal(response.Body, &reviewsResp); err != nil {
+			logger.LogEventWithLatency(ctx, "huawei.reviews.request", "failed", timer(), "country", country, "error", "json_parse_failed")
+			return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+		}
+
+		logger.LogEventWithLatency(ctx, "huawei.reviews.request", "success", timer(), "country", country, "reviews_count", len(reviewsResp.List))
+		return &reviewsResp, nil
+	}
+}
+
+// StreamReviews pages through reviews and hands them to flush in batches of
+// at least window reviews, converted to the App Store review shape. Pages are
+// 1-based; opts.Offset selects the first page when set.
+func (r *ReviewFetcher) StreamReviews(ctx context.Context, country string, appID string, opts *appstore.FetchOptions, window int, flush func(context.Context, []appstore.Review) error) (int, error) {
+	first := 1
+	if opts != nil && opts.Offset > 0 {
+		first = opts.Offset
+	}
+
+	fetch := func(ctx context.Context, cursor string) ([]appstore.Review, string, error) {
+		page, err := strconv.Atoi(cursor)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid page cursor %q: %w", cursor, err)
+		}
+		reviewsResp, err := r.FetchReviews(ctx, country, appID, page)
+		if err != nil {
+			return nil, "", err
+		}
+		reviews := make([]appstore.Review, 0, len(reviewsResp.List))
+		for _, review := range reviewsResp.List {
+			converted, err := toAppStoreReview(review)
+			if err != nil {
+				logger.Warn(ctx, "Skipping malformed AppGallery review", "review_id", review.ID, "error", err.Error())
+				continue
+			}
+			reviews = append(reviews, converted)
+		}
+		next := ""
+		if page < reviewsResp.TotalPages {
+			next = strconv.Itoa(page + 1)
+		}
+		return reviews, next, nil
+	}
+
+	return pager.Stream(ctx, strconv.Itoa(first), opts, window, fetch, flush)
+}
+
+func toAppStoreReview(review Review) (appstore.Review, error) {
+	rating, err := strconv.ParseFloat(review.Rating, 64)
+	if err != nil {
+		return appstore.Review{}, fmt.Errorf("invalid rating %q: %w", review.Rating, err)
+	}
+	date, err := time.Parse(huaweiDate, review.OperTime)
+	if err != nil {
+		return appstore.Review{}, fmt.Errorf("invalid date %q: %w", review.OperTime, err)
+	}
+
+	converted := appstore.Review{
+		ID: review.ID,
+		Attributes: appstore.ReviewAttributes{
+			Date:       date.UTC().Format(dateLayout),
+			Rating:     int(rating),
+			Review:     review.Content,
+			Title:      review.Title,
+			AppVersion: review.Version,
+		},
+	}
+	if review.Reply != nil && review.Reply.Content != "" {
+		modified := ""
+		if replyDate, err := time.Parse(huaweiDate, review.Reply.OperTime); err == nil {
+			modified = replyDate.UTC().Format(dateLayout)
+		}
+		converted.Attributes.DeveloperResponse = &appstore.DeveloperResponse{
+			Body:     review.Reply.Content,
+			Modified: modified,
+		}
+	}
+	return converted, nil
+}
+
+func (r *ReviewFetcher) prepareQuery(host, appID string, page int) string {
+	params := url.Values{}
+	params.Set("method", "internal.user.commenList3")
+	params.Set("appid", appID)
+	params.Set("reqPageNum", strconv.Itoa(page))
+	params.Set("maxResults", strconv.Itoa(r.cfg.Limit))
+	params.Set("contentType", "app")
+	params.Set("zone", "")
+	params.Set("locale", "en")
+
+	return host + "/uowap/index?" + params.Encode()
+}
+
+func (r *ReviewFetcher) headers(code string) map[string]string {
+	headers := map[string]string{
+		"accept":          "application/json",
+		"accept-language": "en-US,en;q=0.9",
+		"origin":          "https://appgallery.huawei.com",
+		"referer":         "https://appgallery.huawei.com/",
+	}
+	if code != "" {
+		headers["Interface-Code"] = fmt.Sprintf("%s_%d", code, time.Now().UnixMilli())
+	}
+	return headers
+}
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// contextHandler adds the correlation IDs stored in the context to every
+// record, so any code logging through slog with a context gets them.
+type contextHandler struct {
+	slog.Handler
+}
+
+// NewContextHandler wraps h so that records carry the correlation IDs found
+// in the logging context.
+func NewContextHandler(h slog.Handler) slog.Handler {
+	if _, ok := h.(contextHandler); ok {
+		return h
+	}
+	return contextHandler{Handler: h}
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil {
+		r.AddAttrs(contextAttrs(ctx)...)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+func contextAttrs(ctx context.Context) []slog.Attr {
+	var attrs []slog.Attr
+	for _, key := range []contextKey{traceIDKey, messageIDKey, reviewIDKey, appIDKey, sagaIDKey} {
+		if id, ok := ctx.Value(key).(string); ok && id != "" {
+			attrs = append(attrs, slog.String(string(key), id))
+		}
+	}
+	return attrs
+}
@@ -12,6 +12,11 @@ import (
 type Config struct {
 	Level  string `mapstructure:"level"`
 	Format string `mapstructure:"format"`
+	// Service, Version and Instance are attached to every record when set.
+	// Instance defaults to the host name.
+	Service  string `mapstructure:"service"`
+	Version  string `mapstructure:"version"`
+	Instance string `mapstructure:"instance"`
 }
 
 type contextKey string
@@ -24,17 +29,13 @@ const (
 	sagaIDKey    contextKey = "saga_id"
 )
 
+// level is the minimum level of the default logger; SetLevel changes it at
+// runtime.
+var level = new(slog.LevelVar)
+
 // InitLogger sets up slog with JSON output
 func InitLogger(cfg Config) *slog.Logger {
-	level := slog.LevelInfo
-	switch cfg.Level {
-	case "debug":
-		level = slog.LevelDebug
-	case "warn":
-		level = slog.LevelWarn
-	case "error":
-		level = slog.LevelError
-	}
+	SetLevel(cfg.Level)
 
 	opts := &slog.HandlerOptions{Level: level}
 	var handler slog.Handler
@@ -45,11 +46,28 @@ func InitLogger(cfg Config) *slog.Logger {
 		handler = slog.NewTextHandler(os.Stdout, opts)
 	}
 
+	handler = NewContextHandler(handler).WithAttrs(staticAttrs(cfg))
+
 	logger := slog.New(handler)
 	slog.SetDefault(logger)
 	return logger
 }
 
+// SetLevel changes the minimum level logged: debug, info, warn or error.
+// Unknown names select info.
+func SetLevel(name string) {
+	switch name {
+	case "debug":
+		level.Set(slog.LevelDebug)
+	case "warn":
+		level.Set(slog.LevelWarn)
+	case "error":
+		level.Set(slog.LevelError)
+	default:
+		level.Set(slog.LevelInfo)
+	}
+}
+
 // Context helpers
 func WithTraceID(ctx context.Context, id string) context.Context {
 	if id == "" {
@@ -74,29 +92,28 @@ func WithSagaID(ctx context.Context, id string) context.Context {
 	return context.WithValue(ctx, sagaIDKey, id)
 }
 
-// Log helpers that automatically include correlation IDs
-func Log(ctx context.Context, level slog.Level, msg string, args ...any) {
-	logger := slog.Default()
-	attrs := []any{}
-
-	if traceID, ok := ctx.Value(traceIDKey).(string); ok && traceID != "" {
-		attrs = append(attrs, "trace_id", traceID)
+func staticAttrs(cfg Config) []slog.Attr {
+	instance := cfg.Instance
+	if instance == "" {
+		instance, _ = os.Hostname()
 	}
-	if messageID, ok := ctx.Value(messageIDKey).(string); ok && messageID != "" {
-		attrs = append(attrs, "message_id", messageID)
-	}
-	if reviewID, ok := ctx.Value(reviewIDKey).(string); ok && reviewID != "" {
-		attrs = append(attrs, "review_id", reviewID)
+
+	var attrs []slog.Attr
+	if cfg.Service != "" {
+		attrs = append(attrs, slog.String("service", cfg.Service))
 	}
-	if appID, ok := ctx.Value(appIDKey).(string); ok && appID != "" {
-		attrs = append(attrs, "app_id", appID)
+	if cfg.Version != "" {
+		attrs = append(attrs, slog.String("version", cfg.Version))
 	}
-	if sagaID, ok := ctx.Value(sagaIDKey).(string); ok && sagaID != "" {
-		attrs = append(attrs, "saga_id", sagaID)
+	if instance != "" {
+		attrs = append(attrs, slog.String("instance", instance))
 	}
+	return attrs
+}
 
-	attrs = append(attrs, args...)
-	logger.Log(ctx, level, msg, attrs...)
+// Log helpers; correlation IDs are added by the context handler
+func Log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	slog.Default().Log(ctx, level, msg, args...)
 }
 
 func Debug(ctx context.Context, msg string, args ...any) {
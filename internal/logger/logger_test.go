@@ -69,7 +69,7 @@ func TestLoggingWithCorrelationIDs(t *testing.T) {
 	var buf bytes.Buffer
 
 	// Create a logger that writes to buffer
-	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	handler := NewContextHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
 	logger := slog.New(handler)
 	slog.SetDefault(logger)
 
@@ -110,7 +110,7 @@ func TestLoggingWithCorrelationIDs(t *testing.T) {
 func TestLogEvent(t *testing.T) {
 	var buf bytes.Buffer
 
-	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	handler := NewContextHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
 	logger := slog.New(handler)
 	slog.SetDefault(logger)
 
@@ -140,7 +140,7 @@ func TestLogEvent(t *testing.T) {
 func TestLogEventWithLatency(t *testing.T) {
 	var buf bytes.Buffer
 
-	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	handler := NewContextHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
 	logger := slog.New(handler)
 	slog.SetDefault(logger)
 
@@ -177,7 +177,7 @@ func TestStartTimer(t *testing.T) {
 func TestErrorLogging(t *testing.T) {
 	var buf bytes.Buffer
 
-	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	handler := NewContextHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
 	logger := slog.New(handler)
 	slog.SetDefault(logger)
 
@@ -215,3 +215,33 @@ func TestAutoGeneratedTraceID(t *testing.T) {
 		t.Errorf("Generated trace ID should be 36 characters, got %d", len(traceID))
 	}
 }
+
+func TestContextHandlerWithPlainSlog(t *testing.T) {
+	var buf bytes.Buffer
+
+	handler := NewContextHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	logger := slog.New(handler.WithAttrs(staticAttrs(Config{Service: "review-ingestor", Version: "1.2.3", Instance: "pod-1"})))
+
+	ctx := WithSagaID(WithTraceID(context.Background(), "test-trace"), "test-saga")
+
+	// Third-party code logging through slog directly should get the IDs too
+	logger.InfoContext(ctx, "library message")
+
+	var logEntry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &logEntry); err != nil {
+		t.Fatalf("Failed to parse log JSON: %v", err)
+	}
+
+	expected := map[string]string{
+		"trace_id": "test-trace",
+		"saga_id":  "test-saga",
+		"service":  "review-ingestor",
+		"version":  "1.2.3",
+		"instance": "pod-1",
+	}
+	for field, want := range expected {
+		if logEntry[field] != want {
+			t.Errorf("Expected %s '%s', got '%v'", field, want, logEntry[field])
+		}
+	}
+}
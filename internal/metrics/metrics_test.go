@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExposition(t *testing.T) {
+	counter := &CounterVec{name: "writes_total", help: "Writes.", labels: []string{"outcome"}, values: make(map[string]float64)}
+	counter.Inc("inserted")
+	counter.Inc("inserted")
+	counter.Add(3, `dup"licate`)
+
+	gauge := &GaugeVec{name: "queue_depth", help: "Depth.", labels: []string{"store"}, values: make(map[string]float64)}
+	gauge.Add(3, "appstore")
+	gauge.Add(-1, "appstore")
+	gauge.Set(5, "steam")
+
+	histogram := &HistogramVec{name: "latency_seconds", help: "Latency.", buckets: []float64{0.1, 1}, labels: []string{"op"}, series: make(map[string]*histogram)}
+	histogram.Observe(0.05, "insert")
+	histogram.Observe(0.5, "insert")
+	histogram.Observe(2, "insert")
+
+	registry := &Registry{}
+	registry.register(counter)
+	registry.register(gauge)
+	registry.register(histogram)
+
+	var out strings.Builder
+	registry.Write(&out)
+
+	want := `# HELP writes_total Writes.
+# TYPE writes_total counter
+writes_total{outcome="dup\"licate"} 3
+writes_total{outcome="inserted"} 2
+# HELP queue_depth Depth.
+# TYPE queue_depth gauge
+queue_depth{store="appstore"} 2
+queue_depth{store="steam"} 5
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{op="insert",le="0.1"} 1
+latency_seconds_bucket{op="insert",le="1"} 2
+latency_seconds_bucket{op="insert",le="+Inf"} 3
+latency_seconds_sum{op="insert"} 2.55
+latency_seconds_count{op="insert"} 3
+`
+	if out.String() != want {
+		t.Errorf("exposition =\n%s\nwant\n%s", out.String(), want)
+	}
+}
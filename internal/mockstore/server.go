@@ -0,0 +1,168 @@
+// Package mockstore serves deterministic App Store landing pages, review
+// pages and lookups so the pipeline can run locally without contacting Apple.
+package mockstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/appstore"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+// newestReview is the date of the first review of every storefront; older
+// reviews follow every reviewInterval.
+var newestReview = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+const reviewInterval = 6 * time.Hour
+
+// mockBundlePrefix precedes the app ID in the bundle ID of every mock app, so
+// bundle lookups resolve too.
+const mockBundlePrefix = "com.mockstore.app"
+
+// Server is the mock App Store.
+type Server struct {
+	srv *http.Server
+	cfg config.MockStoreConfig
+}
+
+func NewServer(cfg config.MockStoreConfig) *Server {
+	s := &Server{cfg: cfg}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{country}/app/{slug}/{id}", s.handleLanding)
+	mux.HandleFunc("GET /v1/catalog/{country}/apps/{app_id}/reviews", s.handleReviews)
+	mux.HandleFunc("GET /lookup", s.handleLookup)
+
+	s.srv = &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// Handler returns the mock store's routes, e.g. for an httptest server.
+func (s *Server) Handler() http.Handler {
+	return s.srv.Handler
+}
+
+// Run serves until ctx is cancelled and then shuts the server down.
+func (s *Server) Run(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		logger.LogEvent(ctx, "mockstore.server.started", "success", "addr", s.srv.Addr)
+		if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.srv.Shutdown(shutdownCtx)
+}
+
+// handleLanding serves a landing page carrying the mock bearer token the way
+// apps.apple.com embeds it.
+func (s *Server) handleLanding(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.PathValue("id"), "id") {
+		http.NotFound(w, r)
+		return
+	}
+	environment := url.QueryEscape(fmt.Sprintf(`{"MEDIA_API":{"token":%q}}`, s.cfg.Token))
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, `<!DOCTYPE html><html><head><meta name="web-experience-app/config/environment" content="%s"><title>%s</title></head><body></body></html>`,
+		environment, r.PathValue("slug"))
+}
+
+func (s *Server) handleReviews(w http.ResponseWriter, r *http.Request) {
+	if strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ") != s.cfg.Token {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"errors": []map[string]string{{"status": "401", "title": "Unauthorized"}}})
+		return
+	}
+
+	country, appID := r.PathValue("country"), r.PathValue("app_id")
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	page := appstore.ReviewsResponse{Data: Reviews(appID, country, offset, limit, s.cfg.ReviewsPerStorefront)}
+	if next := offset + limit; next < s.cfg.ReviewsPerStorefront {
+		page.Next = fmt.Sprintf("%s?offset=%d", r.URL.Path, next)
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) handleLookup(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	appID, country := query.Get("id"), query.Get("country")
+	if bundleID := query.Get("bundleId"); bundleID != "" {
+		appID = strings.TrimPrefix(bundleID, mockBundlePrefix)
+	}
+	trackID, err := strconv.ParseInt(appID, 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusOK, map[string]any{"resultCount": 0, "results": []any{}})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"resultCount": 1,
+		"results": []map[string]any{{
+			"trackId":      trackID,
+			"trackName":    "Mock App " + appID,
+			"trackViewUrl": fmt.Sprintf("http://%s/%s/app/mock-app-%s/id%s", r.Host, country, appID, appID),
+			"bundleId":     mockBundlePrefix + appID,
+		}},
+	})
+}
+
+// Reviews returns reviews [offset, offset+limit) of the total reviews the
+// mock holds for appID in country. The same arguments always give the same
+// reviews.
+func Reviews(appID, country string, offset, limit, total int) []appstore.Review {
+	reviews := make([]appstore.Review, 0, limit)
+	for i := offset; i < offset+limit && i < total; i++ {
+		seed := hash(appID, country, i)
+		reviews = append(reviews, appstore.Review{
+			ID: strconv.FormatUint(seed%1e10, 10),
+			Attributes: appstore.ReviewAttributes{
+				Date:       newestReview.Add(-time.Duration(i) * reviewInterval).Format(time.RFC3339),
+				Rating:     int(seed%5) + 1,
+				Title:      fmt.Sprintf("Review %d", i+1),
+				Review:     fmt.Sprintf("Mock review %d of app %s in %s.", i+1, appID, strings.ToUpper(country)),
+				Territory:  strings.ToUpper(country),
+				AppVersion: fmt.Sprintf("1.%d.0", (total-i)/50),
+			},
+		})
+	}
+	return reviews
+}
+
+func hash(appID, country string, i int) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s/%s/%d", appID, country, i)
+	return h.Sum64()
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,25 @@
+package mockstore
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReviewsAreDeterministic(t *testing.T) {
+	first := Reviews("284882215", "us", 0, 20, 30)
+	if !reflect.DeepEqual(first, Reviews("284882215", "us", 0, 20, 30)) {
+		t.Fatal("Reviews() differs between calls")
+	}
+	if len(first) != 20 {
+		t.Fatalf("len(Reviews()) = %d, want 20", len(first))
+	}
+	if last := Reviews("284882215", "us", 20, 20, 30); len(last) != 10 {
+		t.Errorf("len(last page) = %d, want 10", len(last))
+	}
+	if first[0].ID == Reviews("284882215", "gb", 0, 1, 30)[0].ID {
+		t.Error("storefronts share review IDs")
+	}
+	if first[0].Attributes.Date <= first[1].Attributes.Date {
+		t.Errorf("reviews not newest first: %s then %s", first[0].Attributes.Date, first[1].Attributes.Date)
+	}
+}
@@ -0,0 +1,180 @@
+package msstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/appstore"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/pager"
+
+	"github.com/quiby-ai/common/pkg/httpx"
+)
+
+const dateLayout = "2006-01-02T15:04:05Z"
+
+type Review struct {
+	ID             string `json:"ReviewId"`
+	Rating         int    `json:"Rating"`
+	Title          string `json:"Title"`
+	Text           string `json:"ReviewText"`
+	Submitted      string `json:"SubmittedDateTimeUtc"`
+	Updated        string `json:"UpdatedDateTimeUtc,omitempty"`
+	IsRevised      bool   `json:"IsRevised,omitempty"`
+	ProductVersion string `json:"ProductVersion,omitempty"`
+	Market         string `json:"Market,omitempty"`
+	Locale         string `json:"Locale,omitempty"`
+	Response       string `json:"ResponseText,omitempty"`
+	ResponseDate   string `json:"ResponseDateTimeUtc,omitempty"`
+}
+
+type ReviewsResponse struct {
+	Payload struct {
+		Reviews []Review `json:"Reviews"`
+	} `json:"Payload"`
+}
+
+// ReviewFetcher pages through Microsoft Store ratings and reviews of a
+// product, for apps distributed on Windows. The app ID is the Store product
+// ID (e.g. 9WZDNCRFJ3TJ) and the country selects the market.
+type ReviewFetcher struct {
+	http httpx.Client
+	cfg  config.MicrosoftStoreConfig
+}
+
+func NewReviewFetcher(http httpx.Client, cfg config.Config) *ReviewFetcher {
+	return &ReviewFetcher{http: http, cfg: cfg.MicrosoftStore}
+}
+
+func (r *ReviewFetcher) FetchReviews(ctx context.Context, country, appID string, skip int) (*ReviewsResponse, error) {
+	timer := logger.StartTimer()
+	requestURL := r.prepareQuery(country, appID, skip)
+	headers := map[string]string{
+		"accept": "application/json",
+	}
+
+	logger.Debug(ctx, "Fetching reviews from Microsoft Store", "country", country, "skip", skip)
+
+	response, err := r.http.DoGET(ctx, requestURL, nil, headers)
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "msstore.reviews.request", "failed", timer(), "country", country, "error", "http_request_failed")
+		return nil, fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+
+	if response.Status == 404 {
+		logger.LogEventWithLatency(ctx, "msstore.reviews.request", "failed", timer(), "country", country, "status", 404)
+		return nil, fmt.Errorf("product %s not found or not available in market %s", appID, country)
+	}
+
+	if response.Status != 200 {
+		logger.LogEventWithLatency(ctx, "msstore.reviews.request", "failed", timer(), "country", country, "status", response.Status)
+		return nil, fmt.Errorf("unexpected status code: %d", response.Status)
+	}
+
+	var reviewsResp ReviewsResponse
+	if err := json.Unmarshal(response.Body, &reviewsResp); err != nil {
+		logger.LogEventWithLatency(ctx, "msstore.reviews.request", "failed", timer(), "country", country, "error", "json_parse_failed")
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	logger.LogEventWithLatency(ctx, "msstore.reviews.request", "success", timer(), "country", country, "reviews_count", len(reviewsResp.Payload.Reviews))
+	return &reviewsResp, nil
+}
+
+// StreamReviews pages through reviews newest first and hands them to flush in
+// batches of at least window reviews, converted to the App Store review
+// shape. The API pages by item offset; opts.Offset is the first page, counted
+// from 0.
+func (r *ReviewFetcher) StreamReviews(ctx context.Context, country string, appID string, opts *appstore.FetchOptions, window int, flush func(context.Context, []appstore.Review) error) (int, error) {
+	first := 0
+	if opts != nil && opts.Offset > 0 {
+		first = opts.Offset * r.cfg.Limit
+	}
+
+	fetch := func(ctx context.Context, cursor string) ([]appstore.Review, string, error) {
+		skip, err := strconv.Atoi(cursor)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid skip cursor %q: %w", cursor, err)
+		}
+		reviewsResp, err := r.FetchReviews(ctx, country, appID, skip)
+		if err != nil {
+			return nil, "", err
+		}
+		list := reviewsResp.Payload.Reviews
+		reviews := make([]appstore.Review, 0, len(list))
+		for _, review := range list {
+			converted, err := toAppStoreReview(review)
+			if err != nil {
+				logger.Warn(ctx, "Skipping malformed Microsoft Store review", "review_id", review.ID, "error", err.Error())
+				continue
+			}
+			reviews = append(reviews, converted)
+		}
+		// A short page is the last one.
+		next := ""
+		if len(list) >= r.cfg.Limit {
+			next = strconv.Itoa(skip + len(list))
+		}
+		return reviews, next, nil
+	}
+
+	return pager.Stream(ctx, strconv.Itoa(first), opts, window, fetch, flush)
+}
+
+func toAppStoreReview(review Review) (appstore.Review, error) {
+	submitted, err := time.Parse(time.RFC3339, review.Submitted)
+	if err != nil {
+		return appstore.Review{}, fmt.Errorf("invalid date %q: %w", review.Submitted, err)
+	}
+
+	language, _, _ := strings.Cut(review.Locale, "-")
+	converted := appstore.Review{
+		ID: review.ID,
+		Attributes: appstore.ReviewAttributes{
+			Date:       submitted.UTC().Format(dateLayout),
+			Rating:     review.Rating,
+			Review:     review.Text,
+			Title:      review.Title,
+			Territory:  strings.ToLower(review.Market),
+			AppVersion: review.ProductVersion,
+			Language:   strings.ToLower(language),
+		},
+	}
+	if review.IsRevised {
+		converted.Attributes.IsEdited = true
+		if updated, err := time.Parse(time.RFC3339, review.Updated); err == nil {
+			converted.Attributes.Modified = updated.UTC().Format(dateLayout)
+		}
+	}
+	if review.Response != "" {
+		modified := ""
+		if responded, err := time.Parse(time.RFC3339, review.ResponseDate); err == nil {
+			modified = responded.UTC().Format(dateLayout)
+		}
+		converted.Attributes.DeveloperResponse = &appstore.DeveloperResponse{
+			Body:     review.Response,
+			Modified: modified,
+		}
+	}
+	return converted, nil
+}
+
+func (r *ReviewFetcher) prepareQuery(country, appID string, skip int) string {
+	host := strings.TrimSuffix(r.cfg.APIHost, "/")
+
+	params := url.Values{}
+	params.Set("market", strings.ToUpper(country))
+	params.Set("locale", r.cfg.Locale)
+	params.Set("deviceFamily", r.cfg.DeviceFamily)
+	params.Set("orderBy", "MostRecent")
+	params.Set("pageSize", strconv.Itoa(r.cfg.Limit))
+	params.Set("skipItems", strconv.Itoa(skip))
+
+	return host + "/v8.0/ratings/product/" + url.PathEscape(appID) + "?" + params.Encode()
+}
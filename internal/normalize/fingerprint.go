@@ -0,0 +1,31 @@
+package normalize
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Fingerprint hashes what a review says, its rating, title and body, into a
+// key that stays the same when a store redelivers the review with only
+// cosmetic differences: case, spacing, markup, emoji and compatibility forms
+// such as full-width letters are ignored. A changed fingerprint means the
+// author edited the review.
+func Fingerprint(rating int, title, content string) string {
+	h := sha256.New()
+	h.Write([]byte(strconv.Itoa(rating)))
+	h.Write([]byte{0})
+	h.Write([]byte(fingerprintText(title)))
+	h.Write([]byte{0})
+	h.Write([]byte(fingerprintText(content)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// fingerprintText folds text into the form Fingerprint hashes.
+func fingerprintText(s string) string {
+	s = norm.NFKC.String(PlainText(s))
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
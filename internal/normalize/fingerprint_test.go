@@ -0,0 +1,42 @@
+package normalize
+
+import "testing"
+
+func TestFingerprint(t *testing.T) {
+	base := Fingerprint(4, "Nice app", "Works well offline.\nSyncs fast.")
+
+	same := []struct {
+		name           string
+		title, content string
+	}{
+		{"case", "NICE APP", "works WELL offline.\nSyncs fast."},
+		{"whitespace", "  Nice   app ", "Works well offline.\n\n\nSyncs\tfast."},
+		{"emoji", "Nice app 👍🏽", "Works well offline. 🎉\nSyncs fast."},
+		{"markup", "Nice app", "Works well <b>offline.</b><br/>Syncs fast."},
+		{"full-width", "Ｎｉｃｅ app", "Works well offline.\nSyncs fast."},
+	}
+	for _, tt := range same {
+		if got := Fingerprint(4, tt.title, tt.content); got != base {
+			t.Errorf("%s: fingerprint differs from the original", tt.name)
+		}
+	}
+
+	different := []struct {
+		name           string
+		rating         int
+		title, content string
+	}{
+		{"rating", 2, "Nice app", "Works well offline.\nSyncs fast."},
+		{"body", 4, "Nice app", "Works well offline.\nSyncs slowly."},
+		{"title moved into body", 4, "", "Nice app Works well offline.\nSyncs fast."},
+	}
+	for _, tt := range different {
+		if got := Fingerprint(tt.rating, tt.title, tt.content); got == base {
+			t.Errorf("%s: fingerprint matches the original", tt.name)
+		}
+	}
+
+	if Fingerprint(5, "Отлично", "Всё работает") != Fingerprint(5, "ОТЛИЧНО", "всё  работает") {
+		t.Error("fingerprint is not case-insensitive for Cyrillic text")
+	}
+}
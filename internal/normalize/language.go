@@ -0,0 +1,29 @@
+package normalize
+
+import "strings"
+
+// storefrontLanguages is the main language of reviews written in a
+// storefront, for stores that do not report the language of each review.
+// Multilingual storefronts without a clear majority are left out.
+var storefrontLanguages = map[string]string{
+	"us": "en", "gb": "en", "au": "en", "nz": "en", "ie": "en", "za": "en", "sg": "en", "ph": "en", "ng": "en", "ke": "en", "gh": "en",
+	"de": "de", "at": "de",
+	"fr": "fr", "lu": "fr", "sn": "fr", "ci": "fr",
+	"es": "es", "mx": "es", "ar": "es", "co": "es", "cl": "es", "pe": "es", "ve": "es", "ec": "es", "gt": "es", "cr": "es", "pa": "es", "do": "es", "uy": "es", "py": "es", "bo": "es", "sv": "es", "hn": "es", "ni": "es",
+	"br": "pt", "pt": "pt", "ao": "pt", "mz": "pt",
+	"it": "it", "nl": "nl", "ru": "ru", "by": "ru", "kz": "ru", "ua": "uk", "pl": "pl", "tr": "tr",
+	"se": "sv", "no": "no", "dk": "da", "fi": "fi", "gr": "el", "hu": "hu", "ro": "ro", "lt": "lt", "cz": "cs", "sk": "sk", "bg": "bg",
+	"jp": "ja", "kr": "ko", "cn": "zh", "tw": "zh", "hk": "zh", "th": "th", "vn": "vi", "id": "id", "np": "ne",
+	"sa": "ar", "ae": "ar", "eg": "ar", "jo": "ar", "kw": "ar", "qa": "ar", "bh": "ar", "om": "ar", "lb": "ar", "ma": "ar", "dz": "ar", "tn": "ar",
+	"il": "he",
+}
+
+// Language returns the ISO 639-1 code of a review's language: the one the
+// store reported, when it did, or otherwise the main language of the
+// storefront it was fetched from. It returns "" when neither is known.
+func Language(reported, country string) string {
+	if reported = strings.ToLower(strings.TrimSpace(reported)); reported != "" {
+		return reported
+	}
+	return storefrontLanguages[strings.ToLower(country)]
+}
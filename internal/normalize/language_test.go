@@ -0,0 +1,20 @@
+package normalize
+
+import "testing"
+
+func TestLanguage(t *testing.T) {
+	tests := []struct {
+		reported, country, want string
+	}{
+		{reported: "DE", country: "us", want: "de"},
+		{reported: "", country: "FR", want: "fr"},
+		{reported: "", country: "mx", want: "es"},
+		{reported: "", country: "ch", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := Language(tt.reported, tt.country); got != tt.want {
+			t.Errorf("Language(%q, %q) = %q, want %q", tt.reported, tt.country, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package normalize
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Quality flags stored with reviews that downstream NLP should skip. An empty
+// flag means the review passed every check.
+const (
+	QualitySpamPattern = "spam_pattern"
+	QualityEmojiOnly   = "emoji_only"
+	QualityTooShort    = "too_short"
+	QualityRepeated    = "repeated"
+)
+
+// spamPatterns match text that advertises something instead of reviewing the
+// app: links, messenger handles and promo or giveaway offers.
+var spamPatterns = regexp.MustCompile(`(?i)(https?://|www\.|\bt\.me/|\bbit\.ly/|\bwhats\s?app\b|\btelegram\b|\bpromo\s?code\b|\breferral\s?code\b|\bfree\s+(coins|gems|robux|diamonds)\b|\bgiveaway\b)`)
+
+// QualityFlag checks review text, as returned by Text, against the quality
+// heuristics and returns the first flag that applies, or "". Bodies with
+// fewer than minLength letters or digits are too short; zero disables that
+// check. Repetition across reviews is left to QualityKey.
+func QualityFlag(title, content string, minLength int) string {
+	if spamPatterns.MatchString(title) || spamPatterns.MatchString(content) {
+		return QualitySpamPattern
+	}
+
+	letters, symbols := 0, 0
+	for _, r := range content {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			letters++
+		case unicode.IsSpace(r), unicode.IsPunct(r):
+		default:
+			symbols++
+		}
+	}
+	if letters == 0 && symbols > 0 {
+		return QualityEmojiOnly
+	}
+	if minLength > 0 && letters < minLength {
+		return QualityTooShort
+	}
+	return ""
+}
+
+// QualityKey folds a review body into the key used to detect the same text
+// posted across reviews: case, punctuation and spacing are ignored. Bodies
+// too short to tell copies from coincidence get an empty key.
+func QualityKey(content string) string {
+	var b strings.Builder
+	b.Grow(len(content))
+	for _, r := range strings.ToLower(content) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	if utf8.RuneCountInString(b.String()) < minRepeatedLength {
+		return ""
+	}
+	return b.String()
+}
+
+// minRepeatedLength keeps short stock phrases such as "great app" from being
+// flagged as repeated.
+const minRepeatedLength = 20
@@ -0,0 +1,68 @@
+package normalize
+
+import "testing"
+
+func TestQualityFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		title   string
+		content string
+		want    string
+	}{
+		{
+			name:    "regular review",
+			title:   "Solid",
+			content: "Syncing got much faster after the last update.",
+			want:    "",
+		},
+		{
+			name:    "link in body",
+			title:   "Great",
+			content: "Get free stuff at https://example.com now",
+			want:    QualitySpamPattern,
+		},
+		{
+			name:    "promo code in title",
+			title:   "Use my promo code",
+			content: "Really enjoying the app so far, works well.",
+			want:    QualitySpamPattern,
+		},
+		{
+			name:    "emoji only",
+			title:   "Wow",
+			content: "🔥🔥🔥 👍",
+			want:    QualityEmojiOnly,
+		},
+		{
+			name:    "too short",
+			title:   "Meh",
+			content: "ok!!",
+			want:    QualityTooShort,
+		},
+		{
+			name:    "empty body",
+			title:   "Nice",
+			content: "",
+			want:    QualityTooShort,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := QualityFlag(tt.title, tt.content, 10); got != tt.want {
+				t.Errorf("QualityFlag(%q, %q) = %q, want %q", tt.title, tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQualityKey(t *testing.T) {
+	a := QualityKey("Best app ever, download it NOW!")
+	b := QualityKey("best app ever download it now")
+	if a == "" || a != b {
+		t.Errorf("QualityKey should ignore case and punctuation, got %q and %q", a, b)
+	}
+	if got := QualityKey("Great app!"); got != "" {
+		t.Errorf("QualityKey of a short body = %q, want empty", got)
+	}
+}
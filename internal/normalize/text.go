@@ -0,0 +1,88 @@
+package normalize
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Text prepares review text for storage: invalid UTF-8 and control characters
+// (including NUL, which Postgres rejects) are removed, the result is NFC
+// normalized, runs of spaces collapse to one, and at most one blank line is
+// kept between paragraphs.
+func Text(s string) string {
+	s = strings.ToValidUTF8(s, "")
+	s = norm.NFC.String(s)
+
+	var b strings.Builder
+	b.Grow(len(s))
+
+	pendingSpace := false
+	newlines := 0
+	for _, r := range s {
+		switch {
+		case r == '\n':
+			pendingSpace = false
+			newlines++
+			continue
+		case r == '\r':
+			continue
+		case unicode.IsSpace(r):
+			pendingSpace = true
+			continue
+		case unicode.IsControl(r), r == unicode.ReplacementChar:
+			continue
+		}
+
+		if b.Len() > 0 {
+			switch {
+			case newlines > 0:
+				b.WriteString(strings.Repeat("\n", min(newlines, 2)))
+			case pendingSpace:
+				b.WriteByte(' ')
+			}
+		}
+		pendingSpace = false
+		newlines = 0
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// markupTags matches HTML-like tags such as <br/> or </b>, but not text like
+// "<3".
+var markupTags = regexp.MustCompile(`</?[A-Za-z][^<>]*>`)
+
+// PlainText derives the search and embedding input from review text: markup
+// tags are dropped, HTML entities decoded and emoji (with their modifiers,
+// joiners and variation selectors) removed before the result goes through
+// Text.
+func PlainText(s string) string {
+	s = markupTags.ReplaceAllString(s, " ")
+	s = html.UnescapeString(s)
+	s = strings.Map(func(r rune) rune {
+		if isEmoji(r) {
+			return ' '
+		}
+		return r
+	}, s)
+	return Text(s)
+}
+
+func isEmoji(r rune) bool {
+	switch {
+	case unicode.Is(unicode.So, r):
+		return true
+	case r == '\u200d', r == '\u20e3', r >= '\ufe00' && r <= '\ufe0f': // joiner, keycap, variation selectors
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff: // skin tone modifiers
+		return true
+	case r >= 0xe0020 && r <= 0xe007f: // tag sequences of flag emoji
+		return true
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package normalize
+
+import "testing"
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "trims surrounding whitespace",
+			input: "  \t great app \n\n",
+			want:  "great app",
+		},
+		{
+			name:  "collapses inner spaces",
+			input: "too   many\t\tspaces",
+			want:  "too many spaces",
+		},
+		{
+			name:  "keeps at most one blank line",
+			input: "first\r\n\r\n\r\n\r\nsecond\nthird",
+			want:  "first\n\nsecond\nthird",
+		},
+		{
+			name:  "strips null bytes and control characters",
+			input: "bad\x00 by\x07te",
+			want:  "bad byte",
+		},
+		{
+			name:  "drops invalid utf-8",
+			input: "caf\xffé",
+			want:  "café",
+		},
+		{
+			name:  "applies NFC",
+			input: "cafe\u0301",
+			want:  "café",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Text(tt.input); got != tt.want {
+				t.Errorf("Text(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "decodes entities",
+			input: "Tom &amp; Jerry &quot;rocks&quot; &#39;really&#39;",
+			want:  `Tom & Jerry "rocks" 'really'`,
+		},
+		{
+			name:  "drops tags",
+			input: "line one<br/>line <b>two</b>",
+			want:  "line one line two",
+		},
+		{
+			name:  "keeps text hearts",
+			input: "love it <3",
+			want:  "love it <3",
+		},
+		{
+			name:  "strips emoji and modifiers",
+			input: "great 👍🏽 app ❤️ 👨‍👩‍👧 🇺🇸",
+			want:  "great app",
+		},
+		{
+			name:  "keeps paragraphs",
+			input: "first 🔥\n\nsecond",
+			want:  "first\n\nsecond",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PlainText(tt.input); got != tt.want {
+				t.Errorf("PlainText(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package normalize
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultTimestampLayouts are tried when no layouts are configured. RFC 3339
+// also matches fractional seconds and numeric offsets.
+var DefaultTimestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+}
+
+// Timestamp parses a review timestamp with the first matching layout, or
+// DefaultTimestampLayouts when layouts is empty, and returns it in UTC.
+// Timestamps without an offset are taken as UTC.
+func Timestamp(value string, layouts []string) (time.Time, error) {
+	t, err := ZonedTimestamp(value, layouts)
+	return t.UTC(), err
+}
+
+// ZonedTimestamp is Timestamp keeping the offset the value was written with,
+// for callers that record it.
+func ZonedTimestamp(value string, layouts []string) (time.Time, error) {
+	if len(layouts) == 0 {
+		layouts = DefaultTimestampLayouts
+	}
+	value = strings.TrimSpace(value)
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("timestamp %q matches no accepted layout", value)
+}
@@ -0,0 +1,57 @@
+package normalize
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimestamp(t *testing.T) {
+	want := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		value   string
+		layouts []string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "utc", value: "2025-03-01T10:00:00Z", want: want},
+		{name: "offset", value: "2025-03-01T12:00:00+02:00", want: want},
+		{name: "fractional seconds", value: "2025-03-01T10:00:00.250Z", want: want.Add(250 * time.Millisecond)},
+		{name: "no zone", value: "2025-03-01T10:00:00", want: want},
+		{name: "space separated", value: "2025-03-01 10:00:00", want: want},
+		{name: "configured layout", value: "01.03.2025 10:00", layouts: []string{"02.01.2006 15:04"}, want: want},
+		{name: "configured layouts replace defaults", value: "2025-03-01T10:00:00Z", layouts: []string{"02.01.2006 15:04"}, wantErr: true},
+		{name: "garbage", value: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Timestamp(tt.value, tt.layouts)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Timestamp(%q) = %v, want error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Timestamp(%q): %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) || got.Location() != time.UTC {
+				t.Errorf("Timestamp(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestZonedTimestampKeepsOffset(t *testing.T) {
+	got, err := ZonedTimestamp("2025-03-01T12:00:00+02:00", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, offset := got.Zone(); offset != 2*60*60 {
+		t.Errorf("offset = %ds, want 7200s", offset)
+	}
+	if want := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("ZonedTimestamp = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,34 @@
+package objectstore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/quiby-ai/review-ingestor/config"
+)
+
+// NewClient creates an S3 client from the [s3] settings.
+func NewClient(cfg config.S3Config) (*minio.Client, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 client: %w", err)
+	}
+	return client, nil
+}
+
+// ParseURL splits s3://bucket/key into its bucket and key. It reports false
+// for anything that is not an s3:// URL with a bucket.
+func ParseURL(location string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(location, "s3://")
+	if !ok {
+		return "", "", false
+	}
+	bucket, key, _ := strings.Cut(rest, "/")
+	return bucket, key, bucket != ""
+}
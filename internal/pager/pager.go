@@ -0,0 +1,128 @@
+package pager
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/appstore"
+	"github.com/quiby-ai/review-ingestor/internal/normalize"
+)
+
+// PageFunc fetches the page at cursor and returns its reviews in the App
+// Store review shape together with the cursor of the next page, or "" when
+// there are no more pages.
+type PageFunc func(ctx context.Context, cursor string) ([]appstore.Review, string, error)
+
+// Stream pages through a review source starting at cursor and hands the
+// reviews to flush in batches of at least window reviews, applying the After,
+// Before, MaxLimit, Sleep, Jitter and OnPage options the same way the App Store
+// fetcher does. onPage receives the page number counted from opts.Offset. It
+// returns the number of reviews accepted.
+func Stream(ctx context.Context, cursor string, opts *appstore.FetchOptions, window int, fetch PageFunc, flush func(context.Context, []appstore.Review) error) (int, error) {
+	if opts == nil {
+		opts = &appstore.FetchOptions{}
+	}
+
+	var buffered []appstore.Review
+	fetchedCount := 0
+	page := opts.Offset
+
+	flushBuffered := func() error {
+		if len(buffered) == 0 {
+			return nil
+		}
+		if err := flush(ctx, buffered); err != nil {
+			return err
+		}
+		buffered = nil
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fetchedCount, ctx.Err()
+		default:
+		}
+
+		reviews, next, err := fetch(ctx, cursor)
+		if err != nil {
+			return fetchedCount, err
+		}
+
+		newReviewsAdded := false
+		skippedNewer := false
+		limitReached := false
+		for _, review := range reviews {
+			reviewDate, err := normalize.Timestamp(review.Attributes.Date, nil)
+			if err != nil {
+				continue
+			}
+
+			if opts.After != nil && reviewDate.Before(*opts.After) {
+				continue
+			}
+			if opts.Before != nil && reviewDate.After(*opts.Before) {
+				skippedNewer = true
+				continue
+			}
+
+			buffered = append(buffered, review)
+			fetchedCount++
+			newReviewsAdded = true
+
+			if opts.MaxLimit > 0 && fetchedCount >= opts.MaxLimit {
+				limitReached = true
+				break
+			}
+		}
+
+		if opts.OnPage != nil {
+			opts.OnPage(page, fetchedCount)
+		}
+
+		if limitReached {
+			break
+		}
+
+		if len(buffered) >= window {
+			if err := flushBuffered(); err != nil {
+				return fetchedCount, err
+			}
+		}
+
+		if next == "" || next == cursor {
+			break
+		}
+
+		if opts.After != nil && !newReviewsAdded && !skippedNewer {
+			break
+		}
+
+		cursor = next
+		page++
+
+		if delay := Delay(opts); delay > 0 {
+			select {
+			case <-ctx.Done():
+				return fetchedCount, ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+	}
+
+	return fetchedCount, flushBuffered()
+}
+
+// Delay returns the politeness delay to wait before requesting the next page.
+func Delay(opts *appstore.FetchOptions) time.Duration {
+	var delay time.Duration
+	if opts.Sleep != nil {
+		delay = *opts.Sleep
+	}
+	if opts.Jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(opts.Jitter)))
+	}
+	return delay
+}
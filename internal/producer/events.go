@@ -0,0 +1,384 @@
+package producer
+
+import (
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/quiby-ai/common/pkg/events"
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/budget"
+)
+
+// PipelineExtractCancelled is the event type of ExtractCancelled, published
+// to kafka.cancelled_topic when that is not set.
+const PipelineExtractCancelled = "pipeline.extract_reviews.cancelled"
+
+// CountryStats is the ingestion outcome for a single storefront.
+type CountryStats struct {
+	Country string `json:"country"`
+	// Store is set in the completion event of a multi-store request.
+	Store      string `json:"store,omitempty"`
+	Fetched    int    `json:"fetched"`
+	New        int    `json:"new_reviews"`
+	Duplicates int    `json:"duplicates"`
+	Failed     int    `json:"failed"`
+	// Edited counts stored reviews updated because their author edited them.
+	Edited int `json:"edited,omitempty"`
+	// Refreshed counts stored reviews inside ingest.lookback updated because
+	// their text, rating or developer response changed.
+	Refreshed int `json:"refreshed,omitempty"`
+	// CrossCountry counts reviews dropped as copies of a review from
+	// another country of the saga (ingest.cross_country_dedup).
+	CrossCountry int `json:"cross_country_duplicates,omitempty"`
+	// OutOfRange counts reviews skipped because their app version is outside
+	// the requested range.
+	OutOfRange int `json:"out_of_range,omitempty"`
+	// Disappeared counts stored reviews within the fetched window that the
+	// store no longer returned (ingest.deletion_mode).
+	Disappeared int `json:"disappeared,omitempty"`
+	// Ratings counts stored reviews per star rating, and EarliestReviewedAt
+	// and LatestReviewedAt bound their review dates.
+	Ratings            map[int]int `json:"ratings,omitempty"`
+	EarliestReviewedAt *time.Time  `json:"earliest_reviewed_at,omitempty"`
+	LatestReviewedAt   *time.Time  `json:"latest_reviewed_at,omitempty"`
+	// Unavailable is set when the app is not listed in the storefront, which
+	// was then skipped.
+	Unavailable bool `json:"unavailable,omitempty"`
+	// CoolingDown is set when the storefront was skipped because it failed
+	// repeatedly in earlier sagas.
+	CoolingDown bool `json:"cooling_down,omitempty"`
+	// Locked is set when the storefront was skipped because another worker
+	// was still ingesting it after ingest.lock_wait.
+	Locked bool `json:"locked,omitempty"`
+	// RequestBudget is what fetching the storefront cost: requests sent,
+	// rate-limited responses and seconds spent backing off.
+	RequestBudget budget.Usage `json:"request_budget"`
+	// Samples holds a few of the new reviews, newest first, when the
+	// completion payload includes samples.
+	Samples []IngestedReview `json:"samples,omitempty"`
+}
+
+// Observe adds a stored review to the rating distribution and date coverage.
+func (s *CountryStats) Observe(rating int, reviewedAt time.Time) {
+	if s.Ratings == nil {
+		s.Ratings = make(map[int]int)
+	}
+	s.Ratings[rating]++
+	s.EarliestReviewedAt, s.LatestReviewedAt = widen(s.EarliestReviewedAt, s.LatestReviewedAt, &reviewedAt, &reviewedAt)
+}
+
+// widen returns the range covering both [earliest, latest] and [from, to].
+// Nil bounds are treated as empty.
+func widen(earliest, latest, from, to *time.Time) (*time.Time, *time.Time) {
+	if from != nil && (earliest == nil || from.Before(*earliest)) {
+		t := *from
+		earliest = &t
+	}
+	if to != nil && (latest == nil || to.After(*latest)) {
+		t := *to
+		latest = &t
+	}
+	return earliest, latest
+}
+
+// ExtractCompleted extends events.ExtractCompleted with a breakdown of what
+// happened to the fetched reviews. New counts rows inserted by this run, while
+// Duplicates were already stored, so incremental runs report their true delta.
+// Count holds the number of reviews stored for the request, i.e. New plus
+// Duplicates. Ratings, EarliestReviewedAt, LatestReviewedAt and
+// CountriesCovered describe the stored reviews so downstream steps need not
+// query them again.
+type ExtractCompleted struct {
+	events.ExtractCompleted
+	Store              string      `json:"store"`
+	Fetched            int         `json:"fetched"`
+	New                int         `json:"new_reviews"`
+	Duplicates         int         `json:"duplicates"`
+	Failed             int         `json:"failed"`
+	Ratings            map[int]int `json:"ratings"`
+	EarliestReviewedAt *time.Time  `json:"earliest_reviewed_at,omitempty"`
+	LatestReviewedAt   *time.Time  `json:"latest_reviewed_at,omitempty"`
+	CountriesCovered   []string    `json:"countries_covered"`
+	// UnavailableCountries lists the storefronts skipped because the app is
+	// not available there.
+	UnavailableCountries []string `json:"unavailable_countries,omitempty"`
+	// CoolingDownCountries lists the storefronts skipped because they are in
+	// a failure cooldown.
+	CoolingDownCountries []string `json:"cooling_down_countries,omitempty"`
+	// LockedCountries lists the storefronts skipped because another worker
+	// was ingesting them.
+	LockedCountries []string       `json:"locked_countries,omitempty"`
+	Countries       []CountryStats `json:"countries"`
+	// Summary buckets the stored reviews of all countries by rating.
+	Summary RatingSummary `json:"summary"`
+	// RequestBudget sums the request budget of all countries, for capacity
+	// planning.
+	RequestBudget budget.Usage `json:"request_budget"`
+	// Stores breaks the totals down per store for a multi-store request.
+	Stores []StoreTotals `json:"stores,omitempty"`
+}
+
+// StoreTotals is what one store of a multi-store request contributed.
+type StoreTotals struct {
+	Store            string       `json:"store"`
+	AppID            string       `json:"app_id"`
+	Count            int          `json:"count"`
+	Fetched          int          `json:"fetched"`
+	New              int          `json:"new_reviews"`
+	Duplicates       int          `json:"duplicates"`
+	Failed           int          `json:"failed"`
+	CountriesCovered []string     `json:"countries_covered"`
+	RequestBudget    budget.Usage `json:"request_budget"`
+}
+
+// StoreLeg is the outcome of one store of a multi-store request.
+type StoreLeg struct {
+	Store     string
+	AppID     string
+	Countries []CountryStats
+}
+
+// NewMultiStoreCompleted merges the outcomes of the stores of a multi-store
+// request into one completion event. The totals and Countries cover all
+// stores, with every country tagged with its store, and Stores holds the
+// totals of each; Store joins the store names with "+".
+func NewMultiStoreCompleted(req events.ExtractRequest, legs []StoreLeg) ExtractCompleted {
+	var countries []CountryStats
+	stores := make([]string, 0, len(legs))
+	totals := make([]StoreTotals, 0, len(legs))
+	for _, leg := range legs {
+		for _, stats := range leg.Countries {
+			stats.Store = leg.Store
+			countries = append(countries, stats)
+		}
+		stores = append(stores, leg.Store)
+
+		legEvent := NewExtractCompleted(req, leg.Store, leg.Countries)
+		totals = append(totals, StoreTotals{
+			Store:            leg.Store,
+			AppID:            leg.AppID,
+			Count:            legEvent.Count,
+			Fetched:          legEvent.Fetched,
+			New:              legEvent.New,
+			Duplicates:       legEvent.Duplicates,
+			Failed:           legEvent.Failed,
+			CountriesCovered: legEvent.CountriesCovered,
+			RequestBudget:    legEvent.RequestBudget,
+		})
+	}
+
+	event := NewExtractCompleted(req, strings.Join(stores, "+"), countries)
+	event.Stores = totals
+	return event
+}
+
+// NewExtractCompleted aggregates per-country stats into a completion event.
+func NewExtractCompleted(req events.ExtractRequest, store string, countries []CountryStats) ExtractCompleted {
+	event := ExtractCompleted{
+		ExtractCompleted: events.ExtractCompleted{ExtractRequest: req},
+		Store:            store,
+		Ratings:          make(map[int]int),
+		CountriesCovered: []string{},
+		Countries:        countries,
+	}
+	for _, stats := range countries {
+		event.Fetched += stats.Fetched
+		event.New += stats.New
+		event.Duplicates += stats.Duplicates
+		event.Failed += stats.Failed
+		event.RequestBudget = event.RequestBudget.Add(stats.RequestBudget)
+		for rating, count := range stats.Ratings {
+			event.Ratings[rating] += count
+		}
+		event.EarliestReviewedAt, event.LatestReviewedAt = widen(event.EarliestReviewedAt, event.LatestReviewedAt, stats.EarliestReviewedAt, stats.LatestReviewedAt)
+		if stats.New+stats.Duplicates > 0 {
+			event.CountriesCovered = append(event.CountriesCovered, stats.Country)
+		}
+		if stats.Unavailable {
+			event.UnavailableCountries = append(event.UnavailableCountries, stats.Country)
+		}
+		if stats.CoolingDown {
+			event.CoolingDownCountries = append(event.CoolingDownCountries, stats.Country)
+		}
+		if stats.Locked {
+			event.LockedCountries = append(event.LockedCountries, stats.Country)
+		}
+	}
+	sort.Strings(event.CountriesCovered)
+	// Stores of a multi-store request may cover the same country.
+	event.CountriesCovered = slices.Compact(event.CountriesCovered)
+	event.Count = event.New + event.Duplicates
+	event.Summary = Summarize(event.Ratings)
+	return event
+}
+
+// Project shapes the event for ingest.completion_payload: summary drops the
+// per-country and per-store breakdowns, detailed drops review samples, and
+// with_samples keeps everything.
+func (e ExtractCompleted) Project(payload string) ExtractCompleted {
+	switch payload {
+	case config.CompletionPayloadSummary:
+		e.Countries, e.Stores = nil, nil
+	case config.CompletionPayloadWithSamples:
+	default:
+		countries := make([]CountryStats, len(e.Countries))
+		for i, stats := range e.Countries {
+			stats.Samples = nil
+			countries[i] = stats
+		}
+		e.Countries = countries
+	}
+	return e
+}
+
+// RatingSummary buckets reviews NPS style by star rating: 5 stars are
+// promoters, 4 passives and 1 to 3 detractors. NPS is the promoter share
+// minus the detractor share, in percent.
+type RatingSummary struct {
+	Reviews    int     `json:"reviews"`
+	Average    float64 `json:"average"`
+	Promoters  int     `json:"promoters"`
+	Passives   int     `json:"passives"`
+	Detractors int     `json:"detractors"`
+	NPS        float64 `json:"nps"`
+}
+
+// Summarize buckets a rating distribution. Ratings outside 1 to 5 are
+// ignored.
+func Summarize(ratings map[int]int) RatingSummary {
+	var summary RatingSummary
+	total := 0
+	for rating, count := range ratings {
+		switch {
+		case rating == 5:
+			summary.Promoters += count
+		case rating == 4:
+			summary.Passives += count
+		case rating >= 1 && rating <= 3:
+			summary.Detractors += count
+		default:
+			continue
+		}
+		summary.Reviews += count
+		total += rating * count
+	}
+	if summary.Reviews > 0 {
+		summary.Average = float64(total) / float64(summary.Reviews)
+		summary.NPS = float64(summary.Promoters-summary.Detractors) * 100 / float64(summary.Reviews)
+	}
+	return summary
+}
+
+// ExtractCancelled is published when a running saga is cancelled. Countries
+// holds the stats of the finished storefronts and RemainingCountries those
+// that were not finished.
+type ExtractCancelled struct {
+	events.ExtractRequest
+	Store              string         `json:"store"`
+	Fetched            int            `json:"fetched"`
+	New                int            `json:"new_reviews"`
+	Countries          []CountryStats `json:"countries"`
+	RemainingCountries []string       `json:"remaining_countries"`
+}
+
+func NewExtractCancelled(req events.ExtractRequest, store string, countries []CountryStats, remaining []string) ExtractCancelled {
+	event := ExtractCancelled{
+		ExtractRequest:     req,
+		Store:              store,
+		Countries:          countries,
+		RemainingCountries: remaining,
+	}
+	for _, stats := range countries {
+		event.Fetched += stats.Fetched
+		event.New += stats.New
+	}
+	return event
+}
+
+// IngestedReview is a newly stored review as published on the review topic.
+type IngestedReview struct {
+	ReviewID        string     `json:"review_id"`
+	Rating          int        `json:"rating"`
+	Title           string     `json:"title"`
+	Content         string     `json:"content"`
+	ReviewedAt      time.Time  `json:"reviewed_at"`
+	Territory       string     `json:"territory,omitempty"`
+	AppVersion      string     `json:"app_version,omitempty"`
+	ResponseDate    *time.Time `json:"response_date,omitempty"`
+	ResponseContent *string    `json:"response_content,omitempty"`
+}
+
+// ReviewsIngested carries reviews stored for one app and storefront. In
+// review mode it holds a single review, in batch mode a page window.
+type ReviewsIngested struct {
+	AppID   string           `json:"app_id"`
+	Store   string           `json:"store"`
+	Country string           `json:"country"`
+	Reviews []IngestedReview `json:"reviews"`
+}
+
+// ExtractProgress is a heartbeat for a running saga, letting orchestrators
+// tell slow sagas from stuck ones.
+type ExtractProgress struct {
+	AppID          string  `json:"app_id"`
+	Country        string  `json:"country"`
+	Offset         int     `json:"offset"`
+	Fetched        int     `json:"fetched"`
+	ElapsedSeconds float64 `json:"elapsed_seconds"`
+	// Sequence numbers the heartbeats of a saga run from 1.
+	Sequence int `json:"sequence,omitempty"`
+}
+
+// ExtractResume is the internal event that continues a saga suspended while
+// Postgres or Kafka were unavailable. Request is the encoded service request,
+// Countries holds the stats of the storefronts finished before the outage and
+// RemainingCountries those still to be processed.
+type ExtractResume struct {
+	Request            json.RawMessage `json:"request"`
+	Countries          []CountryStats  `json:"countries"`
+	RemainingCountries []string        `json:"remaining_countries"`
+	Reason             string          `json:"reason"`
+	SuspendedAt        time.Time       `json:"suspended_at"`
+}
+
+// RatingAnomalyDetected reports a storefront whose average rating over the
+// recent window dropped by Drop stars or more against the trailing window
+// before it.
+type RatingAnomalyDetected struct {
+	AppID           string    `json:"app_id"`
+	Store           string    `json:"store"`
+	Country         string    `json:"country"`
+	RecentAverage   float64   `json:"recent_average"`
+	RecentCount     int       `json:"recent_count"`
+	TrailingAverage float64   `json:"trailing_average"`
+	TrailingCount   int       `json:"trailing_count"`
+	Drop            float64   `json:"drop"`
+	RecentFrom      time.Time `json:"recent_from"`
+	TrailingFrom    time.Time `json:"trailing_from"`
+	DetectedAt      time.Time `json:"detected_at"`
+}
+
+// WatchlistHit reports a new review matching watchlist patterns. Matches
+// holds the patterns that matched.
+type WatchlistHit struct {
+	AppID   string         `json:"app_id"`
+	Store   string         `json:"store"`
+	Country string         `json:"country"`
+	Matches []string       `json:"matches"`
+	Review  IngestedReview `json:"review"`
+}
+
+// ResponseSLABreached reports a review the developer responded to later
+// than the response SLA allows. ResponseSeconds is the time from the review
+// to the response.
+type ResponseSLABreached struct {
+	AppID            string         `json:"app_id"`
+	Store            string         `json:"store"`
+	Country          string         `json:"country"`
+	ResponseSeconds  float64        `json:"response_seconds"`
+	ThresholdSeconds float64        `json:"threshold_seconds"`
+	Review           IngestedReview `json:"review"`
+}
@@ -0,0 +1,114 @@
+package producer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quiby-ai/common/pkg/events"
+)
+
+func TestNewExtractCompletedAggregatesCoverage(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC) }
+
+	us := CountryStats{Country: "us", New: 2, Duplicates: 1}
+	us.Observe(5, day(10))
+	us.Observe(5, day(4))
+	us.Observe(1, day(12))
+	gb := CountryStats{Country: "gb", New: 1}
+	gb.Observe(3, day(2))
+	empty := CountryStats{Country: "de", Fetched: 4, Failed: 4}
+	unavailable := CountryStats{Country: "cn", Unavailable: true}
+	coolingDown := CountryStats{Country: "ru", CoolingDown: true}
+	locked := CountryStats{Country: "fr", Locked: true}
+
+	event := NewExtractCompleted(events.ExtractRequest{}, "appstore", []CountryStats{us, gb, empty, unavailable, coolingDown, locked})
+
+	if event.Count != 4 {
+		t.Errorf("Count = %d, want 4", event.Count)
+	}
+	want := map[int]int{1: 1, 3: 1, 5: 2}
+	if len(event.Ratings) != len(want) {
+		t.Fatalf("Ratings = %v, want %v", event.Ratings, want)
+	}
+	for rating, count := range want {
+		if event.Ratings[rating] != count {
+			t.Errorf("Ratings[%d] = %d, want %d", rating, event.Ratings[rating], count)
+		}
+	}
+	if event.EarliestReviewedAt == nil || !event.EarliestReviewedAt.Equal(day(2)) {
+		t.Errorf("EarliestReviewedAt = %v, want %v", event.EarliestReviewedAt, day(2))
+	}
+	if event.LatestReviewedAt == nil || !event.LatestReviewedAt.Equal(day(12)) {
+		t.Errorf("LatestReviewedAt = %v, want %v", event.LatestReviewedAt, day(12))
+	}
+	if len(event.CountriesCovered) != 2 || event.CountriesCovered[0] != "gb" || event.CountriesCovered[1] != "us" {
+		t.Errorf("CountriesCovered = %v, want [gb us]", event.CountriesCovered)
+	}
+	if len(event.UnavailableCountries) != 1 || event.UnavailableCountries[0] != "cn" {
+		t.Errorf("UnavailableCountries = %v, want [cn]", event.UnavailableCountries)
+	}
+	if len(event.CoolingDownCountries) != 1 || event.CoolingDownCountries[0] != "ru" {
+		t.Errorf("CoolingDownCountries = %v, want [ru]", event.CoolingDownCountries)
+	}
+	if len(event.LockedCountries) != 1 || event.LockedCountries[0] != "fr" {
+		t.Errorf("LockedCountries = %v, want [fr]", event.LockedCountries)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	got := Summarize(map[int]int{5: 6, 4: 2, 3: 1, 1: 1, 0: 3})
+	want := RatingSummary{Reviews: 10, Average: 4.2, Promoters: 6, Passives: 2, Detractors: 2, NPS: 40}
+	if got != want {
+		t.Errorf("Summarize = %+v, want %+v", got, want)
+	}
+	if empty := Summarize(nil); empty != (RatingSummary{}) {
+		t.Errorf("Summarize(nil) = %+v", empty)
+	}
+}
+
+func TestNewMultiStoreCompleted(t *testing.T) {
+	legs := []StoreLeg{
+		{Store: "appstore", AppID: "389801252", Countries: []CountryStats{{Country: "us", Fetched: 3, New: 2, Duplicates: 1}}},
+		{Store: "googleplay", AppID: "com.instagram.android", Countries: []CountryStats{{Country: "us", Fetched: 5, New: 4}, {Country: "gb", Fetched: 1, Failed: 1}}},
+	}
+
+	event := NewMultiStoreCompleted(events.ExtractRequest{}, legs)
+
+	if event.Store != "appstore+googleplay" {
+		t.Errorf("Store = %q, want appstore+googleplay", event.Store)
+	}
+	if event.Count != 7 || event.Fetched != 9 || event.Failed != 1 {
+		t.Errorf("Count, Fetched, Failed = %d, %d, %d, want 7, 9, 1", event.Count, event.Fetched, event.Failed)
+	}
+	if len(event.CountriesCovered) != 1 || event.CountriesCovered[0] != "us" {
+		t.Errorf("CountriesCovered = %v, want [us]", event.CountriesCovered)
+	}
+	if len(event.Countries) != 3 || event.Countries[0].Store != "appstore" || event.Countries[2].Store != "googleplay" {
+		t.Errorf("Countries = %+v, want tagged with their store", event.Countries)
+	}
+	if len(event.Stores) != 2 {
+		t.Fatalf("Stores = %+v, want 2 entries", event.Stores)
+	}
+	if got := event.Stores[1]; got.AppID != "com.instagram.android" || got.Count != 4 || got.Fetched != 6 || got.Failed != 1 {
+		t.Errorf("Stores[1] = %+v", got)
+	}
+}
+
+func TestProjectCompletionPayload(t *testing.T) {
+	us := CountryStats{Country: "us", New: 1, Samples: []IngestedReview{{ReviewID: "1"}}}
+	event := NewMultiStoreCompleted(events.ExtractRequest{}, []StoreLeg{{Store: "appstore", Countries: []CountryStats{us}}})
+
+	if summary := event.Project("summary"); summary.Countries != nil || summary.Stores != nil || summary.New != 1 {
+		t.Errorf("summary payload = %+v, want totals only", summary)
+	}
+	detailed := event.Project("detailed")
+	if len(detailed.Countries) != 1 || detailed.Countries[0].Samples != nil {
+		t.Errorf("detailed payload countries = %+v, want stats without samples", detailed.Countries)
+	}
+	if event.Countries[0].Samples == nil {
+		t.Error("Project modified the original event's countries")
+	}
+	if samples := event.Project("with_samples"); len(samples.Countries[0].Samples) != 1 {
+		t.Errorf("with_samples payload countries = %+v, want samples kept", samples.Countries)
+	}
+}
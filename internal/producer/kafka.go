@@ -1,20 +1,57 @@
 package producer
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/quiby-ai/common/pkg/events"
 	"github.com/quiby-ai/review-ingestor/config"
 	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/segmentio/kafka-go"
 )
 
 type Producer struct {
-	producer *events.KafkaProducer
+	producer       *events.KafkaProducer
+	brokers        []string
+	completedTopic string
+	cancelledTopic string
+	progressTopic  string
+	reviewTopic    string
+	resumeTopic    string
+	anomalyTopic   string
+	watchlistTopic string
+	slaTopic       string
 }
 
 func NewProducer(cfg config.KafkaConfig) *Producer {
 	producer := events.NewKafkaProducer(cfg.Brokers)
-	return &Producer{producer: producer}
+	return &Producer{
+		producer:       producer,
+		brokers:        cfg.Brokers,
+		completedTopic: cfg.CompletedTopic,
+		cancelledTopic: cmp.Or(cfg.CancelledTopic, PipelineExtractCancelled),
+		progressTopic:  cfg.ProgressTopic,
+		reviewTopic:    cfg.ReviewTopic,
+		resumeTopic:    cfg.ResumeTopic,
+		anomalyTopic:   cfg.AnomalyTopic,
+		watchlistTopic: cfg.WatchlistTopic,
+		slaTopic:       cfg.SLATopic,
+	}
+}
+
+// Ping reports whether any broker accepts connections.
+func (p *Producer) Ping(ctx context.Context) error {
+	var errs []error
+	for _, broker := range p.brokers {
+		conn, err := kafka.DialContext(ctx, "tcp", broker)
+		if err == nil {
+			return conn.Close()
+		}
+		errs = append(errs, err)
+	}
+	return fmt.Errorf("no kafka broker reachable: %w", errors.Join(errs...))
 }
 
 func (p *Producer) Close() error {
@@ -36,8 +73,60 @@ func (p *Producer) PublishEvent(ctx context.Context, key []byte, envelope events
 	return nil
 }
 
-func (p *Producer) BuildEnvelope(event events.ExtractCompleted, sagaID string) events.Envelope[any] {
-	envelope := events.BuildEnvelope(event, events.PipelineExtractCompleted, sagaID)
+// BuildEnvelope wraps a completion event. A saga completes once, so its
+// completion always has the same message ID.
+func (p *Producer) BuildEnvelope(event ExtractCompleted, sagaID string) events.Envelope[any] {
+	envelope := events.BuildEnvelope(event, p.completedTopic, sagaID)
+	envelope.MessageID = MessageID(sagaID, envelope.Type, 0)
+	envelope.Meta.AppID = event.AppID
+
+	return envelope
+}
+
+func (p *Producer) BuildCancelledEnvelope(event ExtractCancelled, sagaID string) events.Envelope[any] {
+	envelope := events.BuildEnvelope(event, p.cancelledTopic, sagaID)
+	envelope.Meta.AppID = event.AppID
+
+	return envelope
+}
+
+// BuildProgressEnvelope wraps a heartbeat, whose message ID follows from its
+// sequence number.
+func (p *Producer) BuildProgressEnvelope(progress ExtractProgress, sagaID string) events.Envelope[any] {
+	envelope := events.BuildEnvelope(progress, p.progressTopic, sagaID)
+	envelope.MessageID = MessageID(sagaID, envelope.Type, progress.Sequence)
+	envelope.Meta.AppID = progress.AppID
+
+	return envelope
+}
+
+func (p *Producer) BuildResumeEnvelope(event ExtractResume, sagaID string) events.Envelope[any] {
+	return events.BuildEnvelope(event, p.resumeTopic, sagaID)
+}
+
+func (p *Producer) BuildReviewsEnvelope(event ReviewsIngested, sagaID string) events.Envelope[any] {
+	envelope := events.BuildEnvelope(event, p.reviewTopic, sagaID)
+	envelope.Meta.AppID = event.AppID
+
+	return envelope
+}
+
+func (p *Producer) BuildAnomalyEnvelope(event RatingAnomalyDetected, sagaID string) events.Envelope[any] {
+	envelope := events.BuildEnvelope(event, p.anomalyTopic, sagaID)
+	envelope.Meta.AppID = event.AppID
+
+	return envelope
+}
+
+func (p *Producer) BuildWatchlistEnvelope(event WatchlistHit, sagaID string) events.Envelope[any] {
+	envelope := events.BuildEnvelope(event, p.watchlistTopic, sagaID)
+	envelope.Meta.AppID = event.AppID
+
+	return envelope
+}
+
+func (p *Producer) BuildSLAEnvelope(event ResponseSLABreached, sagaID string) events.Envelope[any] {
+	envelope := events.BuildEnvelope(event, p.slaTopic, sagaID)
 	envelope.Meta.AppID = event.AppID
 
 	return envelope
@@ -0,0 +1,18 @@
+package producer
+
+import (
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// messageIDSpace namespaces the deterministic message IDs of this service.
+var messageIDSpace = uuid.MustParse("5b0f3c1e-8a4d-4f6e-9c2b-7d1e0a6f4b93")
+
+// MessageID derives the ID of the event of eventType a saga emits with the
+// given sequence number, 0 for events it emits once. Publishing the same
+// event again, e.g. after a retry or from the publish spool, yields the same
+// ID, so consumers can drop the duplicate.
+func MessageID(sagaID, eventType string, sequence int) string {
+	return uuid.NewSHA1(messageIDSpace, []byte(sagaID+"\x00"+eventType+"\x00"+strconv.Itoa(sequence))).String()
+}
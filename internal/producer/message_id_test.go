@@ -0,0 +1,19 @@
+package producer
+
+import "testing"
+
+func TestMessageID(t *testing.T) {
+	id := MessageID("saga-1", "pipeline.extract_reviews.completed", 0)
+	if id != MessageID("saga-1", "pipeline.extract_reviews.completed", 0) {
+		t.Errorf("MessageID is not deterministic")
+	}
+	for _, other := range []string{
+		MessageID("saga-2", "pipeline.extract_reviews.completed", 0),
+		MessageID("saga-1", "pipeline.extract_reviews.progress", 0),
+		MessageID("saga-1", "pipeline.extract_reviews.completed", 1),
+	} {
+		if other == id {
+			t.Errorf("MessageID collided for different inputs: %s", id)
+		}
+	}
+}
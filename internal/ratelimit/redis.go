@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/budget"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+const window = time.Minute
+
+// RedisLimiter shares the App Store request budget and 429 cooldowns between
+// all ingestor replicas pointed at the same Redis instance.
+type RedisLimiter struct {
+	client            *redis.Client
+	prefix            string
+	requestsPerMinute atomic.Int64
+}
+
+func NewRedisLimiter(client *redis.Client, prefix string, requestsPerMinute int) *RedisLimiter {
+	l := &RedisLimiter{client: client, prefix: prefix}
+	l.SetRequestsPerMinute(requestsPerMinute)
+	return l
+}
+
+// SetRequestsPerMinute changes this replica's view of the shared budget.
+// Zero or less removes the budget.
+func (l *RedisLimiter) SetRequestsPerMinute(requestsPerMinute int) {
+	l.requestsPerMinute.Store(int64(requestsPerMinute))
+}
+
+// Wait blocks until an active cooldown has expired and a request slot is
+// available in the current one-minute window.
+func (l *RedisLimiter) Wait(ctx context.Context) error {
+	for {
+		delay, err := l.reserve(ctx)
+		if err != nil {
+			return err
+		}
+		if delay <= 0 {
+			return nil
+		}
+
+		logger.LogEvent(ctx, "ratelimit.wait", "in_progress", "delay_ms", delay.Milliseconds())
+		budget.From(ctx).Backoff(delay)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// Cooldown pauses every replica for d, extending but never shortening an
+// active cooldown.
+func (l *RedisLimiter) Cooldown(ctx context.Context, d time.Duration) error {
+	key := l.cooldownKey()
+	ttl, err := l.client.PTTL(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read cooldown: %w", err)
+	}
+	if ttl >= d {
+		return nil
+	}
+	if err := l.client.Set(ctx, key, "1", d).Err(); err != nil {
+		return fmt.Errorf("failed to set cooldown: %w", err)
+	}
+	logger.LogEvent(ctx, "ratelimit.cooldown", "success", "cooldown_ms", d.Milliseconds())
+	return nil
+}
+
+func (l *RedisLimiter) Close() error {
+	return l.client.Close()
+}
+
+func (l *RedisLimiter) reserve(ctx context.Context) (time.Duration, error) {
+	ttl, err := l.client.PTTL(ctx, l.cooldownKey()).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read cooldown: %w", err)
+	}
+	if ttl > 0 {
+		return ttl, nil
+	}
+
+	budget := l.requestsPerMinute.Load()
+	if budget <= 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	windowStart := now.Truncate(window)
+	key := l.prefix + ":budget:" + strconv.FormatInt(windowStart.Unix(), 10)
+
+	pipe := l.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, 2*window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to reserve request budget: %w", err)
+	}
+
+	if incr.Val() > budget {
+		return windowStart.Add(window).Sub(now), nil
+	}
+	return 0, nil
+}
+
+func (l *RedisLimiter) cooldownKey() string {
+	return l.prefix + ":cooldown"
+}
@@ -0,0 +1,168 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/objectstore"
+	"github.com/quiby-ai/review-ingestor/internal/producer"
+)
+
+// PageSink stores the reviews of one archived page.
+type PageSink interface {
+	ReplayPage(ctx context.Context, appID, country string, body []byte, overwrite bool) (producer.CountryStats, error)
+}
+
+// Replayer feeds archived raw App Store review pages back through the
+// ingestion pipeline. An archive is a local directory or an
+// s3://bucket/prefix URL holding one sub-directory per storefront, each with
+// the raw JSON bodies of its pages as *.json files:
+//
+//	<archive>/us/0000.json
+//	<archive>/us/0001.json
+//	<archive>/gb/0000.json
+type Replayer struct {
+	sink  PageSink
+	s3Cfg config.S3Config
+}
+
+func NewReplayer(sink PageSink, s3Cfg config.S3Config) *Replayer {
+	return &Replayer{sink: sink, s3Cfg: s3Cfg}
+}
+
+// page is an archived page and the storefront it was fetched from.
+type page struct {
+	country string
+	name    string
+	open    func(ctx context.Context) (io.ReadCloser, error)
+}
+
+// Replay stores every page of the archive at src for appID and returns the
+// totals per storefront. Pages that cannot be read or parsed are logged and
+// skipped.
+func (r *Replayer) Replay(ctx context.Context, appID, src string, overwrite bool) ([]producer.CountryStats, error) {
+	timer := logger.StartTimer()
+
+	var pages []page
+	var err error
+	if bucket, prefix, ok := objectstore.ParseURL(src); ok {
+		pages, err = r.s3Pages(ctx, bucket, prefix)
+	} else {
+		pages, err = localPages(src)
+	}
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "replay.archive.listed", "failed", timer(), "src", src)
+		return nil, err
+	}
+	logger.LogEventWithLatency(ctx, "replay.archive.listed", "success", timer(), "src", src, "pages", len(pages))
+
+	var totals []producer.CountryStats
+	byCountry := make(map[string]int)
+	for _, p := range pages {
+		if err := ctx.Err(); err != nil {
+			return totals, err
+		}
+
+		stats, err := r.replayPage(ctx, appID, p, overwrite)
+		if err != nil {
+			logger.LogEvent(ctx, "replay.page.processed", "failed", "country", p.country, "page", p.name, "error", err.Error())
+			continue
+		}
+		logger.LogEvent(ctx, "replay.page.processed", "success", "country", p.country, "page", p.name, "new_reviews", stats.New, "duplicates", stats.Duplicates, "failed", stats.Failed)
+
+		i, ok := byCountry[p.country]
+		if !ok {
+			i = len(totals)
+			byCountry[p.country] = i
+			totals = append(totals, producer.CountryStats{Country: p.country})
+		}
+		totals[i].Fetched += stats.Fetched
+		totals[i].New += stats.New
+		totals[i].Duplicates += stats.Duplicates
+		totals[i].Failed += stats.Failed
+	}
+
+	logger.LogEventWithLatency(ctx, "replay.archive.completed", "success", timer(), "src", src, "countries", len(totals))
+	return totals, nil
+}
+
+func (r *Replayer) replayPage(ctx context.Context, appID string, p page, overwrite bool) (producer.CountryStats, error) {
+	body, err := p.open(ctx)
+	if err != nil {
+		return producer.CountryStats{}, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return producer.CountryStats{}, fmt.Errorf("failed to read page: %w", err)
+	}
+	return r.sink.ReplayPage(ctx, appID, p.country, data, overwrite)
+}
+
+func localPages(dir string) ([]page, error) {
+	var pages []page
+	err := filepath.WalkDir(dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(name, ".json") {
+			return nil
+		}
+		country := strings.ToLower(filepath.Base(filepath.Dir(name)))
+		pages = append(pages, page{
+			country: country,
+			name:    name,
+			open: func(context.Context) (io.ReadCloser, error) {
+				return os.Open(name)
+			},
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archive %s: %w", dir, err)
+	}
+	return pages, nil
+}
+
+func (r *Replayer) s3Pages(ctx context.Context, bucket, prefix string) ([]page, error) {
+	client, err := objectstore.NewClient(r.s3Cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var pages []page
+	for object := range client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("failed to list s3://%s/%s: %w", bucket, prefix, object.Err)
+		}
+		if !strings.HasSuffix(object.Key, ".json") {
+			continue
+		}
+		key := object.Key
+		pages = append(pages, page{
+			country: strings.ToLower(path.Base(path.Dir(key))),
+			name:    key,
+			open: func(ctx context.Context) (io.ReadCloser, error) {
+				obj, err := client.GetObject(ctx, bucket, key, minio.GetObjectOptions{})
+				if err != nil {
+					return nil, fmt.Errorf("failed to download s3://%s/%s: %w", bucket, key, err)
+				}
+				return obj, nil
+			},
+		})
+	}
+
+	sort.Slice(pages, func(i, j int) bool { return pages[i].name < pages[j].name })
+	return pages, nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/producer"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// AggregateStore keeps the per-country rating snapshot of each saga.
+type AggregateStore interface {
+	SaveAggregates(ctx context.Context, aggregates []storage.IngestAggregate) error
+}
+
+// WithAggregateStore enables the rating snapshot saved after each saga.
+func WithAggregateStore(aggregates AggregateStore) Option {
+	return func(s *IngestService) {
+		s.aggregates = aggregates
+	}
+}
+
+// saveAggregates stores the rating buckets of every country that stored
+// reviews in this saga. Failures are logged and never fail the saga.
+func (s *IngestService) saveAggregates(ctx context.Context, req Request, sagaID string, stats []producer.CountryStats) {
+	if s.aggregates == nil {
+		return
+	}
+
+	var aggregates []storage.IngestAggregate
+	for _, country := range stats {
+		summary := producer.Summarize(country.Ratings)
+		if summary.Reviews == 0 {
+			continue
+		}
+		aggregates = append(aggregates, storage.IngestAggregate{
+			SagaID:     sagaID,
+			Store:      req.Store,
+			AppID:      req.AppID,
+			Country:    country.Country,
+			Reviews:    summary.Reviews,
+			Average:    summary.Average,
+			Promoters:  summary.Promoters,
+			Passives:   summary.Passives,
+			Detractors: summary.Detractors,
+			NPS:        summary.NPS,
+		})
+	}
+
+	timer := logger.StartTimer()
+	if err := s.aggregates.SaveAggregates(ctx, aggregates); err != nil {
+		logger.LogEventWithLatency(ctx, "service.aggregates.saved", "failed", timer(), "countries", len(aggregates), "error", err.Error())
+		return
+	}
+	logger.LogEventWithLatency(ctx, "service.aggregates.saved", "success", timer(), "countries", len(aggregates))
+}
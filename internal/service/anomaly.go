@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/producer"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// detectRatingAnomaly compares a storefront's recent average rating with its
+// trailing window once the storefront is ingested, and publishes a
+// RatingAnomalyDetected event when it dropped sharply. Failures are logged
+// and never fail the saga.
+func (s *IngestService) detectRatingAnomaly(ctx context.Context, req Request, sagaID, country string) {
+	cfg := s.anomalyCfg
+	if !cfg.Enabled {
+		return
+	}
+
+	now := time.Now().UTC()
+	recentFrom := now.Add(-cfg.RecentWindow)
+	trailingFrom := recentFrom.Add(-cfg.TrailingWindow)
+	recent, trailing, err := s.repo.RatingAverages(ctx, req.Store, req.AppID, country, trailingFrom, recentFrom, now)
+	if err != nil {
+		logger.LogEvent(ctx, "service.rating_anomaly.checked", "failed", "country", country, "error", err.Error())
+		return
+	}
+	drop, anomalous := ratingDrop(recent, trailing, cfg.MinReviews, cfg.DropThreshold)
+	if !anomalous {
+		logger.LogEvent(ctx, "service.rating_anomaly.checked", "success", "country", country, "anomaly", false,
+			"recent_average", recent.Average, "recent_count", recent.Count, "trailing_average", trailing.Average)
+		return
+	}
+
+	event := producer.RatingAnomalyDetected{
+		AppID:           req.AppID,
+		Store:           req.Store,
+		Country:         country,
+		RecentAverage:   recent.Average,
+		RecentCount:     recent.Count,
+		TrailingAverage: trailing.Average,
+		TrailingCount:   trailing.Count,
+		Drop:            drop,
+		RecentFrom:      recentFrom,
+		TrailingFrom:    trailingFrom,
+		DetectedAt:      now,
+	}
+	if err := s.producer.PublishEvent(ctx, []byte(req.AppID), s.producer.BuildAnomalyEnvelope(event, sagaID)); err != nil {
+		logger.LogEvent(ctx, "service.rating_anomaly.checked", "failed", "country", country, "anomaly", true, "error", err.Error())
+		return
+	}
+	logger.LogEvent(ctx, "service.rating_anomaly.checked", "success", "country", country, "anomaly", true,
+		"recent_average", recent.Average, "recent_count", recent.Count, "trailing_average", trailing.Average, "drop", drop)
+}
+
+// ratingDrop returns how far the recent average fell below the trailing one
+// and whether that is an anomaly: both windows hold at least minReviews
+// reviews and the drop reaches threshold stars.
+func ratingDrop(recent, trailing storage.RatingAverage, minReviews int, threshold float64) (float64, bool) {
+	if recent.Count < minReviews || trailing.Count < minReviews {
+		return 0, false
+	}
+	drop := trailing.Average - recent.Average
+	return drop, drop >= threshold
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+func TestRatingDrop(t *testing.T) {
+	tests := []struct {
+		name      string
+		recent    storage.RatingAverage
+		trailing  storage.RatingAverage
+		wantDrop  float64
+		anomalous bool
+	}{
+		{
+			name:      "sharp drop",
+			recent:    storage.RatingAverage{Average: 3.5, Count: 40},
+			trailing:  storage.RatingAverage{Average: 4.5, Count: 300},
+			wantDrop:  1,
+			anomalous: true,
+		},
+		{
+			name:     "small drop",
+			recent:   storage.RatingAverage{Average: 4.25, Count: 40},
+			trailing: storage.RatingAverage{Average: 4.5, Count: 300},
+			wantDrop: 0.25,
+		},
+		{
+			name:     "too few recent reviews",
+			recent:   storage.RatingAverage{Average: 1, Count: 3},
+			trailing: storage.RatingAverage{Average: 4.5, Count: 300},
+		},
+		{
+			name:     "no trailing history",
+			recent:   storage.RatingAverage{Average: 1, Count: 40},
+			trailing: storage.RatingAverage{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drop, anomalous := ratingDrop(tt.recent, tt.trailing, 20, 0.5)
+			if drop != tt.wantDrop || anomalous != tt.anomalous {
+				t.Errorf("ratingDrop = %v, %v, want %v, %v", drop, anomalous, tt.wantDrop, tt.anomalous)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/appstore"
+)
+
+// flushFunc hands a page window of fetched reviews on for saving.
+type flushFunc = func(context.Context, []appstore.Review) error
+
+// persistQueue runs persist in its own goroutine behind a queue of depth
+// page windows, so fetching goes on while earlier windows are saved but
+// blocks once depth windows are waiting: slow persistence throttles
+// fetching and memory stays bounded. enqueue takes the place of persist and
+// returns the first persist error, which stops fetching. drain waits for the
+// queued windows to be saved and returns that error too.
+func persistQueue(store string, depth int, persist flushFunc) (enqueue flushFunc, drain func() error) {
+	type window struct {
+		ctx     context.Context
+		reviews []appstore.Review
+	}
+	windows := make(chan window, depth)
+	failed := make(chan struct{})
+	done := make(chan struct{})
+	var persistErr error
+
+	go func() {
+		defer close(done)
+		for w := range windows {
+			persistQueueDepth.Add(-1, store)
+			if persistErr != nil {
+				continue
+			}
+			if err := persist(w.ctx, w.reviews); err != nil {
+				persistErr = err
+				close(failed)
+			}
+		}
+	}()
+
+	enqueue = func(ctx context.Context, reviews []appstore.Review) error {
+		select {
+		case <-failed:
+			return persistErr
+		default:
+		}
+
+		start := time.Now()
+		persistQueueDepth.Add(1, store)
+		select {
+		case windows <- window{ctx: ctx, reviews: reviews}:
+			persistQueueWait.Observe(time.Since(start).Seconds(), store)
+			return nil
+		case <-failed:
+			persistQueueDepth.Add(-1, store)
+			return persistErr
+		case <-ctx.Done():
+			persistQueueDepth.Add(-1, store)
+			return ctx.Err()
+		}
+	}
+	drain = func() error {
+		close(windows)
+		<-done
+		return persistErr
+	}
+	return enqueue, drain
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/quiby-ai/review-ingestor/internal/appstore"
+)
+
+func TestPersistQueueSavesInOrder(t *testing.T) {
+	var saved []string
+	enqueue, drain := persistQueue("test", 1, func(_ context.Context, reviews []appstore.Review) error {
+		saved = append(saved, reviews[0].ID)
+		return nil
+	})
+	for _, id := range []string{"a", "b", "c"} {
+		if err := enqueue(context.Background(), []appstore.Review{{ID: id}}); err != nil {
+			t.Fatalf("enqueue(%s): %v", id, err)
+		}
+	}
+	if err := drain(); err != nil {
+		t.Fatalf("drain: %v", err)
+	}
+	if len(saved) != 3 || saved[0] != "a" || saved[2] != "c" {
+		t.Errorf("saved = %v, want [a b c]", saved)
+	}
+}
+
+func TestPersistQueueStopsOnError(t *testing.T) {
+	errSave := errors.New("save failed")
+	enqueue, drain := persistQueue("test", 1, func(context.Context, []appstore.Review) error {
+		return errSave
+	})
+
+	var err error
+	for i := 0; i < 1000 && err == nil; i++ {
+		err = enqueue(context.Background(), []appstore.Review{{ID: "a"}})
+	}
+	if !errors.Is(err, errSave) {
+		t.Errorf("enqueue error = %v, want %v", err, errSave)
+	}
+	if err := drain(); !errors.Is(err, errSave) {
+		t.Errorf("drain error = %v, want %v", err, errSave)
+	}
+}
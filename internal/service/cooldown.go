@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+// StorefrontHealth tracks consecutive failures per storefront and the
+// cooldowns they trigger.
+type StorefrontHealth interface {
+	CooldownUntil(ctx context.Context, store, country string) (time.Time, error)
+	RecordFailure(ctx context.Context, store, country string, threshold int, cooldown time.Duration) (time.Time, error)
+	RecordSuccess(ctx context.Context, store, country string) error
+}
+
+// WithStorefrontHealth enables skipping storefronts that failed
+// ingest.storefront_failure_threshold sagas in a row.
+func WithStorefrontHealth(health StorefrontHealth) Option {
+	return func(s *IngestService) {
+		s.health = health
+	}
+}
+
+func (s *IngestService) cooldownEnabled() bool {
+	return s.health != nil && s.ingestCfg.StorefrontFailureThreshold > 0
+}
+
+// coolingDown reports whether country is in a failure cooldown for req's
+// store. Lookup failures count as not cooling down.
+func (s *IngestService) coolingDown(ctx context.Context, req Request, country string) bool {
+	if !s.cooldownEnabled() {
+		return false
+	}
+	until, err := s.health.CooldownUntil(ctx, req.Store, country)
+	if err != nil {
+		logger.Warn(ctx, "Storefront cooldown check failed, fetching anyway", "country", country, "error", err.Error())
+		return false
+	}
+	return !until.IsZero()
+}
+
+// recordStorefrontOutcome updates the consecutive failure count of country
+// after it was processed.
+func (s *IngestService) recordStorefrontOutcome(ctx context.Context, req Request, country string, failed bool) {
+	if !s.cooldownEnabled() {
+		return
+	}
+	if !failed {
+		if err := s.health.RecordSuccess(ctx, req.Store, country); err != nil {
+			logger.Warn(ctx, "Failed to reset storefront failures", "country", country, "error", err.Error())
+		}
+		return
+	}
+
+	until, err := s.health.RecordFailure(ctx, req.Store, country, s.ingestCfg.StorefrontFailureThreshold, s.ingestCfg.StorefrontCooldown)
+	if err != nil {
+		logger.Warn(ctx, "Failed to record storefront failure", "country", country, "error", err.Error())
+		return
+	}
+	if !until.IsZero() {
+		logger.LogEvent(ctx, "service.storefront.cooldown_started", "success", "store", req.Store, "country", country, "until", until.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/appstore"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/normalize"
+	"github.com/quiby-ai/review-ingestor/internal/producer"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// fetchedWindow records the reviews a storefront returned and the dates they
+// span, for ingest.deletion_mode.
+type fetchedWindow struct {
+	seen     []string
+	from, to time.Time
+}
+
+func (w *fetchedWindow) observe(review appstore.Review, layouts []string) {
+	w.seen = append(w.seen, review.ID)
+	reviewedAt, err := normalize.Timestamp(review.Attributes.Date, layouts)
+	if err != nil {
+		return
+	}
+	if w.from.IsZero() || reviewedAt.Before(w.from) {
+		w.from = reviewedAt
+	}
+	if reviewedAt.After(w.to) {
+		w.to = reviewedAt
+	}
+}
+
+// reconcileDeletions compares the stored reviews of the dates the storefront
+// returned with what it returned, and counts the ones that disappeared in
+// stats. Only the span between the oldest and newest fetched review is
+// checked, so a fetch cut short by max_reviews does not mark older reviews.
+// Failures are logged and leave the saga alone.
+func (s *IngestService) reconcileDeletions(ctx context.Context, req Request, country string, window *fetchedWindow, stats *producer.CountryStats) {
+	if s.ingestCfg.DeletionMode == config.DeletionModeOff || window.from.IsZero() {
+		return
+	}
+
+	deletions := storage.DeletionWindow{
+		Store:   req.Store,
+		AppID:   req.AppID,
+		Country: country,
+		From:    window.from,
+		To:      window.to,
+		Seen:    window.seen,
+	}
+	mark := s.ingestCfg.DeletionMode == config.DeletionModeMark
+	disappeared, err := s.repo.ReconcileDeletions(ctx, deletions, mark)
+	if err != nil {
+		logger.Error(ctx, "Failed to reconcile deleted reviews", err, "country", country)
+		return
+	}
+	if mark {
+		s.queueShadowWrite(ctx, shadowWrite{op: shadowOpDeletions, window: deletions, changed: disappeared})
+	}
+	stats.Disappeared = int(disappeared)
+	if disappeared > 0 {
+		logger.LogEvent(ctx, "service.reviews.disappeared", "success", "country", country, "count", disappeared, "mode", s.ingestCfg.DeletionMode)
+	}
+}
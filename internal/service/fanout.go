@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/producer"
+	"github.com/quiby-ai/review-ingestor/internal/webhook"
+)
+
+// storeLegs collects the outcome of each store of a multi-store saga. ingest
+// hands a finished store to it instead of publishing a completion event.
+type storeLegs struct {
+	parent Request
+	legs   []producer.StoreLeg
+}
+
+type storeLegsKey struct{}
+
+// checkpointRequest is the request a saga checkpoint stores. For a
+// multi-store saga stopped during one of its stores it is the parent request
+// with Stores cut down to that store and the ones after it, and
+// FinishedStores holds the outcome of the stores before; the checkpoint's
+// remaining countries and progress belong to the first store of Stores.
+type checkpointRequest struct {
+	Request
+	FinishedStores []producer.StoreLeg `json:"finished_stores,omitempty"`
+	// Heartbeats is the sequence of the last heartbeat the saga sent.
+	Heartbeats int `json:"heartbeats,omitempty"`
+}
+
+// checkpoint returns the request to checkpoint when leg, one store of the
+// collected saga, stops before it finishes.
+func (c *storeLegs) checkpoint(leg Request) checkpointRequest {
+	if len(c.parent.Stores) == 0 {
+		return checkpointRequest{Request: leg}
+	}
+	parent := c.parent
+	if i := slices.Index(parent.Stores, leg.Store); i >= 0 {
+		parent.Stores = parent.Stores[i:]
+	}
+	// The store's app ID may have been resolved from another identifier.
+	parent.AppIDs = maps.Clone(parent.AppIDs)
+	if parent.AppIDs == nil {
+		parent.AppIDs = make(map[string]string)
+	}
+	parent.AppIDs[leg.Store] = leg.AppID
+	return checkpointRequest{Request: parent, FinishedStores: slices.Clone(c.legs)}
+}
+
+// fanOutResume is where a checkpointed multi-store saga continues: the
+// stores finished before it stopped, and the countries left and finished of
+// the store it stopped in.
+type fanOutResume struct {
+	finished  []producer.StoreLeg
+	remaining []string
+	progress  []producer.CountryStats
+}
+
+// checkStores reports an error when a store req targets has no fetcher.
+func (s *IngestService) checkStores(req Request) error {
+	if len(req.Stores) == 0 {
+		if !s.fetchers.Has(req.Store) {
+			return errUnsupportedStore(req.Store)
+		}
+		return nil
+	}
+	for _, store := range req.Stores {
+		if !s.fetchers.Has(store) {
+			return errUnsupportedStore(store)
+		}
+	}
+	return nil
+}
+
+// fanOut ingests a multi-store request store by store under one saga and
+// publishes a single completion event with the counts of every store. A
+// store that is cancelled, parked or suspended reports itself like a
+// single-store saga and checkpoints the whole request, so resuming it
+// fetches the rest of that store and the stores after it. resumed, when
+// set, continues such a checkpoint.
+func (s *IngestService) fanOut(ctx context.Context, req Request, sagaID string, resumed *fanOutResume, timer func() time.Duration) error {
+	collected := &storeLegs{parent: req}
+	if resumed != nil {
+		collected.legs = resumed.finished
+	}
+	legCtx := context.WithValue(ctx, storeLegsKey{}, collected)
+
+	legs := make([]Request, len(req.Stores))
+	for i, store := range req.Stores {
+		leg, err := s.resolveAppID(ctx, req.ForStore(store))
+		if err != nil {
+			logger.LogEventWithLatency(ctx, "service.ingest.completed", "failed", timer(), "error", "app_id_unresolved", "store", store)
+			return err
+		}
+		legs[i] = leg
+	}
+	s.recordStoreIdentifiers(ctx, legs)
+
+	for i, leg := range legs {
+		store := leg.Store
+		var stats []producer.CountryStats
+		if i == 0 && resumed != nil {
+			leg.Countries, stats = resumed.remaining, resumed.progress
+		} else {
+			var err error
+			leg, err = s.expandCountries(ctx, leg)
+			if err != nil {
+				logger.LogEventWithLatency(ctx, "service.ingest.completed", "failed", timer(), "error", "discovery_failed", "store", store)
+				return err
+			}
+			leg.Countries = s.orderCountries(ctx, leg, leg.Countries)
+		}
+
+		storeTimer := logger.StartTimer()
+		finished := len(collected.legs)
+		if err := s.ingest(legCtx, leg, sagaID, leg.Countries, stats, timer); err != nil {
+			logger.LogEventWithLatency(ctx, "service.store.processed", "failed", storeTimer(), "store", store)
+			return fmt.Errorf("failed to process store %s: %w", store, err)
+		}
+		if len(collected.legs) == finished {
+			logger.LogEventWithLatency(ctx, "service.store.processed", "stopped", storeTimer(), "store", store, "remaining_stores", len(legs)-i-1)
+			return nil
+		}
+		logger.LogEventWithLatency(ctx, "service.store.processed", "success", storeTimer(), "store", store, "app_id", leg.AppID)
+	}
+
+	publishTimer := logger.StartTimer()
+	outputEvent := producer.NewMultiStoreCompleted(req.ExtractRequest, collected.legs)
+	if err := s.publishEvent(ctx, outputEvent, sagaID); err != nil {
+		logger.LogEventWithLatency(ctx, "producer.event.published", "failed", publishTimer())
+		logger.LogEventWithLatency(ctx, "service.ingest.completed", "failed", timer(), "error", "event_publish_failed")
+		return fmt.Errorf("failed to publish prepare reviews event: %w", err)
+	}
+	logger.LogEventWithLatency(ctx, "producer.event.published", "success", publishTimer())
+
+	s.notify(ctx, webhook.Payload{
+		Event:     webhook.EventCompleted,
+		SagaID:    sagaID,
+		AppID:     req.AppID,
+		Store:     outputEvent.Store,
+		Completed: &outputEvent,
+	})
+
+	logger.LogEventWithLatency(ctx, "service.ingest.completed", "success", timer(), "total_reviews", outputEvent.Count, "stores", len(collected.legs),
+		"fetched", outputEvent.Fetched, "new_reviews", outputEvent.New, "duplicates", outputEvent.Duplicates, "failed", outputEvent.Failed,
+		"requests", outputEvent.RequestBudget.Requests, "rate_limited", outputEvent.RequestBudget.RateLimited, "backoff_seconds", outputEvent.RequestBudget.BackoffSeconds)
+	return nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/quiby-ai/common/pkg/events"
+	"github.com/quiby-ai/review-ingestor/internal/producer"
+)
+
+func TestNewCheckpointOfStoreLeg(t *testing.T) {
+	parent := NewRequest(events.ExtractRequest{AppID: "com.example", Countries: []string{"us", "gb"}}, "")
+	parent.Stores = []string{StoreAppStore, StoreGooglePlay, StoreAmazon}
+	finished := producer.StoreLeg{Store: StoreAppStore, AppID: "123", Countries: []producer.CountryStats{{Country: "us", New: 4}}}
+	collected := &storeLegs{parent: parent, legs: []producer.StoreLeg{finished}}
+	ctx := context.WithValue(context.Background(), storeLegsKey{}, collected)
+
+	leg := parent.ForStore(StoreGooglePlay)
+	leg.AppID = "com.example.app"
+	stats := []producer.CountryStats{{Country: "us", New: 2}}
+	s := &IngestService{runs: newRunRegistry()}
+	saga, err := s.newCheckpoint(ctx, leg, "saga-1", []string{"gb"}, stats)
+	if err != nil {
+		t.Fatalf("newCheckpoint: %v", err)
+	}
+
+	var saved checkpointRequest
+	if err := json.Unmarshal(saga.Request, &saved); err != nil {
+		t.Fatalf("decode checkpoint: %v", err)
+	}
+	if want := []string{StoreGooglePlay, StoreAmazon}; !reflect.DeepEqual(saved.Stores, want) {
+		t.Errorf("stores = %v, want %v", saved.Stores, want)
+	}
+	if got := saved.AppIDs[StoreGooglePlay]; got != "com.example.app" {
+		t.Errorf("resolved app ID = %q, want com.example.app", got)
+	}
+	if !reflect.DeepEqual(saved.FinishedStores, []producer.StoreLeg{finished}) {
+		t.Errorf("finished stores = %+v, want %+v", saved.FinishedStores, finished)
+	}
+	if !reflect.DeepEqual(saga.RemainingCountries, []string{"gb"}) || saga.AppID != "com.example.app" {
+		t.Errorf("checkpoint = %+v, want the leg's remaining countries and app ID", saga)
+	}
+	if parent.AppIDs != nil {
+		t.Errorf("parent request modified: %v", parent.AppIDs)
+	}
+}
+
+func TestNewCheckpointOfSingleStore(t *testing.T) {
+	req := NewRequest(events.ExtractRequest{AppID: "123", Countries: []string{"us"}}, "")
+	s := &IngestService{runs: newRunRegistry()}
+	saga, err := s.newCheckpoint(context.Background(), req, "saga-1", []string{"us"}, nil)
+	if err != nil {
+		t.Fatalf("newCheckpoint: %v", err)
+	}
+
+	var saved checkpointRequest
+	if err := json.Unmarshal(saga.Request, &saved); err != nil {
+		t.Fatalf("decode checkpoint: %v", err)
+	}
+	if !reflect.DeepEqual(saved.Request, req) || saved.FinishedStores != nil {
+		t.Errorf("checkpoint request = %+v, want %+v", saved, req)
+	}
+}
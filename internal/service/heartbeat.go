@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/producer"
+)
+
+// sagaProgress tracks where a running saga is, for heartbeats.
+type sagaProgress struct {
+	mu      sync.Mutex
+	started time.Time
+	country string
+	offset  int
+	fetched int
+}
+
+func newSagaProgress() *sagaProgress {
+	return &sagaProgress{started: time.Now()}
+}
+
+func (p *sagaProgress) setCountry(country string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.country = country
+	p.offset = 0
+	p.fetched = 0
+}
+
+func (p *sagaProgress) update(offset, fetched int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.offset = offset
+	p.fetched = fetched
+}
+
+func (p *sagaProgress) snapshot(appID string) producer.ExtractProgress {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return producer.ExtractProgress{
+		AppID:          appID,
+		Country:        p.country,
+		Offset:         p.offset,
+		Fetched:        p.fetched,
+		ElapsedSeconds: time.Since(p.started).Seconds(),
+	}
+}
+
+// startHeartbeat emits a progress heartbeat every HeartbeatInterval until the
+// returned stop function is called. It is a no-op when no interval is set.
+// Heartbeats are numbered per saga, so a later store or a resume of the same
+// saga continues the sequence instead of reusing message IDs.
+func (s *IngestService) startHeartbeat(ctx context.Context, req Request, sagaID string, progress *sagaProgress) func() {
+	if s.ingestCfg.HeartbeatInterval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(s.ingestCfg.HeartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				snapshot := progress.snapshot(req.AppID)
+				snapshot.Sequence = s.runs.nextHeartbeat(sagaID)
+				s.heartbeat(ctx, sagaID, snapshot)
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+func (s *IngestService) heartbeat(ctx context.Context, sagaID string, progress producer.ExtractProgress) {
+	logger.LogEvent(ctx, "service.ingest.heartbeat", "in_progress",
+		"country", progress.Country, "offset", progress.Offset, "fetched", progress.Fetched, "elapsed_seconds", progress.ElapsedSeconds)
+
+	if !s.ingestCfg.PublishHeartbeats {
+		return
+	}
+	envelope := s.producer.BuildProgressEnvelope(progress, sagaID)
+	if err := s.publishOrSpool(ctx, []byte(sagaID), envelope); err != nil {
+		logger.Warn(ctx, "Failed to publish heartbeat", "error", err.Error())
+	}
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/quiby-ai/common/pkg/events"
+)
+
+func TestHeartbeatSequenceSurvivesCheckpoint(t *testing.T) {
+	req := NewRequest(events.ExtractRequest{AppID: "123", Countries: []string{"us", "gb"}}, "")
+	s := &IngestService{runs: newRunRegistry()}
+	s.runs.start("saga-1", req, func(error) {})
+	s.runs.nextHeartbeat("saga-1")
+	s.runs.nextHeartbeat("saga-1")
+
+	saga, err := s.newCheckpoint(context.Background(), req, "saga-1", []string{"gb"}, nil)
+	if err != nil {
+		t.Fatalf("newCheckpoint: %v", err)
+	}
+	var saved checkpointRequest
+	if err := json.Unmarshal(saga.Request, &saved); err != nil {
+		t.Fatalf("decode checkpoint: %v", err)
+	}
+	if saved.Heartbeats != 2 {
+		t.Fatalf("checkpointed heartbeats = %d, want 2", saved.Heartbeats)
+	}
+
+	resumed := &IngestService{runs: newRunRegistry()}
+	resumed.runs.start("saga-1", req, func(error) {})
+	resumed.runs.resumeHeartbeats("saga-1", saved.Heartbeats)
+	if got := resumed.runs.nextHeartbeat("saga-1"); got != 3 {
+		t.Errorf("first heartbeat after resume = %d, want 3", got)
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// PostSaveHook receives the reviews of each page window that were stored for
+// the first time. Hooks run inline in the saga, so slow work such as calls
+// to external services belongs in a background worker fed by the hook.
+type PostSaveHook interface {
+	AfterSave(ctx context.Context, rows []storage.RawReview)
+}
+
+// WithPostSaveHooks registers hooks run after every page window is saved.
+func WithPostSaveHooks(hooks ...PostSaveHook) Option {
+	return func(s *IngestService) {
+		s.hooks = append(s.hooks, hooks...)
+	}
+}
+
+// afterSave hands the reviews a page window stored for the first time to
+// the post-save stages: publishing, watchlist alerts, translation and
+// registered hooks. The app's overrides can switch translation and hooks
+// off.
+func (s *IngestService) afterSave(ctx context.Context, batch *ReviewBatch) {
+	rows := batch.Inserted
+	if len(rows) == 0 {
+		return
+	}
+	s.sampleReviews(batch)
+	s.publishReviews(ctx, batch.SagaID, batch.Request, batch.Country, rows)
+	s.checkWatchlist(ctx, batch.SagaID, batch.Request, batch.Country, rows)
+	s.checkResponseSLA(ctx, batch.SagaID, batch.Request, batch.Country, rows)
+	if batch.settings.translate {
+		s.queueTranslations(ctx, rows)
+	}
+	if !batch.settings.hooks {
+		return
+	}
+	for _, hook := range s.hooks {
+		hook.AfterSave(ctx, rows)
+	}
+}
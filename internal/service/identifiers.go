@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// IdentifierStore maps a product's App Store ID, bundle ID and Play package
+// name onto each other.
+type IdentifierStore interface {
+	FindIdentifiers(ctx context.Context, id string) (*storage.AppIdentifiers, error)
+	SaveIdentifiers(ctx context.Context, ids storage.AppIdentifiers) error
+}
+
+// WithIdentifierStore lets requests name an app by any of its identifiers,
+// e.g. an App Store request by bundle ID. Bundle IDs not mapped yet are
+// resolved through the app lookup set with WithAppLookup.
+func WithIdentifierStore(identifiers IdentifierStore) Option {
+	return func(s *IngestService) {
+		s.identifiers = identifiers
+	}
+}
+
+// resolveAppID replaces the app ID of req with the store-native one when it
+// names the app by another identifier: App Store requests take the numeric
+// app ID and Google Play requests the package name. Other stores and IDs
+// already native are left unchanged.
+func (s *IngestService) resolveAppID(ctx context.Context, req Request) (Request, error) {
+	if s.identifiers == nil {
+		return req, nil
+	}
+	native := isAppleID(req.AppID)
+	if req.Store != StoreAppStore && req.Store != StoreGooglePlay || native == (req.Store == StoreAppStore) {
+		return req, nil
+	}
+
+	timer := logger.StartTimer()
+	ids, err := s.identifiers.FindIdentifiers(ctx, req.AppID)
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "service.app_id.resolved", "failed", timer(), "app_id", req.AppID, "store", req.Store)
+		return req, err
+	}
+
+	resolved := ""
+	switch req.Store {
+	case StoreAppStore:
+		if ids != nil {
+			resolved = ids.AppleID
+		} else if ids, err = s.lookupBundle(ctx, req.AppID); err != nil {
+			logger.LogEventWithLatency(ctx, "service.app_id.resolved", "failed", timer(), "app_id", req.AppID, "store", req.Store)
+			return req, err
+		} else if ids != nil {
+			resolved = ids.AppleID
+		}
+	case StoreGooglePlay:
+		if ids != nil {
+			resolved = ids.PlayPackage
+		}
+	}
+	if resolved == "" {
+		logger.LogEventWithLatency(ctx, "service.app_id.resolved", "failed", timer(), "app_id", req.AppID, "store", req.Store, "error", "unknown_identifier")
+		return req, fmt.Errorf("no %s app ID known for %s", req.Store, req.AppID)
+	}
+
+	logger.LogEventWithLatency(ctx, "service.app_id.resolved", "success", timer(), "app_id", req.AppID, "store", req.Store, "resolved_app_id", resolved)
+	req.AppID = resolved
+	return req, nil
+}
+
+// lookupBundle finds the App Store app with bundleID through the lookup API
+// and records its identifiers. It returns nil when there is none.
+func (s *IngestService) lookupBundle(ctx context.Context, bundleID string) (*storage.AppIdentifiers, error) {
+	if s.lookup == nil {
+		return nil, nil
+	}
+	info, err := s.lookup.LookupBundle(ctx, s.appStoreCfg.DefaultStorefront, bundleID)
+	if err != nil || info == nil {
+		return nil, err
+	}
+	ids := storage.AppIdentifiers{AppleID: strconv.FormatInt(info.TrackID, 10), BundleID: info.BundleID}
+	s.recordIdentifiers(ctx, ids)
+	return &ids, nil
+}
+
+// recordIdentifiers maps identifiers learned along the way. Failures are
+// logged and never fail the saga.
+func (s *IngestService) recordIdentifiers(ctx context.Context, ids storage.AppIdentifiers) {
+	if s.identifiers == nil || !isAppleID(ids.AppleID) || ids.BundleID == "" && ids.PlayPackage == "" {
+		return
+	}
+	if err := s.identifiers.SaveIdentifiers(ctx, ids); err != nil {
+		logger.Warn(ctx, "Failed to record app identifiers", "error", err.Error())
+	}
+}
+
+// recordStoreIdentifiers maps the App Store and Google Play IDs a
+// multi-store request gives the same product.
+func (s *IngestService) recordStoreIdentifiers(ctx context.Context, legs []Request) {
+	var ids storage.AppIdentifiers
+	for _, leg := range legs {
+		switch leg.Store {
+		case StoreAppStore:
+			ids.AppleID = leg.AppID
+		case StoreGooglePlay:
+			ids.PlayPackage = leg.AppID
+		}
+	}
+	s.recordIdentifiers(ctx, ids)
+}
+
+// isAppleID reports whether id is a numeric App Store app ID.
+func isAppleID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -1,15 +1,26 @@
 package service
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/quiby-ai/common/pkg/events"
+	"github.com/quiby-ai/review-ingestor/config"
 	"github.com/quiby-ai/review-ingestor/internal/appstore"
+	"github.com/quiby-ai/review-ingestor/internal/budget"
+	"github.com/quiby-ai/review-ingestor/internal/fetcher"
 	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/normalize"
 	"github.com/quiby-ai/review-ingestor/internal/producer"
 	"github.com/quiby-ai/review-ingestor/internal/storage"
+	"github.com/quiby-ai/review-ingestor/internal/webhook"
 )
 
 var (
@@ -21,146 +32,855 @@ type TokenExtractor interface {
 	ExtractToken(ctx context.Context, country, appName, appID string) (string, error)
 }
 
-type ReviewFetcher interface {
-	SetToken(token string)
-	FetchAllReviews(ctx context.Context, country, appID string, opts *appstore.FetchOptions) ([]appstore.Review, error)
+type ReviewFetcher = fetcher.Fetcher
+
+// FetcherRegistry resolves the fetcher serving a store, see fetcher.Registry.
+type FetcherRegistry interface {
+	Has(store string) bool
+	Resolve(store string) (fetcher.Fetcher, bool)
 }
 
 type ReviewRepository interface {
-	SaveRawReview(ctx context.Context, id, appID, country string, rating int, title, content string, reviewedAt time.Time, responseDate *time.Time, responseContent *string) error
+	SaveRawReview(ctx context.Context, review storage.RawReview) (bool, error)
+	ReplaceRawReview(ctx context.Context, review storage.RawReview) (bool, error)
+	UpdateEditedReview(ctx context.Context, review storage.RawReview) (bool, error)
+	RefreshReview(ctx context.Context, review storage.RawReview) (bool, error)
+	BulkLoadRawReviews(ctx context.Context, reviews []storage.RawReview) ([]string, error)
+	CountByCountry(ctx context.Context, store, appID string) (map[string]int, error)
+	SaveTranslation(ctx context.Context, key storage.ReviewKey, translated string) error
+	RatingAverages(ctx context.Context, store, appID, country string, trailingFrom, recentFrom, until time.Time) (storage.RatingAverage, storage.RatingAverage, error)
+	RollbackRun(ctx context.Context, runID string, purge bool) (int64, error)
+	ReconcileDeletions(ctx context.Context, window storage.DeletionWindow, mark bool) (int64, error)
+}
+
+type IngestionLocker interface {
+	Lock(ctx context.Context, store, appID, country string) (func(), error)
+}
+
+type ControlStore interface {
+	IsPaused(ctx context.Context, appID string) (bool, error)
+	ParkSaga(ctx context.Context, saga storage.ParkedSaga) error
+	TakeParkedSagas(ctx context.Context, scope string) ([]storage.ParkedSaga, error)
+	CheckpointCancelled(ctx context.Context, saga storage.ParkedSaga) error
+	GetStartOverride(ctx context.Context, store, appID, country string) (*storage.StartOverride, error)
+	CompleteStartOverride(ctx context.Context, override storage.StartOverride) error
+}
+
+type QuarantineStore interface {
+	Quarantine(ctx context.Context, review storage.QuarantinedReview) error
+	List(ctx context.Context, afterID int64, limit int) ([]storage.QuarantinedReview, error)
+	Delete(ctx context.Context, id int64) error
 }
 
 type KafkaProducer interface {
 	PublishEvent(ctx context.Context, key []byte, envelope events.Envelope[any]) error
-	BuildEnvelope(event events.ExtractCompleted, sagaID string) events.Envelope[any]
+	BuildEnvelope(event producer.ExtractCompleted, sagaID string) events.Envelope[any]
+	BuildProgressEnvelope(progress producer.ExtractProgress, sagaID string) events.Envelope[any]
+	BuildCancelledEnvelope(event producer.ExtractCancelled, sagaID string) events.Envelope[any]
+	BuildReviewsEnvelope(event producer.ReviewsIngested, sagaID string) events.Envelope[any]
+	BuildResumeEnvelope(event producer.ExtractResume, sagaID string) events.Envelope[any]
+	BuildAnomalyEnvelope(event producer.RatingAnomalyDetected, sagaID string) events.Envelope[any]
+	BuildWatchlistEnvelope(event producer.WatchlistHit, sagaID string) events.Envelope[any]
+	BuildSLAEnvelope(event producer.ResponseSLABreached, sagaID string) events.Envelope[any]
+}
+
+// AppLookup reports an app's listing in a storefront, or nil when it is not
+// available there.
+type AppLookup interface {
+	Lookup(ctx context.Context, country, appID string) (*appstore.AppInfo, error)
+	LookupBundle(ctx context.Context, country, bundleID string) (*appstore.AppInfo, error)
+}
+
+// StorefrontDiscoverer lists the storefronts an app is available in.
+type StorefrontDiscoverer interface {
+	Discover(ctx context.Context, appID string) ([]string, error)
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, payload webhook.Payload) error
 }
 
 type IngestService struct {
-	extractor TokenExtractor
-	fetcher   ReviewFetcher
-	repo      ReviewRepository
-	producer  KafkaProducer
+	extractor     TokenExtractor
+	fetchers      FetcherRegistry
+	repo          ReviewRepository
+	locker        IngestionLocker
+	control       ControlStore
+	quarantine    QuarantineStore
+	producer      KafkaProducer
+	appStoreCfg   config.AppStoreConfig
+	ingestCfg     config.IngestConfig
+	anomalyCfg    config.AnomalyConfig
+	slaCfg        config.ResponseSLAConfig
+	accountingCfg config.AccountingConfig
+	runs          *runRegistry
+	notifier      Notifier
+	lookup        AppLookup
+	discoverer    StorefrontDiscoverer
+	health        StorefrontHealth
+	suspended     SuspendedSagaStore
+	spool         EventSpool
+	translator    Translator
+	// translations queues stored reviews for RunTranslations.
+	translations chan storage.RawReview
+	shadow       ShadowStore
+	// shadowQueue queues saved reviews for RunShadowWrites.
+	shadowQueue   chan shadowWrite
+	shadowTimeout time.Duration
+	hooks         []PostSaveHook
+	versions      AppVersionStore
+	watchlist     Watchlist
+	aggregates    AggregateStore
+	usage         UsageStore
+	identifiers   IdentifierStore
+	apps          config.AppOverrides
+	features      map[string]bool
+	// pageDelay and pageDelayJitter start as appstore.page_delay and
+	// page_delay_jitter and can be changed with SetPageDelay.
+	delayMu         sync.Mutex
+	pageDelay       time.Duration
+	pageDelayJitter time.Duration
+	// stages holds the pipeline stages ingest.pipeline can name.
+	stages    map[string]Middleware
+	enrichers []Enricher
+	// limit caps the reviews fetched per storefront, Limit unless set with
+	// WithLimit.
+	limit int
+	// sagaSlots bounds the sagas running at once, see WithConcurrency.
+	sagaSlots chan struct{}
 }
 
-func NewIngestService(te *appstore.TokenExtractor, rf *appstore.ReviewFetcher, repo *storage.ReviewRepository, prod *producer.Producer) *IngestService {
-	return &IngestService{extractor: te, fetcher: rf, repo: repo, producer: prod}
+// NewIngestService creates the service. Requests are served by the fetcher
+// fetchers resolves for their store; the App Store fetcher must be in it.
+// Configuration and the optional stores and integrations are set with opts.
+func NewIngestService(te TokenExtractor, fetchers FetcherRegistry, repo ReviewRepository, locker IngestionLocker, control ControlStore, quarantine QuarantineStore, prod KafkaProducer, opts ...Option) *IngestService {
+	s := &IngestService{extractor: te, fetchers: fetchers, repo: repo, locker: locker, control: control, quarantine: quarantine, producer: prod, runs: newRunRegistry(), limit: Limit}
+	s.stages = s.builtinStages()
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.SetPageDelay(s.appStoreCfg.PageDelay, s.appStoreCfg.PageDelayJitter)
+	return s
 }
 
-func (s *IngestService) Handle(ctx context.Context, evt events.ExtractRequest, sagaID string) error {
+// SetPageDelay changes the delay between review pages, plus up to jitter,
+// for sagas started from now on. Apps with their own delay keep it.
+func (s *IngestService) SetPageDelay(delay, jitter time.Duration) {
+	s.delayMu.Lock()
+	defer s.delayMu.Unlock()
+	s.pageDelay, s.pageDelayJitter = delay, jitter
+}
+
+func (s *IngestService) pageDelays() (time.Duration, time.Duration) {
+	s.delayMu.Lock()
+	defer s.delayMu.Unlock()
+	return s.pageDelay, s.pageDelayJitter
+}
+
+func (s *IngestService) Handle(ctx context.Context, req Request, sagaID string) error {
 	timer := logger.StartTimer()
 
-	logger.LogEvent(ctx, "service.ingest.started", "in_progress", "countries", len(evt.Countries))
+	logger.LogEvent(ctx, "service.ingest.started", "in_progress", "countries", len(req.Countries), "store", req.Store)
 
-	if err := evt.Validate(); err != nil {
+	req = req.WithDefaultWindow(s.ingestCfg.DefaultWindow, time.Now())
+	if err := req.Validate(); err != nil {
 		logger.LogEventWithLatency(ctx, "service.ingest.completed", "failed", timer(), "error", "validation_failed")
 		return fmt.Errorf("invalid incoming event: %w", err)
 	}
+	if err := s.checkStores(req); err != nil {
+		logger.LogEventWithLatency(ctx, "service.ingest.completed", "failed", timer(), "error", "unknown_store")
+		return err
+	}
+	if len(req.Stores) > 0 {
+		return s.track(ctx, req, sagaID, func(ctx context.Context) error {
+			return s.fanOut(ctx, req, sagaID, nil, timer)
+		})
+	}
+	req, err := s.resolveAppID(ctx, req)
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "service.ingest.completed", "failed", timer(), "error", "app_id_unresolved")
+		return err
+	}
+	req, err = s.expandCountries(ctx, req)
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "service.ingest.completed", "failed", timer(), "error", "discovery_failed")
+		return err
+	}
+	req.Countries = s.orderCountries(ctx, req, req.Countries)
 
-	totalCount := 0
+	return s.track(ctx, req, sagaID, func(ctx context.Context) error {
+		return s.ingest(ctx, req, sagaID, req.Countries, nil, timer)
+	})
+}
+
+func errUnsupportedStore(store string) error {
+	return fmt.Errorf("unsupported store %q", store)
+}
 
-	tokenCountry := evt.Countries[0]
-	tokenTimer := logger.StartTimer()
-	token, err := s.extractor.ExtractToken(ctx, tokenCountry, evt.AppName, evt.AppID)
+// ResumeParked continues the parked sagas for scope (an app ID or
+// storage.GlobalScope) from their checkpoints. It returns the number of sagas
+// that were resumed.
+func (s *IngestService) ResumeParked(ctx context.Context, scope string) (int, error) {
+	sagas, err := s.control.TakeParkedSagas(ctx, scope)
 	if err != nil {
-		logger.LogEventWithLatency(ctx, "service.token.extracted", "failed", tokenTimer(), "country", tokenCountry)
-		logger.LogEventWithLatency(ctx, "service.ingest.completed", "failed", timer(), "error", "token_extraction_failed")
-		return fmt.Errorf("failed to extract token for country %s: %w", tokenCountry, err)
+		return 0, err
 	}
-	logger.LogEventWithLatency(ctx, "service.token.extracted", "success", tokenTimer(), "country", tokenCountry)
 
-	s.fetcher.SetToken(token)
+	for _, saga := range sagas {
+		sagaCtx := logger.WithAppID(logger.WithSagaID(ctx, saga.SagaID), saga.AppID)
+
+		var req checkpointRequest
+		var progress []producer.CountryStats
+		if err := json.Unmarshal(saga.Request, &req); err != nil {
+			logger.Error(sagaCtx, "Failed to decode parked saga", err)
+			continue
+		}
+		if err := json.Unmarshal(saga.Progress, &progress); err != nil {
+			logger.Error(sagaCtx, "Failed to decode parked saga progress", err)
+			continue
+		}
+
+		logger.LogEvent(sagaCtx, "service.ingest.resumed", "in_progress", "remaining_countries", len(saga.RemainingCountries), "completed_countries", len(progress))
+		if err := s.continueSaga(sagaCtx, req, saga.SagaID, saga.RemainingCountries, progress); err != nil {
+			logger.Error(sagaCtx, "Resumed saga failed", err)
+		}
+	}
+	return len(sagas), nil
+}
+
+// continueSaga runs a checkpointed saga over its remaining countries, and a
+// multi-store saga over its remaining stores as well.
+func (s *IngestService) continueSaga(ctx context.Context, saved checkpointRequest, sagaID string, remaining []string, progress []producer.CountryStats) error {
+	req := saved.Request
+	if len(req.Stores) > 0 {
+		resumed := &fanOutResume{finished: saved.FinishedStores, remaining: remaining, progress: progress}
+		return s.track(ctx, req, sagaID, func(ctx context.Context) error {
+			s.runs.resumeHeartbeats(sagaID, saved.Heartbeats)
+			return s.fanOut(ctx, req, sagaID, resumed, logger.StartTimer())
+		})
+	}
+	if req.Store == "" {
+		req.Store = StoreAppStore
+	}
+	return s.track(ctx, req, sagaID, func(ctx context.Context) error {
+		s.runs.resumeHeartbeats(sagaID, saved.Heartbeats)
+		return s.ingest(ctx, req, sagaID, remaining, progress, logger.StartTimer())
+	})
+}
+
+// ingest processes countries and publishes the completion event. stats holds
+// the results of countries finished before the saga was parked, if any.
+func (s *IngestService) ingest(ctx context.Context, req Request, sagaID string, countries []string, stats []producer.CountryStats, timer func() time.Duration) error {
+	fetcher, _ := s.fetchers.Resolve(req.Store)
+	ctx, settings := s.resolveSettings(ctx, req)
+
+	if req.Store == StoreAppStore {
+		token, err := s.extractToken(ctx, req)
+		if err != nil {
+			logger.LogEventWithLatency(ctx, "service.ingest.completed", "failed", timer(), "error", "token_extraction_failed")
+			return err
+		}
+		settings.token = token
+	}
+
+	progress := newSagaProgress()
+	s.runs.attach(sagaID, progress)
+	stopHeartbeat := s.startHeartbeat(ctx, req, sagaID, progress)
+	defer stopHeartbeat()
+
+	for i, country := range countries {
+		if isCancelled(ctx) {
+			return s.cancel(ctx, req, sagaID, countries[i:], stats, timer)
+		}
+
+		parked, err := s.parkIfPaused(ctx, req, sagaID, countries[i:], stats)
+		if reason, ok := suspendReason(err); ok && s.suspend(ctx, req, sagaID, reason, countries[i:], stats, timer) {
+			return nil
+		}
+		if err != nil {
+			logger.LogEventWithLatency(ctx, "service.ingest.completed", "failed", timer(), "error", "park_failed")
+			return err
+		}
+		if parked {
+			s.runs.setStatus(sagaID, RunStatusParked)
+			logger.LogEventWithLatency(ctx, "service.ingest.parked", "success", timer(), "remaining_countries", len(countries)-i)
+			return nil
+		}
+
+		if !s.availableIn(ctx, req, country) {
+			logger.LogEvent(ctx, "service.country.processed", "skipped", "country", country, "reason", "app_unavailable")
+			stats = append(stats, producer.CountryStats{Country: country, Unavailable: true})
+			continue
+		}
+		if s.coolingDown(ctx, req, country) {
+			logger.LogEvent(ctx, "service.country.processed", "skipped", "country", country, "reason", "cooling_down")
+			stats = append(stats, producer.CountryStats{Country: country, CoolingDown: true})
+			continue
+		}
 
-	for _, country := range evt.Countries {
 		countryTimer := logger.StartTimer()
-		count, err := s.handleReviewsByCountry(ctx, evt, country, Limit)
+		progress.setCountry(country)
+		countryStats, err := s.handleReviewsByCountry(ctx, req, sagaID, fetcher, country, settings, progress)
+		if err != nil && isCancelled(ctx) {
+			logger.LogEventWithLatency(ctx, "service.country.processed", "cancelled", countryTimer(), "country", country)
+			return s.cancel(ctx, req, sagaID, countries[i:], stats, timer)
+		}
+		if reason, ok := suspendReason(err); ok && s.suspend(ctx, req, sagaID, reason, countries[i:], stats, timer) {
+			logger.LogEventWithLatency(ctx, "service.country.processed", "suspended", countryTimer(), "country", country)
+			return nil
+		}
+		if errors.Is(err, storage.ErrLockNotAcquired) {
+			logger.LogEventWithLatency(ctx, "service.country.processed", "skipped", countryTimer(), "country", country, "reason", "locked")
+			stats = append(stats, producer.CountryStats{Country: country, Locked: true})
+			continue
+		}
+		s.recordStorefrontOutcome(ctx, req, country, err != nil)
 		if err != nil {
 			logger.LogEventWithLatency(ctx, "service.country.processed", "failed", countryTimer(), "country", country)
 			logger.LogEventWithLatency(ctx, "service.ingest.completed", "failed", timer(), "error", "country_processing_failed")
 			return fmt.Errorf("failed to process country %s: %w", country, err)
 		}
-		logger.LogEventWithLatency(ctx, "service.country.processed", "success", countryTimer(), "country", country, "reviews_count", count)
-		totalCount += count
+		logger.LogEventWithLatency(ctx, "service.country.processed", "success", countryTimer(), "country", country,
+			"fetched", countryStats.Fetched, "new_reviews", countryStats.New, "duplicates", countryStats.Duplicates, "failed", countryStats.Failed)
+		stats = append(stats, countryStats)
+		s.detectRatingAnomaly(ctx, req, sagaID, country)
 	}
 
-	publishTimer := logger.StartTimer()
-	outputEvent := events.ExtractCompleted{
-		ExtractRequest: evt,
-		Count:          totalCount,
+	s.trackAppVersion(ctx, req)
+	s.saveAggregates(ctx, req, sagaID, stats)
+
+	if collected, ok := ctx.Value(storeLegsKey{}).(*storeLegs); ok {
+		collected.legs = append(collected.legs, producer.StoreLeg{Store: req.Store, AppID: req.AppID, Countries: stats})
+		return nil
 	}
+
+	publishTimer := logger.StartTimer()
+	outputEvent := producer.NewExtractCompleted(req.ExtractRequest, req.Store, stats)
 	if err := s.publishEvent(ctx, outputEvent, sagaID); err != nil {
 		logger.LogEventWithLatency(ctx, "producer.event.published", "failed", publishTimer())
+		if s.suspend(ctx, req, sagaID, suspendReasonKafka, nil, stats, timer) {
+			return nil
+		}
 		logger.LogEventWithLatency(ctx, "service.ingest.completed", "failed", timer(), "error", "event_publish_failed")
 		return fmt.Errorf("failed to publish prepare reviews event: %w", err)
 	}
 	logger.LogEventWithLatency(ctx, "producer.event.published", "success", publishTimer())
 
-	logger.LogEventWithLatency(ctx, "service.ingest.completed", "success", timer(), "total_reviews", totalCount)
+	s.notify(ctx, webhook.Payload{
+		Event:     webhook.EventCompleted,
+		SagaID:    sagaID,
+		AppID:     req.AppID,
+		Store:     req.Store,
+		Completed: &outputEvent,
+	})
+
+	logger.LogEventWithLatency(ctx, "service.ingest.completed", "success", timer(), "total_reviews", outputEvent.Count,
+		"fetched", outputEvent.Fetched, "new_reviews", outputEvent.New, "duplicates", outputEvent.Duplicates, "failed", outputEvent.Failed,
+		"requests", outputEvent.RequestBudget.Requests, "rate_limited", outputEvent.RequestBudget.RateLimited, "backoff_seconds", outputEvent.RequestBudget.BackoffSeconds)
+	return nil
+}
+
+// expandCountries replaces the "all" countries entry of an App Store request
+// with the storefronts the app is available in. Without a discoverer every
+// storefront is requested.
+func (s *IngestService) expandCountries(ctx context.Context, req Request) (Request, error) {
+	if !req.WantsAllCountries() {
+		return req, nil
+	}
+	if req.Store != StoreAppStore {
+		return req, fmt.Errorf("countries %q is only supported for the App Store", AllCountries)
+	}
+	if s.discoverer == nil {
+		req.Countries = slices.Clone(appstore.Storefronts)
+		return req, nil
+	}
+	storefronts, err := s.discoverer.Discover(ctx, req.AppID)
+	if err != nil {
+		return req, fmt.Errorf("failed to discover storefronts: %w", err)
+	}
+	req.Countries = storefronts
+	return req, nil
+}
+
+// availableIn reports whether req's app is listed in country. Only App Store
+// requests are checked, and lookup failures count as available so the
+// pre-check never loses reviews.
+func (s *IngestService) availableIn(ctx context.Context, req Request, country string) bool {
+	if s.lookup == nil || !s.appStoreCfg.PrecheckAvailability || req.Store != StoreAppStore {
+		return true
+	}
+	info, err := s.lookup.Lookup(ctx, country, req.AppID)
+	if err != nil {
+		logger.Warn(ctx, "App availability check failed, fetching anyway", "country", country, "error", err.Error())
+		return true
+	}
+	return info != nil
+}
+
+// parkIfPaused checkpoints the saga into the parked table when ingestion is
+// paused for the app or globally.
+func (s *IngestService) parkIfPaused(ctx context.Context, req Request, sagaID string, remaining []string, stats []producer.CountryStats) (bool, error) {
+	paused, err := s.control.IsPaused(ctx, req.AppID)
+	if err != nil {
+		return false, err
+	}
+	if !paused {
+		return false, nil
+	}
+
+	saga, err := s.newCheckpoint(ctx, req, sagaID, remaining, stats)
+	if err != nil {
+		return false, err
+	}
+	if err := s.control.ParkSaga(ctx, saga); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// notify delivers a webhook when a notifier is set. Delivery failures are
+// logged and never fail the saga.
+func (s *IngestService) notify(ctx context.Context, payload webhook.Payload) {
+	if s.notifier == nil {
+		return
+	}
+	payload.OccurredAt = time.Now().UTC()
+	if err := s.notifier.Notify(context.WithoutCancel(ctx), payload); err != nil {
+		logger.Error(ctx, "Failed to deliver webhook", err, "event", payload.Event)
+	}
+}
+
+func isCancelled(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), ErrRunCancelled)
+}
+
+// cancel checkpoints a saga stopped by CancelRun and publishes an
+// ExtractCancelled event. The countries in remaining were not finished.
+func (s *IngestService) cancel(ctx context.Context, req Request, sagaID string, remaining []string, stats []producer.CountryStats, timer func() time.Duration) error {
+	ctx = context.WithoutCancel(ctx)
+
+	saga, err := s.newCheckpoint(ctx, req, sagaID, remaining, stats)
+	if err == nil {
+		err = s.control.CheckpointCancelled(ctx, saga)
+	}
+	if err != nil {
+		logger.Error(ctx, "Failed to checkpoint cancelled saga", err)
+	}
+
+	event := producer.NewExtractCancelled(req.ExtractRequest, req.Store, stats, remaining)
+	envelope := s.producer.BuildCancelledEnvelope(event, sagaID)
+	if err := s.producer.PublishEvent(ctx, []byte(sagaID), envelope); err != nil {
+		logger.LogEventWithLatency(ctx, "service.ingest.cancelled", "failed", timer(), "error", "event_publish_failed")
+		return fmt.Errorf("failed to publish cancelled event: %w", err)
+	}
+
+	logger.LogEventWithLatency(ctx, "service.ingest.cancelled", "success", timer(), "remaining_countries", len(remaining), "fetched", event.Fetched)
 	return nil
 }
 
-func (s *IngestService) handleReviewsByCountry(ctx context.Context, event events.ExtractRequest, country string, maxLimit int) (int, error) {
+// newCheckpoint encodes a saga's request and the stats of its finished
+// countries so it can be continued later. A store of a multi-store saga
+// checkpoints the whole saga.
+func (s *IngestService) newCheckpoint(ctx context.Context, req Request, sagaID string, remaining []string, stats []producer.CountryStats) (storage.ParkedSaga, error) {
+	saved := checkpointRequest{Request: req}
+	if collected, ok := ctx.Value(storeLegsKey{}).(*storeLegs); ok {
+		saved = collected.checkpoint(req)
+	}
+	saved.Heartbeats = s.runs.heartbeats(sagaID)
+	request, err := json.Marshal(saved)
+	if err != nil {
+		return storage.ParkedSaga{}, fmt.Errorf("failed to encode saga checkpoint: %w", err)
+	}
+	progress, err := json.Marshal(stats)
+	if err != nil {
+		return storage.ParkedSaga{}, fmt.Errorf("failed to encode saga checkpoint progress: %w", err)
+	}
+
+	return storage.ParkedSaga{
+		SagaID:             sagaID,
+		AppID:              req.AppID,
+		Request:            request,
+		RemainingCountries: remaining,
+		Progress:           progress,
+	}, nil
+}
+
+// extractToken walks the token storefront chain and returns the first token
+// that could be extracted. The error of the last attempt is wrapped when every
+// storefront fails.
+func (s *IngestService) extractToken(ctx context.Context, req Request) (string, error) {
+	var lastErr error
+	for i, country := range tokenStorefronts(req.Countries, s.appStoreCfg.DefaultStorefront) {
+		tokenTimer := logger.StartTimer()
+		token, err := s.extractor.ExtractToken(ctx, country, s.resolveAppName(ctx, req, country), req.AppID)
+		if err != nil {
+			logger.LogEventWithLatency(ctx, "service.token.extracted", "failed", tokenTimer(), "country", country, "attempt", i+1)
+			lastErr = fmt.Errorf("failed to extract token for country %s: %w", country, err)
+			continue
+		}
+		logger.LogEventWithLatency(ctx, "service.token.extracted", "success", tokenTimer(), "country", country, "attempt", i+1)
+		return token, nil
+	}
+	return "", lastErr
+}
+
+// resolveAppName returns the landing page slug the iTunes lookup API reports
+// for the app in country, so requests without an app name or with a stale
+// slug still reach the right landing page. It falls back to req.AppName.
+func (s *IngestService) resolveAppName(ctx context.Context, req Request, country string) string {
+	if s.lookup == nil {
+		return req.AppName
+	}
+	info, err := s.lookup.Lookup(ctx, country, req.AppID)
+	if err != nil || info == nil || info.Slug() == "" {
+		return req.AppName
+	}
+	if slug := info.Slug(); slug != req.AppName {
+		logger.LogEvent(ctx, "service.app_name.resolved", "success", "country", country, "requested", req.AppName, "resolved", slug)
+		return slug
+	}
+	return req.AppName
+}
+
+// tokenStorefronts returns the storefronts to try for token extraction: the
+// requested countries in order, followed by the default storefront unless it
+// was already requested.
+func tokenStorefronts(countries []string, defaultStorefront string) []string {
+	candidates := make([]string, 0, len(countries)+1)
+	candidates = append(candidates, countries...)
+	candidates = append(candidates, defaultStorefront)
+
+	storefronts := make([]string, 0, len(candidates))
+	seen := make(map[string]bool, len(candidates))
+	for _, country := range candidates {
+		key := strings.ToLower(country)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		storefronts = append(storefronts, country)
+	}
+	return storefronts
+}
+
+func (s *IngestService) handleReviewsByCountry(ctx context.Context, event Request, sagaID string, fetcher ReviewFetcher, country string, settings sagaSettings, progress *sagaProgress) (producer.CountryStats, error) {
+	stats := producer.CountryStats{Country: country}
+
 	logger.Debug(ctx, "Processing country", "country", country, "app_id", event.AppID)
 
-	afterDate, _ := time.Parse("2006-01-02", event.DateFrom)
+	release, err := s.locker.Lock(ctx, event.Store, event.AppID, country)
+	if err != nil {
+		return stats, fmt.Errorf("failed to lock app %s in country %s: %w", event.AppID, country, err)
+	}
+	defer release()
+
+	afterDate, err := ParseRequestDate(event.DateFrom)
+	if err != nil {
+		return stats, fmt.Errorf("invalid date_from: %w", err)
+	}
+	afterDate = s.lookbackStart(afterDate)
 	opts := &appstore.FetchOptions{
 		Limit:    20,
 		Offset:   0,
 		After:    &afterDate,
-		MaxLimit: maxLimit,
+		MaxLimit: settings.maxReviews,
+		Sleep:    &settings.pageDelay,
+		Jitter:   settings.pageDelayJitter,
+		OnPage:   progress.update,
+		Token:    settings.token,
+	}
+	override, err := s.applyStartPosition(ctx, event, country, opts)
+	if err != nil {
+		return stats, err
+	}
+
+	// Reviews are saved window by window while paging so memory stays bounded
+	// however many reviews the app has.
+	handler, err := s.pipeline()
+	if err != nil {
+		return stats, err
+	}
+	seenBodies := make(map[string]string)
+	var failedSaves []BatchItem
+	newBatch := func(reviews []appstore.Review) *ReviewBatch {
+		batch := &ReviewBatch{
+			Request:    event,
+			SagaID:     sagaID,
+			Country:    country,
+			Items:      make([]BatchItem, len(reviews)),
+			stats:      &stats,
+			seenBodies: seenBodies,
+			settings:   settings,
+		}
+		for i, review := range reviews {
+			batch.Items[i].Review = review
+		}
+		return batch
+	}
+	window := &fetchedWindow{}
+	flush := func(ctx context.Context, reviews []appstore.Review) error {
+		reviewBatchSize.Observe(float64(len(reviews)), event.Store)
+		if s.ingestCfg.DeletionMode != config.DeletionModeOff {
+			for _, review := range reviews {
+				window.observe(review, s.appStoreCfg.DateLayouts)
+			}
+		}
+		batch := newBatch(reviews)
+		if s.ingestCfg.RetryFailedSaves {
+			batch.failedSaves = &failedSaves
+		}
+		return handler(ctx, batch)
+	}
+
+	persist, drain := flush, func() error { return nil }
+	if s.ingestCfg.PersistQueue > 0 {
+		persist, drain = persistQueue(event.Store, s.ingestCfg.PersistQueue, flush)
 	}
 
 	fetchTimer := logger.StartTimer()
-	reviews, err := s.fetcher.FetchAllReviews(ctx, country, event.AppID, opts)
+	usage := &budget.Budget{}
+	fetched, err := fetcher.StreamReviews(budget.With(ctx, usage), country, event.AppID, opts, s.appStoreCfg.PageWindow, persist)
+	if drainErr := drain(); err == nil {
+		err = drainErr
+	}
+	if retryErr := s.retryFailedSaves(ctx, handler, newBatch, failedSaves); err == nil {
+		err = retryErr
+	}
+	stats.Fetched = fetched
+	stats.RequestBudget = usage.Usage()
+	s.recordUsage(ctx, event, settings, stats.RequestBudget)
 	if err != nil {
 		logger.LogEventWithLatency(ctx, "service.reviews.fetched", "failed", fetchTimer(), "country", country)
-		return 0, fmt.Errorf("failed to fetch reviews for country %s: %w", country, err)
+		return stats, fmt.Errorf("failed to fetch reviews for country %s: %w", country, err)
 	}
-	logger.LogEventWithLatency(ctx, "service.reviews.fetched", "success", fetchTimer(), "country", country, "count", len(reviews))
+	logger.LogEventWithLatency(ctx, "service.reviews.fetched", "success", fetchTimer(), "country", country, "count", fetched)
 
-	successCount := 0
-	for _, review := range reviews {
-		reviewCtx := logger.WithReviewID(ctx, review.ID)
+	if err := s.checkSaveErrorThreshold(stats); err != nil {
+		return stats, err
+	}
+	s.reconcileDeletions(ctx, event, country, window, &stats)
+	s.completeStartOverride(ctx, override)
 
-		reviewDate, err := time.Parse("2006-01-02T15:04:05Z", review.Attributes.Date)
-		if err != nil {
-			logger.Warn(reviewCtx, "Failed to parse review date", "error", err.Error())
+	logger.Info(ctx, "Country processing completed", "country", country, "fetched", stats.Fetched, "new_reviews", stats.New, "duplicates", stats.Duplicates, "failed", stats.Failed)
+	return stats, nil
+}
+
+// observeReview adds a stored review to the completion stats. Stored reviews
+// always have a valid date, as saveReview rejects the others.
+func (s *IngestService) observeReview(stats *producer.CountryStats, review appstore.Review) {
+	reviewedAt, _ := normalize.Timestamp(review.Attributes.Date, s.appStoreCfg.DateLayouts)
+	stats.Observe(review.Attributes.Rating, reviewedAt)
+}
+
+// checkSaveErrorThreshold fails the country when the threshold policy is
+// active and the share of failed saves exceeds the configured limit.
+func (s *IngestService) checkSaveErrorThreshold(stats producer.CountryStats) error {
+	if s.ingestCfg.SaveErrorPolicy != config.SaveErrorPolicyThreshold || stats.Fetched == 0 {
+		return nil
+	}
+	ratio := float64(stats.Failed) / float64(stats.Fetched)
+	if ratio > s.ingestCfg.SaveErrorThreshold {
+		return fmt.Errorf("%d of %d reviews failed to save in country %s, above threshold %.2f", stats.Failed, stats.Fetched, stats.Country, s.ingestCfg.SaveErrorThreshold)
+	}
+	return nil
+}
+
+// saveReview stores a review and reports whether it was new. With overwrite
+// an already stored copy is replaced instead of kept.
+func (s *IngestService) saveReview(ctx context.Context, store, appID, country string, review appstore.Review, overwrite bool) (bool, error) {
+	row, err := s.toRawReview(store, appID, country, review)
+	if err != nil {
+		logger.Warn(ctx, "Failed to parse review date", "error", err.Error())
+		return false, err
+	}
+	return s.saveRow(ctx, row, overwrite)
+}
+
+// saveRow stores a converted review and reports whether it was new.
+func (s *IngestService) saveRow(ctx context.Context, row storage.RawReview, overwrite bool) (bool, error) {
+	save := s.repo.SaveRawReview
+	if overwrite {
+		save = s.repo.ReplaceRawReview
+	}
+
+	saveTimer := logger.StartTimer()
+	inserted, err := save(ctx, row)
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "storage.review.saved", "failed", saveTimer(), "country", row.Country)
+		return false, fmt.Errorf("failed to save review: %w", err)
+	}
+	logger.LogEventWithLatency(ctx, "storage.review.saved", "success", saveTimer(), "country", row.Country, "inserted", inserted)
+	op := shadowOpInsert
+	if overwrite {
+		op = shadowOpReplace
+	}
+	s.mirrorReview(ctx, op, row, inserted)
+	return inserted, nil
+}
+
+// toRawReview normalizes a fetched review into the row that is stored.
+func (s *IngestService) toRawReview(store, appID, country string, review appstore.Review) (storage.RawReview, error) {
+	reviewDate, err := normalize.ZonedTimestamp(review.Attributes.Date, s.appStoreCfg.DateLayouts)
+	if err != nil {
+		return storage.RawReview{}, fmt.Errorf("invalid review date: %w", err)
+	}
+
+	row := storage.RawReview{
+		Store:      store,
+		ID:         review.ID,
+		AppID:      appID,
+		Country:    country,
+		Territory:  strings.ToLower(strings.TrimSpace(review.Attributes.Territory)),
+		AppVersion: strings.TrimSpace(review.Attributes.AppVersion),
+		Rating:     review.Attributes.Rating,
+		Title:      normalize.Text(review.Attributes.Title),
+		Content:    normalize.Text(review.Attributes.Review),
+		ReviewedAt: reviewDate,
+	}
+	row.ContentPlain = normalize.PlainText(row.Content)
+	row.Fingerprint = normalize.Fingerprint(row.Rating, row.Title, row.Content)
+	row.Language = normalize.Language(review.Attributes.Language, country)
+	if s.ingestCfg.StoreRawPayloads {
+		row.Payload = review.Raw
+	}
+	if review.Attributes.DeveloperResponse != nil {
+		if parsed, err := normalize.Timestamp(review.Attributes.DeveloperResponse.Modified, s.appStoreCfg.DateLayouts); err == nil {
+			row.ResponseDate = &parsed
+		}
+		body := normalize.Text(review.Attributes.DeveloperResponse.Body)
+		row.ResponseContent = &body
+	}
+	if review.Attributes.IsEdited {
+		edited := cmp.Or(review.Attributes.Modified, review.Attributes.Date)
+		if parsed, err := normalize.Timestamp(edited, s.appStoreCfg.DateLayouts); err == nil {
+			row.EditedAt = &parsed
+		}
+	}
+	row.QualityFlag = normalize.QualityFlag(row.Title, row.Content, s.ingestCfg.QualityMinLength)
+	return row, nil
+}
+
+// bulkSave stores a page window with one COPY-based load, recording the new
+// rows in batch.Inserted. It returns false, leaving the window unsaved, when
+// the load fails, so the persist stage falls back to row-by-row saves that
+// quarantine the offending reviews and apply the save error policy.
+func (s *IngestService) bulkSave(ctx context.Context, batch *ReviewBatch) bool {
+	rows := make([]storage.RawReview, len(batch.Items))
+	for i, item := range batch.Items {
+		rows[i] = item.Row
+	}
+
+	timer := logger.StartTimer()
+	insertedIDs, err := s.repo.BulkLoadRawReviews(ctx, rows)
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "service.reviews.bulk_saved", "failed", timer(), "country", batch.Country, "reviews", len(rows), "error", err.Error())
+		return false
+	}
+	logger.LogEventWithLatency(ctx, "service.reviews.bulk_saved", "success", timer(), "country", batch.Country, "reviews", len(rows), "inserted", len(insertedIDs))
+
+	isNew := make(map[string]bool, len(insertedIDs))
+	for _, id := range insertedIDs {
+		isNew[id] = true
+	}
+	for _, row := range rows {
+		batch.stats.Observe(row.Rating, row.ReviewedAt.UTC())
+		s.mirrorReview(ctx, shadowOpInsert, row, isNew[row.ID])
+		if isNew[row.ID] {
+			batch.Inserted = append(batch.Inserted, row)
+			delete(isNew, row.ID)
 			continue
 		}
+		s.applyEdit(logger.WithReviewID(ctx, row.ID), batch, row)
+	}
+	batch.stats.New += len(insertedIDs)
+	batch.stats.Duplicates += len(rows) - len(insertedIDs)
+	return true
+}
 
-		var responseDate *time.Time
-		var responseContent *string
-		if review.Attributes.DeveloperResponse != nil {
-			if parsed, err := time.Parse("2006-01-02T15:04:05Z", review.Attributes.DeveloperResponse.Modified); err == nil {
-				responseDate = &parsed
-			}
-			responseContent = &review.Attributes.DeveloperResponse.Body
+// applyEdit updates a stored review whose fingerprint changed since it was
+// stored, counting it in the batch stats; exact redeliveries are left alone.
+// Failures are logged; the stored copy is then kept until the next saga sees
+// the review.
+func (s *IngestService) applyEdit(ctx context.Context, batch *ReviewBatch, row storage.RawReview) {
+	if s.inLookback(row) {
+		s.refreshReview(ctx, batch, row)
+		return
+	}
+	updated, err := s.repo.UpdateEditedReview(ctx, row)
+	if err != nil {
+		logger.Error(ctx, "Failed to update edited review", err)
+		return
+	}
+	s.mirrorReview(ctx, shadowOpEdit, row, updated)
+	if updated {
+		batch.stats.Edited++
+	}
+}
+
+// quarantineReview keeps a review that failed to save, together with the
+// reason, so it can be reprocessed instead of being dropped.
+func (s *IngestService) quarantineReview(ctx context.Context, store, appID, country string, review appstore.Review, reason error) {
+	raw, err := json.Marshal(review)
+	if err != nil {
+		logger.Error(ctx, "Failed to encode review for quarantine", err)
+		return
+	}
+
+	if err := s.quarantine.Quarantine(ctx, storage.QuarantinedReview{
+		Store:    store,
+		ReviewID: review.ID,
+		AppID:    appID,
+		Country:  country,
+		Raw:      raw,
+		Reason:   reason.Error(),
+	}); err != nil {
+		logger.Error(ctx, "Failed to quarantine review", err)
+	}
+}
+
+// ReprocessQuarantine retries every quarantined review once, removing those
+// that are saved successfully. It returns the number of reviews saved and the
+// number that remain quarantined.
+func (s *IngestService) ReprocessQuarantine(ctx context.Context, batchSize int) (int, int, error) {
+	saved, failed := 0, 0
+	var afterID int64
+
+	for {
+		batch, err := s.quarantine.List(ctx, afterID, batchSize)
+		if err != nil {
+			return saved, failed, err
 		}
+		if len(batch) == 0 {
+			break
+		}
+
+		for _, item := range batch {
+			afterID = item.ID
+			reviewCtx := logger.WithAppID(logger.WithReviewID(ctx, item.ReviewID), item.AppID)
+
+			var review appstore.Review
+			if err := json.Unmarshal(item.Raw, &review); err != nil {
+				logger.Warn(reviewCtx, "Failed to decode quarantined review", "error", err.Error())
+				failed++
+				continue
+			}
 
-		saveTimer := logger.StartTimer()
-		if err := s.repo.SaveRawReview(
-			reviewCtx,
-			review.ID,
-			event.AppID,
-			country,
-			review.Attributes.Rating,
-			review.Attributes.Title,
-			review.Attributes.Review,
-			reviewDate,
-			responseDate,
-			responseContent,
-		); err != nil {
-			logger.LogEventWithLatency(reviewCtx, "storage.review.saved", "failed", saveTimer(), "country", country)
-		} else {
-			logger.LogEventWithLatency(reviewCtx, "storage.review.saved", "success", saveTimer(), "country", country)
-			successCount++
+			if _, err := s.saveReview(reviewCtx, item.Store, item.AppID, item.Country, review, false); err != nil {
+				failed++
+				continue
+			}
+			if err := s.quarantine.Delete(ctx, item.ID); err != nil {
+				return saved, failed, err
+			}
+			saved++
 		}
 	}
 
-	logger.Info(ctx, "Country processing completed", "country", country, "fetched", len(reviews), "saved", successCount)
-	return len(reviews), nil
+	logger.LogEvent(ctx, "service.quarantine.reprocessed", "success", "saved", saved, "failed", failed)
+	return saved, failed, nil
 }
 
-func (s *IngestService) publishEvent(ctx context.Context, event events.ExtractCompleted, sagaID string) error {
-	envelope := s.producer.BuildEnvelope(event, sagaID)
-	return s.producer.PublishEvent(ctx, []byte(sagaID), envelope)
+func (s *IngestService) publishEvent(ctx context.Context, event producer.ExtractCompleted, sagaID string) error {
+	envelope := s.producer.BuildEnvelope(event.Project(s.ingestCfg.CompletionPayload), sagaID)
+	return s.publishOrSpool(ctx, []byte(sagaID), envelope)
 }
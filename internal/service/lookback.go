@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// lookbackStart moves after back to the start of ingest.lookback when the
+// request would not fetch that far, so recent reviews are always seen again.
+func (s *IngestService) lookbackStart(after time.Time) time.Time {
+	if s.ingestCfg.Lookback <= 0 {
+		return after
+	}
+	if start := time.Now().Add(-s.ingestCfg.Lookback); start.Before(after) {
+		return start
+	}
+	return after
+}
+
+// inLookback reports whether row was written inside ingest.lookback.
+func (s *IngestService) inLookback(row storage.RawReview) bool {
+	return s.ingestCfg.Lookback > 0 && time.Since(row.ReviewedAt) <= s.ingestCfg.Lookback
+}
+
+// refreshReview updates a stored review inside the lookback window with
+// what the store returns now, counting it when anything changed. Failures
+// are logged; the next saga tries again.
+func (s *IngestService) refreshReview(ctx context.Context, batch *ReviewBatch, row storage.RawReview) {
+	refreshed, err := s.repo.RefreshReview(ctx, row)
+	if err != nil {
+		logger.Error(ctx, "Failed to refresh review", err)
+		return
+	}
+	s.mirrorReview(ctx, shadowOpRefresh, row, refreshed)
+	if refreshed {
+		batch.stats.Refreshed++
+		s.checkResponseSLA(ctx, batch.SagaID, batch.Request, batch.Country, []storage.RawReview{row})
+	}
+}
@@ -0,0 +1,32 @@
+package service
+
+import "github.com/quiby-ai/review-ingestor/internal/metrics"
+
+// persistQueueDepth and persistQueueWait show backpressure between fetching
+// and persistence: page windows queued for saving, and how long fetching
+// waited for room in the queue.
+var (
+	persistQueueDepth = metrics.NewGaugeVec("ingestor_persist_queue_depth",
+		"Page windows fetched and waiting to be saved, by store.", "store")
+	persistQueueWait = metrics.NewHistogramVec("ingestor_persist_queue_wait_seconds",
+		"Time fetching waited for room in the persist queue, by store.", metrics.DefBuckets, "store")
+)
+
+// reviewBatchSize is the number of reviews per flushed page window.
+var reviewBatchSize = metrics.NewHistogramVec("ingestor_review_batch_size",
+	"Reviews per flushed page window, by store.", []float64{1, 5, 10, 20, 50, 100, 200, 500, 1000}, "store")
+
+// responseSeconds is the time from a review to the developer's response, and
+// slaBreaches counts responses later than response_sla.threshold.
+var (
+	responseSeconds = metrics.NewHistogramVec("ingestor_review_response_seconds",
+		"Time from a review to the developer response, by store.", []float64{3600, 6 * 3600, 24 * 3600, 3 * 24 * 3600, 7 * 24 * 3600, 30 * 24 * 3600}, "store")
+	slaBreaches = metrics.NewCounterVec("ingestor_response_sla_breaches_total",
+		"Developer responses later than the response SLA, by store.", "store")
+)
+
+// shadowWrites counts the writes mirrored to the shadow store by outcome:
+// whether the shadow agreed with the primary on what changed and on the
+// content it stored, failed, or was skipped because the queue was full.
+var shadowWrites = metrics.NewCounterVec("ingestor_shadow_writes_total",
+	"Review writes mirrored to the shadow store, by outcome.", "outcome")
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+
+	"github.com/quiby-ai/review-ingestor/config"
+)
+
+// Option configures an IngestService when it is created.
+type Option func(*IngestService)
+
+// WithAppStoreConfig sets the App Store settings: page size, page delays
+// and the other appstore.* keys. The page delays can later be changed with
+// SetPageDelay.
+func WithAppStoreConfig(cfg config.AppStoreConfig) Option {
+	return func(s *IngestService) {
+		s.appStoreCfg = cfg
+	}
+}
+
+// WithIngestConfig sets the ingest.* settings, e.g. the save error policy and
+// the pipeline stages. Without it no stage runs.
+func WithIngestConfig(cfg config.IngestConfig) Option {
+	return func(s *IngestService) {
+		s.ingestCfg = cfg
+	}
+}
+
+// WithAnomalyConfig enables rating anomaly detection as configured.
+func WithAnomalyConfig(cfg config.AnomalyConfig) Option {
+	return func(s *IngestService) {
+		s.anomalyCfg = cfg
+	}
+}
+
+// WithResponseSLAConfig enables developer response SLA checks as configured.
+func WithResponseSLAConfig(cfg config.ResponseSLAConfig) Option {
+	return func(s *IngestService) {
+		s.slaCfg = cfg
+	}
+}
+
+// WithAccountingConfig sets the tenant sagas are accounted to when neither
+// the request nor the app names one. Usage is recorded in the store set with
+// WithUsageStore.
+func WithAccountingConfig(cfg config.AccountingConfig) Option {
+	return func(s *IngestService) {
+		s.accountingCfg = cfg
+	}
+}
+
+// WithFeatures sets the feature flags.
+func WithFeatures(features map[string]bool) Option {
+	return func(s *IngestService) {
+		s.features = features
+	}
+}
+
+// WithAppOverrides sets the per-app overrides of the [apps] tables.
+func WithAppOverrides(apps config.AppOverrides) Option {
+	return func(s *IngestService) {
+		s.apps = apps
+	}
+}
+
+// WithLimit caps the reviews fetched per storefront at limit instead of
+// Limit. An app's max_reviews replaces it and a request's may only lower it.
+func WithLimit(limit int) Option {
+	return func(s *IngestService) {
+		if limit > 0 {
+			s.limit = limit
+		}
+	}
+}
+
+// WithConcurrency bounds how many sagas the service runs at once; further
+// sagas wait until one finishes. Zero leaves them unbounded.
+func WithConcurrency(n int) Option {
+	return func(s *IngestService) {
+		if n > 0 {
+			s.sagaSlots = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithEnrichers registers enrichers run by the enrich stage.
+func WithEnrichers(enrichers ...Enricher) Option {
+	return func(s *IngestService) {
+		s.enrichers = append(s.enrichers, enrichers...)
+	}
+}
+
+// WithStage makes a custom stage available to ingest.pipeline under name,
+// or replaces a built-in one.
+func WithStage(name string, stage Middleware) Option {
+	return func(s *IngestService) {
+		s.stages[name] = stage
+	}
+}
+
+// WithNotifier enables webhook notifications for completed and failed sagas.
+func WithNotifier(notifier Notifier) Option {
+	return func(s *IngestService) {
+		s.notifier = notifier
+	}
+}
+
+// WithAppLookup enables app name resolution and, with
+// appstore.precheck_availability, the availability pre-check of App Store
+// storefronts.
+func WithAppLookup(lookup AppLookup) Option {
+	return func(s *IngestService) {
+		s.lookup = lookup
+	}
+}
+
+// WithStorefrontDiscoverer lets App Store requests for all countries expand
+// to the storefronts the app is available in rather than every storefront.
+func WithStorefrontDiscoverer(discoverer StorefrontDiscoverer) Option {
+	return func(s *IngestService) {
+		s.discoverer = discoverer
+	}
+}
+
+// acquireSaga waits for a free saga slot under WithConcurrency and returns
+// the function releasing it.
+func (s *IngestService) acquireSaga(ctx context.Context) (func(), error) {
+	if s.sagaSlots == nil {
+		return func() {}, nil
+	}
+	select {
+	case s.sagaSlots <- struct{}{}:
+		return func() { <-s.sagaSlots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
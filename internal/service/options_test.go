@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+func TestNewIngestServiceOptions(t *testing.T) {
+	s := NewIngestService(nil, nil, nil, nil, nil, nil, nil, WithLimit(50), WithConcurrency(1), WithIngestConfig(config.IngestConfig{Pipeline: []string{config.StageEnrich}}))
+	if s.limit != 50 {
+		t.Errorf("limit = %d, want 50", s.limit)
+	}
+	if err := s.ValidatePipeline(); err != nil {
+		t.Errorf("configured pipeline: %v", err)
+	}
+
+	release, err := s.acquireSaga(context.Background())
+	if err != nil {
+		t.Fatalf("first saga did not start: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.acquireSaga(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("second saga started while the only slot was taken: %v", err)
+	}
+	release()
+	if _, err := s.acquireSaga(context.Background()); err != nil {
+		t.Errorf("saga did not start after the slot was released: %v", err)
+	}
+
+	if s := NewIngestService(nil, nil, nil, nil, nil, nil, nil); s.limit != Limit || s.sagaSlots != nil {
+		t.Errorf("defaults: limit = %d, slots = %v; want %d and unbounded", s.limit, s.sagaSlots, Limit)
+	}
+}
+
+// upperTitle is an enricher upper-casing review titles.
+type upperTitle struct{}
+
+func (upperTitle) Enrich(_ context.Context, row *storage.RawReview) {
+	row.Title = strings.ToUpper(row.Title)
+}
+
+func TestWithEnrichersAndStage(t *testing.T) {
+	var seen []string
+	record := func(next BatchHandler) BatchHandler {
+		return func(ctx context.Context, batch *ReviewBatch) error {
+			for _, item := range batch.Items {
+				seen = append(seen, item.Row.Title)
+			}
+			return next(ctx, batch)
+		}
+	}
+	s := NewIngestService(nil, nil, nil, nil, nil, nil, nil,
+		WithIngestConfig(config.IngestConfig{Pipeline: []string{config.StageEnrich, "record"}}),
+		WithEnrichers(upperTitle{}),
+		WithStage("record", record))
+
+	handler, err := s.pipeline()
+	if err != nil {
+		t.Fatalf("pipeline: %v", err)
+	}
+	batch := &ReviewBatch{Items: []BatchItem{{Row: storage.RawReview{Title: "great app"}}}}
+	if err := handler(context.Background(), batch); err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	if len(seen) != 1 || seen[0] != "GREAT APP" {
+		t.Errorf("titles after enrich = %v, want [GREAT APP]", seen)
+	}
+}
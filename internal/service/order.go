@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+// orderCountries reorders countries by their stored review volume according
+// to ingest.country_order. Failing to read the volumes keeps the requested
+// order.
+func (s *IngestService) orderCountries(ctx context.Context, req Request, countries []string) []string {
+	if s.ingestCfg.CountryOrder == config.CountryOrderRequest || len(countries) < 2 {
+		return countries
+	}
+	counts, err := s.repo.CountByCountry(ctx, req.Store, req.AppID)
+	if err != nil {
+		logger.Warn(ctx, "Failed to read review volumes, keeping requested country order", "error", err.Error())
+		return countries
+	}
+
+	ordered := sortByVolume(countries, counts, s.ingestCfg.CountryOrder == config.CountryOrderLargestFirst)
+	logger.LogEvent(ctx, "service.countries.ordered", "success", "order", s.ingestCfg.CountryOrder, "countries", strings.Join(ordered, ","))
+	return ordered
+}
+
+// sortByVolume returns countries sorted by counts, largest or smallest first.
+// Countries are matched case-insensitively and those with equal volume keep
+// their requested order.
+func sortByVolume(countries []string, counts map[string]int, largestFirst bool) []string {
+	volume := make(map[string]int, len(counts))
+	for country, count := range counts {
+		volume[strings.ToLower(country)] += count
+	}
+
+	ordered := slices.Clone(countries)
+	slices.SortStableFunc(ordered, func(a, b string) int {
+		ca, cb := volume[strings.ToLower(a)], volume[strings.ToLower(b)]
+		if largestFirst {
+			return cb - ca
+		}
+		return ca - cb
+	})
+	return ordered
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortByVolume(t *testing.T) {
+	countries := []string{"fr", "US", "de", "gb"}
+	counts := map[string]int{"us": 900, "GB": 300, "fr": 300}
+
+	if got, want := sortByVolume(countries, counts, true), []string{"US", "fr", "gb", "de"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("largest first = %v, want %v", got, want)
+	}
+	if got, want := sortByVolume(countries, counts, false), []string{"de", "fr", "gb", "US"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("smallest first = %v, want %v", got, want)
+	}
+	if countries[0] != "fr" {
+		t.Errorf("input reordered: %v", countries)
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/features"
+	"github.com/quiby-ai/review-ingestor/internal/httpclient"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+// sagaSettings are the politeness and enrichment settings of one saga: the
+// global configuration with the app's overrides applied.
+type sagaSettings struct {
+	maxReviews      int
+	pageDelay       time.Duration
+	pageDelayJitter time.Duration
+	proxy           *url.URL
+	translate       bool
+	hooks           bool
+	// tenant is who the saga's usage is accounted to.
+	tenant string
+	// fingerprints maps the fingerprints of the reviews seen so far in the
+	// saga to their country, for ingest.cross_country_dedup. It is shared by
+	// the saga's countries.
+	fingerprints map[string]string
+	// token authorizes the saga's App Store requests.
+	token string
+}
+
+// resolveSettings applies the overrides configured for the app to the
+// global settings, and the request's own max_reviews below that. The
+// returned context carries the saga's feature flags and routes its store
+// requests through the app's proxy, if it has one.
+func (s *IngestService) resolveSettings(ctx context.Context, req Request) (context.Context, sagaSettings) {
+	settings := sagaSettings{maxReviews: s.limit, translate: true, hooks: true, fingerprints: make(map[string]string)}
+	settings.pageDelay, settings.pageDelayJitter = s.pageDelays()
+
+	override, ok := s.apps.For(req.AppID)
+	settings.tenant = cmp.Or(req.Tenant, override.Tenant, s.accountingCfg.DefaultTenant)
+	flags := features.Resolve(s.features, override.Features)
+	ctx = features.With(ctx, flags)
+	if names := flags.Names(); len(names) > 0 {
+		logger.LogEvent(ctx, "service.features.resolved", "success", "enabled", names)
+	}
+	if override.MaxReviews > 0 {
+		settings.maxReviews = override.MaxReviews
+	}
+	if req.MaxReviews > 0 {
+		settings.maxReviews = min(settings.maxReviews, req.MaxReviews)
+	}
+	if !ok {
+		return ctx, settings
+	}
+	if override.PageDelay != nil {
+		settings.pageDelay = *override.PageDelay
+	}
+	if override.PageDelayJitter != nil {
+		settings.pageDelayJitter = *override.PageDelayJitter
+	}
+	if override.Translate != nil {
+		settings.translate = *override.Translate
+	}
+	if override.Hooks != nil {
+		settings.hooks = *override.Hooks
+	}
+	if override.Proxy != "" {
+		// config.Load rejects proxies that do not parse.
+		settings.proxy, _ = url.Parse(override.Proxy)
+		ctx = httpclient.WithProxy(ctx, settings.proxy)
+	}
+
+	logger.LogEvent(ctx, "service.app_override.applied", "success", "max_reviews", settings.maxReviews,
+		"page_delay_ms", settings.pageDelay.Milliseconds(), "proxy", settings.proxy != nil, "translate", settings.translate, "hooks", settings.hooks)
+	return ctx, settings
+}
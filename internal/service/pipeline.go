@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/appstore"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/producer"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// ReviewBatch is a page window of fetched reviews moving through the review
+// pipeline. Stages may drop items or change their rows; Inserted is set by
+// the persist stage.
+type ReviewBatch struct {
+	Request  Request
+	SagaID   string
+	Country  string
+	Items    []BatchItem
+	Inserted []storage.RawReview
+
+	stats    *producer.CountryStats
+	settings sagaSettings
+	// seenBodies carries body keys across the windows of a country for the
+	// repeated text check.
+	seenBodies map[string]string
+	// failedSaves, when set, collects the reviews of the country whose save
+	// failed with a transient error, for retryFailedSaves.
+	failedSaves *[]BatchItem
+}
+
+// BatchItem is a fetched review and, once normalized, the row stored for it.
+type BatchItem struct {
+	Review appstore.Review
+	Row    storage.RawReview
+}
+
+// BatchHandler processes a page window.
+type BatchHandler func(ctx context.Context, batch *ReviewBatch) error
+
+// Middleware is a pipeline stage. It does its work on the batch and calls
+// next to hand it on, or returns without calling next to stop it.
+type Middleware func(next BatchHandler) BatchHandler
+
+// Enricher adds to or rewrites a normalized row before it is stored, e.g.
+// sentiment or PII scrubbing. It runs in the enrich stage.
+type Enricher interface {
+	Enrich(ctx context.Context, row *storage.RawReview)
+}
+
+// builtinStages returns the stages ingest.pipeline can name out of the box.
+func (s *IngestService) builtinStages() map[string]Middleware {
+	return map[string]Middleware{
+		config.StageNormalize: s.normalizeStage,
+		config.StageDedupe:    s.dedupeStage,
+		config.StageEnrich:    s.enrichStage,
+		config.StagePersist:   s.persistStage,
+		config.StageEmit:      s.emitStage,
+	}
+}
+
+// pipeline chains the stages named in ingest.pipeline, in order.
+func (s *IngestService) pipeline() (BatchHandler, error) {
+	handler := func(context.Context, *ReviewBatch) error { return nil }
+	for i := len(s.ingestCfg.Pipeline) - 1; i >= 0; i-- {
+		stage, ok := s.stages[s.ingestCfg.Pipeline[i]]
+		if !ok {
+			return nil, fmt.Errorf("unknown pipeline stage %q", s.ingestCfg.Pipeline[i])
+		}
+		handler = stage(handler)
+	}
+	return handler, nil
+}
+
+// ValidatePipeline reports whether every stage of ingest.pipeline is known,
+// including stages added with WithStage.
+func (s *IngestService) ValidatePipeline() error {
+	_, err := s.pipeline()
+	return err
+}
+
+// normalizeStage drops reviews outside the requested version range and
+// converts the others into rows tagged with the saga. Reviews that cannot be converted are
+// quarantined.
+func (s *IngestService) normalizeStage(next BatchHandler) BatchHandler {
+	return func(ctx context.Context, batch *ReviewBatch) error {
+		items := batch.Items[:0]
+		for _, item := range batch.Items {
+			if !batch.Request.inVersionRange(item.Review.Attributes.AppVersion) {
+				batch.stats.OutOfRange++
+				continue
+			}
+			row, err := s.toRawReview(batch.Request.Store, batch.Request.AppID, batch.Country, item.Review)
+			if err != nil {
+				reviewCtx := logger.WithReviewID(ctx, item.Review.ID)
+				logger.Warn(reviewCtx, "Failed to parse review date", "error", err.Error())
+				if err := s.rejectReview(reviewCtx, batch, item.Review, err); err != nil {
+					return err
+				}
+				continue
+			}
+			row.IngestRunID = batch.SagaID
+			item.Row = row
+			items = append(items, item)
+		}
+		batch.Items = items
+		return next(ctx, batch)
+	}
+}
+
+// dedupeStage drops reviews repeated within the window, which overlapping
+// pages can return, and with ingest.cross_country_dedup copies of reviews
+// from the saga's other countries. It flags bodies already posted by another
+// review of the country as repeated.
+func (s *IngestService) dedupeStage(next BatchHandler) BatchHandler {
+	return func(ctx context.Context, batch *ReviewBatch) error {
+		seenIDs := make(map[string]bool, len(batch.Items))
+		items := batch.Items[:0]
+		for _, item := range batch.Items {
+			if seenIDs[item.Row.ID] {
+				batch.stats.Duplicates++
+				continue
+			}
+			seenIDs[item.Row.ID] = true
+			if s.ingestCfg.CrossCountryDedup && crossCountryCopy(batch.settings.fingerprints, batch.Country, item.Row) {
+				batch.stats.CrossCountry++
+				continue
+			}
+			flagRepeated(batch.seenBodies, &item.Row)
+			items = append(items, item)
+		}
+		batch.Items = items
+		return next(ctx, batch)
+	}
+}
+
+// enrichStage runs the registered enrichers on every row.
+func (s *IngestService) enrichStage(next BatchHandler) BatchHandler {
+	return func(ctx context.Context, batch *ReviewBatch) error {
+		for i := range batch.Items {
+			for _, enricher := range s.enrichers {
+				enricher.Enrich(ctx, &batch.Items[i].Row)
+			}
+		}
+		return next(ctx, batch)
+	}
+}
+
+// persistStage stores the rows, with one bulk load when ingest.bulk_load is
+// set and row by row otherwise or when the load fails. Stored reviews the
+// store reports as edited are updated. Rows that fail to save are
+// quarantined and the save error policy applies, except that with
+// ingest.retry_failed_saves transient failures are first set aside to be
+// retried once the country is fetched.
+func (s *IngestService) persistStage(next BatchHandler) BatchHandler {
+	return func(ctx context.Context, batch *ReviewBatch) error {
+		if s.ingestCfg.BulkLoad && len(batch.Items) > 0 && s.bulkSave(ctx, batch) {
+			return next(ctx, batch)
+		}
+
+		for _, item := range batch.Items {
+			reviewCtx := logger.WithReviewID(ctx, item.Row.ID)
+			isNew, err := s.saveRow(reviewCtx, item.Row, false)
+			switch {
+			case err != nil && batch.failedSaves != nil && storage.IsTransient(err):
+				*batch.failedSaves = append(*batch.failedSaves, item)
+			case err != nil:
+				if err := s.rejectReview(reviewCtx, batch, item.Review, err); err != nil {
+					// Reviews stored before the abort still reach the later
+					// stages.
+					next(ctx, batch)
+					return err
+				}
+			case isNew:
+				batch.stats.New++
+				batch.stats.Observe(item.Row.Rating, item.Row.ReviewedAt.UTC())
+				batch.Inserted = append(batch.Inserted, item.Row)
+			default:
+				batch.stats.Duplicates++
+				batch.stats.Observe(item.Row.Rating, item.Row.ReviewedAt.UTC())
+				s.applyEdit(reviewCtx, batch, item.Row)
+			}
+		}
+		return next(ctx, batch)
+	}
+}
+
+// emitStage hands the newly stored reviews to the post-save stages.
+func (s *IngestService) emitStage(next BatchHandler) BatchHandler {
+	return func(ctx context.Context, batch *ReviewBatch) error {
+		s.afterSave(ctx, batch)
+		return next(ctx, batch)
+	}
+}
+
+// rejectReview quarantines a review that could not be stored and counts it
+// as failed. It returns an error when the fail-fast policy aborts the
+// country.
+func (s *IngestService) rejectReview(ctx context.Context, batch *ReviewBatch, review appstore.Review, reason error) error {
+	s.quarantineReview(ctx, batch.Request.Store, batch.Request.AppID, batch.Country, review, reason)
+	batch.stats.Failed++
+	if s.ingestCfg.SaveErrorPolicy == config.SaveErrorPolicyFailFast {
+		return fmt.Errorf("aborting country %s after failed save: %w", batch.Country, reason)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/producer"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// publishReviews sends newly stored reviews to the review topic, one event
+// per review or one per page window depending on ingest.publish_reviews.
+// Publish failures are logged and never fail the saga, since the reviews are
+// already stored.
+func (s *IngestService) publishReviews(ctx context.Context, sagaID string, req Request, country string, rows []storage.RawReview) {
+	if len(rows) == 0 {
+		return
+	}
+
+	reviews := make([]producer.IngestedReview, len(rows))
+	for i, row := range rows {
+		reviews[i] = toIngestedReview(row)
+	}
+
+	var events []producer.ReviewsIngested
+	switch s.ingestCfg.PublishReviews {
+	case config.PublishReviewsBatch:
+		events = append(events, producer.ReviewsIngested{AppID: req.AppID, Store: req.Store, Country: country, Reviews: reviews})
+	case config.PublishReviewsReview:
+		for _, review := range reviews {
+			events = append(events, producer.ReviewsIngested{AppID: req.AppID, Store: req.Store, Country: country, Reviews: []producer.IngestedReview{review}})
+		}
+	default:
+		return
+	}
+
+	timer := logger.StartTimer()
+	failed := 0
+	for _, event := range events {
+		envelope := s.producer.BuildReviewsEnvelope(event, sagaID)
+		if err := s.producer.PublishEvent(ctx, []byte(req.AppID), envelope); err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		logger.LogEventWithLatency(ctx, "service.reviews.published", "failed", timer(), "country", country, "events", len(events), "failed", failed)
+		return
+	}
+	logger.LogEventWithLatency(ctx, "service.reviews.published", "success", timer(), "country", country, "events", len(events), "reviews", len(reviews))
+}
+
+// sampleReviews keeps the first new reviews of a country for completion
+// payloads with samples.
+func (s *IngestService) sampleReviews(batch *ReviewBatch) {
+	if s.ingestCfg.CompletionPayload != config.CompletionPayloadWithSamples {
+		return
+	}
+	for _, row := range batch.Inserted {
+		if len(batch.stats.Samples) >= s.ingestCfg.CompletionSamples {
+			return
+		}
+		batch.stats.Samples = append(batch.stats.Samples, toIngestedReview(row))
+	}
+}
+
+// toIngestedReview is the published form of a stored review.
+func toIngestedReview(row storage.RawReview) producer.IngestedReview {
+	return producer.IngestedReview{
+		ReviewID:        row.ID,
+		Rating:          row.Rating,
+		Title:           row.Title,
+		Content:         row.Content,
+		ReviewedAt:      row.ReviewedAt.UTC(),
+		Territory:       row.Territory,
+		AppVersion:      row.AppVersion,
+		ResponseDate:    row.ResponseDate,
+		ResponseContent: row.ResponseContent,
+	}
+}
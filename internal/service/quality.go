@@ -0,0 +1,41 @@
+package service
+
+import (
+	"github.com/quiby-ai/review-ingestor/internal/normalize"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// crossCountryCopy reports whether row repeats a review seen in another
+// country of the saga, recording its fingerprint otherwise. seen maps
+// fingerprints to the country they were first seen in. Rows with bodies too
+// short for normalize.QualityKey are never copies, so stock phrases such as
+// "great app" posted in several countries are all kept.
+func crossCountryCopy(seen map[string]string, country string, row storage.RawReview) bool {
+	if row.Fingerprint == "" || normalize.QualityKey(row.Content) == "" {
+		return false
+	}
+	first, ok := seen[row.Fingerprint]
+	if !ok {
+		seen[row.Fingerprint] = country
+		return false
+	}
+	return first != country
+}
+
+// flagRepeated marks a row as repeated when its body was already posted by
+// another review of the same country run, unless a more specific flag
+// applies. seen maps body keys to the first review that used them and is
+// carried across page windows.
+func flagRepeated(seen map[string]string, row *storage.RawReview) {
+	key := normalize.QualityKey(row.Content)
+	if key == "" {
+		return
+	}
+	first, ok := seen[key]
+	switch {
+	case !ok:
+		seen[key] = row.ID
+	case first != row.ID && row.QualityFlag == "":
+		row.QualityFlag = normalize.QualityRepeated
+	}
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/quiby-ai/review-ingestor/internal/appstore"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/producer"
+)
+
+// ReplayPage runs an archived App Store review page through the same parsing,
+// sanitization and storage as a live fetch, without contacting Apple. With
+// overwrite, reviews that are already stored are replaced so parsing fixes
+// are backfilled. Reviews that were already stored count as duplicates either
+// way.
+func (s *IngestService) ReplayPage(ctx context.Context, appID, country string, body []byte, overwrite bool) (producer.CountryStats, error) {
+	stats := producer.CountryStats{Country: country}
+
+	var page appstore.ReviewsResponse
+	if err := json.Unmarshal(body, &page); err != nil {
+		return stats, fmt.Errorf("failed to parse archived page: %w", err)
+	}
+
+	for _, review := range page.Data {
+		reviewCtx := logger.WithReviewID(ctx, review.ID)
+		stats.Fetched++
+
+		inserted, err := s.saveReview(reviewCtx, StoreAppStore, appID, country, review, overwrite)
+		switch {
+		case err != nil:
+			s.quarantineReview(reviewCtx, StoreAppStore, appID, country, review, err)
+			stats.Failed++
+		case inserted:
+			stats.New++
+			s.observeReview(&stats, review)
+		default:
+			stats.Duplicates++
+			s.observeReview(&stats, review)
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/fetcher"
+
+	"github.com/quiby-ai/common/pkg/events"
+)
+
+const (
+	StoreAppStore   = fetcher.StoreAppStore
+	StoreAmazon     = fetcher.StoreAmazon
+	StoreHuawei     = fetcher.StoreHuawei
+	StoreSteam      = fetcher.StoreSteam
+	StoreTrustpilot = fetcher.StoreTrustpilot
+	StoreMicrosoft  = fetcher.StoreMicrosoft
+	StoreGalaxy     = fetcher.StoreGalaxy
+	// StoreGooglePlay has no built-in fetcher; code embedding the service
+	// registers one on the fetcher registry it passes to NewIngestService.
+	// App IDs are package names.
+	StoreGooglePlay = "googleplay"
+)
+
+// Request is an extract request together with the review store it targets
+// and the ingestor-specific options the shared events.ExtractRequest lacks.
+// Requests without a store default to the App Store.
+type Request struct {
+	events.ExtractRequest
+	Store string `json:"store,omitempty"`
+	// MinVersion and MaxVersion, when set, keep only reviews written for app
+	// versions in [MinVersion, MaxVersion]. Reviews without version metadata
+	// are skipped while a bound is set.
+	MinVersion string `json:"min_version,omitempty"`
+	MaxVersion string `json:"max_version,omitempty"`
+	// MaxReviews, when set, caps the reviews fetched per storefront below
+	// the configured limit.
+	MaxReviews int `json:"max_reviews,omitempty"`
+	// Tenant, when set, is who the saga's usage is accounted to, ahead of
+	// the app's configured tenant.
+	Tenant string `json:"tenant,omitempty"`
+	// Stores, when set, fans the request out to every listed store selling
+	// the same product, e.g. ["appstore", "googleplay"], in place of Store.
+	// AppIDs maps a store to the product's app ID there; stores without an
+	// entry use AppID.
+	Stores []string          `json:"stores,omitempty"`
+	AppIDs map[string]string `json:"app_ids,omitempty"`
+	// StartOffset and StartBefore, when set, start every storefront at that
+	// offset (a review offset for the App Store, a page for other stores)
+	// and skip reviews written after StartBefore, to step over a known-bad
+	// range or data ingested by other means.
+	StartOffset int    `json:"start_offset,omitempty"`
+	StartBefore string `json:"start_before,omitempty"`
+}
+
+// ForStore returns the single-store request for one store of a multi-store
+// request.
+func (r Request) ForStore(store string) Request {
+	leg := r
+	leg.Store = store
+	if appID := r.AppIDs[store]; appID != "" {
+		leg.AppID = appID
+	}
+	leg.Stores, leg.AppIDs = nil, nil
+	return leg
+}
+
+// IgnoresCountry reports whether store returns the same reviews whatever the
+// country, so a request for it must name a single country to store them
+// under.
+func IgnoresCountry(store string) bool {
+	return store == StoreSteam
+}
+
+// AllCountries, as the only entry of countries, asks for every storefront
+// the app is available in.
+const AllCountries = "all"
+
+// WantsAllCountries reports whether r asks for every available storefront.
+func (r Request) WantsAllCountries() bool {
+	return len(r.Countries) == 1 && strings.EqualFold(r.Countries[0], AllCountries)
+}
+
+// requestDateLayouts are the accepted formats of date_from and date_to, tried
+// in order.
+var requestDateLayouts = []string{"2006-01-02", time.RFC3339, "2006-01-02T15:04:05"}
+
+// ParseRequestDate parses a request date in any accepted layout. Dates
+// without an offset are taken as UTC.
+func ParseRequestDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range requestDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("date %q is not YYYY-MM-DD or RFC 3339", value)
+}
+
+// WithDefaultWindow fills a missing date_from with the date window before
+// now. A zero window leaves the request unchanged, so Validate rejects it.
+func (r Request) WithDefaultWindow(window time.Duration, now time.Time) Request {
+	if strings.TrimSpace(r.DateFrom) == "" && window > 0 {
+		r.DateFrom = now.Add(-window).UTC().Format("2006-01-02")
+	}
+	return r
+}
+
+// NewRequest wraps evt for the given store, defaulting to the App Store.
+func NewRequest(evt events.ExtractRequest, store string) Request {
+	if store == "" {
+		store = StoreAppStore
+	}
+	return Request{ExtractRequest: evt, Store: store}
+}
+
+// Validate checks the request before a saga starts. The validation tags of
+// the embedded events.ExtractRequest are not used: they require date_to and
+// YYYY-MM-DD dates, which the ingestor does not.
+func (r Request) Validate() error {
+	if r.Store == "" && len(r.Stores) == 0 {
+		return fmt.Errorf("store is required")
+	}
+	seen := make(map[string]bool, len(r.Stores))
+	for _, store := range r.Stores {
+		if store == "" || seen[store] {
+			return fmt.Errorf("stores must be distinct and not empty")
+		}
+		seen[store] = true
+		if strings.TrimSpace(r.ForStore(store).AppID) == "" {
+			return fmt.Errorf("app_id is required for store %s", store)
+		}
+	}
+	if len(r.Stores) == 0 && strings.TrimSpace(r.AppID) == "" {
+		return fmt.Errorf("app_id is required")
+	}
+	for _, store := range append([]string{r.Store}, r.Stores...) {
+		if IgnoresCountry(store) && len(r.Countries) > 1 {
+			return fmt.Errorf("store %s is not split by country and takes a single country", store)
+		}
+	}
+
+	if len(r.Countries) == 0 {
+		return fmt.Errorf("countries must not be empty")
+	}
+	for _, country := range r.Countries {
+		if !isCountryCode(country) && !r.WantsAllCountries() {
+			return fmt.Errorf("country %q is not an ISO 3166 alpha-2 code", country)
+		}
+	}
+
+	if strings.TrimSpace(r.DateFrom) == "" {
+		return fmt.Errorf("date_from is required")
+	}
+	from, err := ParseRequestDate(r.DateFrom)
+	if err != nil {
+		return fmt.Errorf("invalid date_from: %w", err)
+	}
+	if r.DateTo != "" {
+		to, err := ParseRequestDate(r.DateTo)
+		if err != nil {
+			return fmt.Errorf("invalid date_to: %w", err)
+		}
+		if to.Before(from) {
+			return fmt.Errorf("date_to %s is before date_from %s", r.DateTo, r.DateFrom)
+		}
+	}
+
+	if r.StartOffset < 0 {
+		return fmt.Errorf("start_offset must not be negative")
+	}
+	if r.StartBefore != "" {
+		if _, err := ParseRequestDate(r.StartBefore); err != nil {
+			return fmt.Errorf("invalid start_before: %w", err)
+		}
+	}
+
+	var minVersion, maxVersion []int
+	if r.MinVersion != "" {
+		if minVersion, err = parseVersion(r.MinVersion); err != nil {
+			return fmt.Errorf("invalid min_version: %w", err)
+		}
+	}
+	if r.MaxVersion != "" {
+		if maxVersion, err = parseVersion(r.MaxVersion); err != nil {
+			return fmt.Errorf("invalid max_version: %w", err)
+		}
+	}
+	if minVersion != nil && maxVersion != nil && compareVersions(minVersion, maxVersion) > 0 {
+		return fmt.Errorf("min_version %s is above max_version %s", r.MinVersion, r.MaxVersion)
+	}
+	return nil
+}
+
+func isCountryCode(s string) bool {
+	if len(s) != 2 {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// inVersionRange reports whether a review for version passes the request's
+// version bounds.
+func (r Request) inVersionRange(version string) bool {
+	if r.MinVersion == "" && r.MaxVersion == "" {
+		return true
+	}
+	v, err := parseVersion(version)
+	if err != nil {
+		return false
+	}
+	if r.MinVersion != "" {
+		if minVersion, err := parseVersion(r.MinVersion); err == nil && compareVersions(v, minVersion) < 0 {
+			return false
+		}
+	}
+	if r.MaxVersion != "" {
+		if maxVersion, err := parseVersion(r.MaxVersion); err == nil && compareVersions(v, maxVersion) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// parseVersion splits a dotted numeric version such as "4.12.1".
+func parseVersion(version string) ([]int, error) {
+	version = strings.TrimSpace(version)
+	if version == "" {
+		return nil, fmt.Errorf("empty version")
+	}
+	parts := strings.Split(version, ".")
+	segments := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("version %q is not dotted numbers", version)
+		}
+		segments[i] = n
+	}
+	return segments, nil
+}
+
+// compareVersions orders versions segment by segment, treating missing
+// segments as zero so "1.2" equals "1.2.0".
+func compareVersions(a, b []int) int {
+	for i := 0; i < max(len(a), len(b)); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quiby-ai/common/pkg/events"
+)
+
+func TestRequestInVersionRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max string
+		version  string
+		want     bool
+	}{
+		{name: "no bounds", version: "", want: true},
+		{name: "inside", min: "4.2", max: "4.10", version: "4.9.1", want: true},
+		{name: "numeric not lexical", min: "4.2", max: "4.10", version: "4.10", want: true},
+		{name: "below min", min: "4.2", version: "4.1.9", want: false},
+		{name: "above max", max: "4.10", version: "4.10.1", want: false},
+		{name: "trailing zero equals", min: "4.2.0", version: "4.2", want: true},
+		{name: "missing version", min: "1.0", version: "", want: false},
+		{name: "unparsable version", max: "2.0", version: "1.0-beta", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := Request{MinVersion: tt.min, MaxVersion: tt.max}
+			if got := req.inVersionRange(tt.version); got != tt.want {
+				t.Errorf("inVersionRange(%q) = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRequestDate(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "2025-03-01", want: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "2025-03-01T10:00:00+02:00", want: time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)},
+		{value: "2025-03-01T10:00:00", want: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)},
+		{value: "", wantErr: true},
+		{value: "01/03/2025", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRequestDate(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseRequestDate(%q) = %v, want error", tt.value, got)
+			}
+			continue
+		}
+		if err != nil || !got.Equal(tt.want) || got.Location() != time.UTC {
+			t.Errorf("ParseRequestDate(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestRequestWithDefaultWindow(t *testing.T) {
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	if got := (Request{}).WithDefaultWindow(0, now).DateFrom; got != "" {
+		t.Errorf("zero window set date_from %q", got)
+	}
+	if got := (Request{}).WithDefaultWindow(30*24*time.Hour, now).DateFrom; got != "2025-03-01" {
+		t.Errorf("date_from = %q, want 2025-03-01", got)
+	}
+	req := Request{}
+	req.DateFrom = "2024-01-01"
+	if got := req.WithDefaultWindow(time.Hour, now).DateFrom; got != "2024-01-01" {
+		t.Errorf("explicit date_from replaced with %q", got)
+	}
+}
+
+func TestRequestWantsAllCountries(t *testing.T) {
+	cases := map[string]struct {
+		countries []string
+		want      bool
+	}{
+		"all":          {[]string{"all"}, true},
+		"upper case":   {[]string{"ALL"}, true},
+		"with country": {[]string{"all", "us"}, false},
+		"country":      {[]string{"us"}, false},
+		"empty":        {nil, false},
+	}
+	for name, tc := range cases {
+		var req Request
+		req.Countries = tc.countries
+		if got := req.WantsAllCountries(); got != tc.want {
+			t.Errorf("%s: WantsAllCountries() = %v, want %v", name, got, tc.want)
+		}
+	}
+}
+
+func TestRequestValidate(t *testing.T) {
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+	cases := map[string]struct {
+		evt     events.ExtractRequest
+		wantErr bool
+	}{
+		"dates":               {evt: events.ExtractRequest{AppID: "123", Countries: []string{"us"}, DateFrom: "2025-01-01", DateTo: "2025-02-01"}},
+		"rfc 3339 dates":      {evt: events.ExtractRequest{AppID: "123", Countries: []string{"us"}, DateFrom: "2025-01-01T10:00:00Z", DateTo: "2025-02-01T00:00:00+02:00"}},
+		"no date_to":          {evt: events.ExtractRequest{AppID: "123", Countries: []string{"us"}, DateFrom: "2025-01-01"}},
+		"default window":      {evt: events.ExtractRequest{AppID: "123", Countries: []string{"us", "gb"}}},
+		"no app name":         {evt: events.ExtractRequest{AppID: "123", Countries: []string{"us"}, DateFrom: "2025-01-01"}},
+		"all countries":       {evt: events.ExtractRequest{AppID: "123", Countries: []string{"all"}, DateFrom: "2025-01-01"}},
+		"all with country":    {evt: events.ExtractRequest{AppID: "123", Countries: []string{"all", "us"}, DateFrom: "2025-01-01"}, wantErr: true},
+		"no app_id":           {evt: events.ExtractRequest{Countries: []string{"us"}, DateFrom: "2025-01-01"}, wantErr: true},
+		"no countries":        {evt: events.ExtractRequest{AppID: "123", DateFrom: "2025-01-01"}, wantErr: true},
+		"bad country":         {evt: events.ExtractRequest{AppID: "123", Countries: []string{"usa"}, DateFrom: "2025-01-01"}, wantErr: true},
+		"bad date":            {evt: events.ExtractRequest{AppID: "123", Countries: []string{"us"}, DateFrom: "01/01/2025"}, wantErr: true},
+		"date_to before from": {evt: events.ExtractRequest{AppID: "123", Countries: []string{"us"}, DateFrom: "2025-02-01", DateTo: "2025-01-01"}, wantErr: true},
+	}
+	for name, tc := range cases {
+		req := NewRequest(tc.evt, "").WithDefaultWindow(30*24*time.Hour, now)
+		if err := req.Validate(); (err != nil) != tc.wantErr {
+			t.Errorf("%s: Validate() = %v, want error %v", name, err, tc.wantErr)
+		}
+	}
+
+	if err := NewRequest(events.ExtractRequest{AppID: "123", Countries: []string{"us"}}, "").Validate(); err == nil {
+		t.Error("Validate() accepted a request without date_from and no default window")
+	}
+
+	steam := NewRequest(events.ExtractRequest{AppID: "570", Countries: []string{"us", "gb"}, DateFrom: "2025-01-01"}, StoreSteam)
+	if err := steam.Validate(); err == nil {
+		t.Error("Validate() accepted a Steam request for several countries")
+	}
+	steam.Countries = []string{"us"}
+	if err := steam.Validate(); err != nil {
+		t.Errorf("Validate() of a single-country Steam request = %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/producer"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+const (
+	suspendReasonPostgres = "postgres_unavailable"
+	suspendReasonKafka    = "kafka_unavailable"
+)
+
+// SuspendedSagaStore keeps checkpoints of sagas interrupted by an outage
+// somewhere that does not depend on Postgres or Kafka.
+type SuspendedSagaStore interface {
+	Save(saga storage.SuspendedSaga) error
+	List() ([]storage.SuspendedSaga, error)
+	Remove(sagaID string) error
+}
+
+// HealthCheck reports whether a dependency is reachable.
+type HealthCheck func(ctx context.Context) error
+
+// WithSuspendedSagaStore enables suspending sagas when Postgres or Kafka
+// become unavailable instead of failing them.
+func WithSuspendedSagaStore(store SuspendedSagaStore) Option {
+	return func(s *IngestService) {
+		s.suspended = store
+	}
+}
+
+// suspend checkpoints a saga that failed because Postgres or Kafka were
+// unreachable. It reports whether the saga was suspended; other failures,
+// and any failure without a store, are left to the caller.
+func (s *IngestService) suspend(ctx context.Context, req Request, sagaID, reason string, remaining []string, stats []producer.CountryStats, timer func() time.Duration) bool {
+	if s.suspended == nil {
+		return false
+	}
+
+	saga, err := s.newCheckpoint(ctx, req, sagaID, remaining, stats)
+	if err == nil {
+		saga.ParkedAt = time.Now().UTC()
+		err = s.suspended.Save(storage.SuspendedSaga{ParkedSaga: saga, Reason: reason})
+	}
+	if err != nil {
+		logger.Error(ctx, "Failed to checkpoint suspended saga", err, "reason", reason)
+		return false
+	}
+
+	s.runs.setStatus(sagaID, RunStatusParked)
+	logger.LogEventWithLatency(ctx, "service.ingest.suspended", "success", timer(), "reason", reason, "remaining_countries", len(remaining))
+	return true
+}
+
+// suspendReason classifies err as an outage worth suspending the saga for.
+func suspendReason(err error) (string, bool) {
+	if storage.IsUnavailable(err) {
+		return suspendReasonPostgres, true
+	}
+	return "", false
+}
+
+// RunResumer checks the dependencies every ingest.resume_check_interval and,
+// once all of them pass, emits an ExtractResume event for every suspended
+// saga. It returns when ctx is cancelled.
+func (s *IngestService) RunResumer(ctx context.Context, checks ...HealthCheck) error {
+	if s.suspended == nil || s.ingestCfg.ResumeCheckInterval <= 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(s.ingestCfg.ResumeCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.emitResumes(ctx, checks)
+		}
+	}
+}
+
+func (s *IngestService) emitResumes(ctx context.Context, checks []HealthCheck) {
+	sagas, err := s.suspended.List()
+	if err != nil {
+		logger.Error(ctx, "Failed to read suspended sagas", err)
+	}
+	if len(sagas) == 0 {
+		return
+	}
+
+	for _, check := range checks {
+		if err := check(ctx); err != nil {
+			logger.Debug(ctx, "Dependencies still unavailable, keeping sagas suspended", "suspended", len(sagas), "error", err.Error())
+			return
+		}
+	}
+
+	for _, saga := range sagas {
+		sagaCtx := logger.WithAppID(logger.WithSagaID(ctx, saga.SagaID), saga.AppID)
+
+		var progress []producer.CountryStats
+		if err := json.Unmarshal(saga.Progress, &progress); err != nil {
+			logger.Error(sagaCtx, "Failed to decode suspended saga progress", err)
+			continue
+		}
+		event := producer.ExtractResume{
+			Request:            saga.Request,
+			Countries:          progress,
+			RemainingCountries: saga.RemainingCountries,
+			Reason:             saga.Reason,
+			SuspendedAt:        saga.ParkedAt,
+		}
+		envelope := s.producer.BuildResumeEnvelope(event, saga.SagaID)
+		if err := s.producer.PublishEvent(sagaCtx, []byte(saga.SagaID), envelope); err != nil {
+			logger.LogEvent(sagaCtx, "service.ingest.resume_emitted", "failed", "reason", saga.Reason, "error", err.Error())
+			return
+		}
+		if err := s.suspended.Remove(saga.SagaID); err != nil {
+			logger.Error(sagaCtx, "Failed to remove resumed saga checkpoint", err)
+		}
+		logger.LogEvent(sagaCtx, "service.ingest.resume_emitted", "success", "reason", saga.Reason, "remaining_countries", len(saga.RemainingCountries))
+	}
+}
+
+// Resume continues a saga from an ExtractResume event.
+func (s *IngestService) Resume(ctx context.Context, event producer.ExtractResume, sagaID string) error {
+	var req checkpointRequest
+	if err := json.Unmarshal(event.Request, &req); err != nil {
+		return fmt.Errorf("failed to decode resumed request: %w", err)
+	}
+	if err := s.checkStores(req.Request); err != nil {
+		return err
+	}
+	if run, ok := s.runs.get(sagaID); ok && run.Status == RunStatusRunning {
+		// A redelivered event while the saga already runs here again.
+		return nil
+	}
+
+	logger.LogEvent(ctx, "service.ingest.resumed", "in_progress", "reason", event.Reason, "remaining_countries", len(event.RemainingCountries), "completed_countries", len(event.Countries))
+	return s.continueSaga(ctx, req, sagaID, event.RemainingCountries, event.Countries)
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/producer"
+	"github.com/quiby-ai/review-ingestor/internal/webhook"
+)
+
+const (
+	RunStatusRunning   = "running"
+	RunStatusCompleted = "completed"
+	RunStatusFailed    = "failed"
+	RunStatusParked    = "parked"
+	RunStatusCancelled = "cancelled"
+)
+
+// maxFinishedRuns bounds how many finished runs are kept for status queries.
+const maxFinishedRuns = 100
+
+var (
+	ErrRunNotFound  = errors.New("run not found")
+	ErrRunNotActive = errors.New("run is not running")
+	// ErrRunActive is returned when rolling back a run still running here.
+	ErrRunActive = errors.New("run is still running")
+	// ErrRunCancelled is the cancellation cause of a run stopped by CancelRun.
+	ErrRunCancelled = errors.New("run cancelled")
+)
+
+// Run is the status of a saga handled by this instance.
+type Run struct {
+	SagaID     string
+	AppID      string
+	Store      string
+	Countries  []string
+	Status     string
+	Error      string
+	Country    string
+	Fetched    int
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+type trackedRun struct {
+	run      Run
+	cancel   context.CancelCauseFunc
+	progress *sagaProgress
+	// heartbeats is the sequence of the last heartbeat, carried across the
+	// stores of a multi-store saga and, through the checkpoint, resumes.
+	heartbeats int
+}
+
+// runRegistry keeps the runs started on this instance so they can be
+// inspected and cancelled. It is in-memory only.
+type runRegistry struct {
+	mu       sync.Mutex
+	runs     map[string]*trackedRun
+	finished []string
+}
+
+func newRunRegistry() *runRegistry {
+	return &runRegistry{runs: make(map[string]*trackedRun)}
+}
+
+func (r *runRegistry) start(sagaID string, req Request, cancel context.CancelCauseFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs[sagaID] = &trackedRun{
+		run: Run{
+			SagaID:    sagaID,
+			AppID:     req.AppID,
+			Store:     req.Store,
+			Countries: req.Countries,
+			Status:    RunStatusRunning,
+			StartedAt: time.Now(),
+		},
+		cancel: cancel,
+	}
+}
+
+func (r *runRegistry) attach(sagaID string, progress *sagaProgress) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if tracked, ok := r.runs[sagaID]; ok {
+		tracked.progress = progress
+	}
+}
+
+// nextHeartbeat returns the sequence of the saga's next heartbeat.
+func (r *runRegistry) nextHeartbeat(sagaID string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tracked, ok := r.runs[sagaID]
+	if !ok {
+		return 0
+	}
+	tracked.heartbeats++
+	return tracked.heartbeats
+}
+
+// heartbeats returns the sequence of the saga's last heartbeat.
+func (r *runRegistry) heartbeats(sagaID string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if tracked, ok := r.runs[sagaID]; ok {
+		return tracked.heartbeats
+	}
+	return 0
+}
+
+// resumeHeartbeats continues the heartbeat sequence of a checkpointed saga
+// after last.
+func (r *runRegistry) resumeHeartbeats(sagaID string, last int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if tracked, ok := r.runs[sagaID]; ok {
+		tracked.heartbeats = max(tracked.heartbeats, last)
+	}
+}
+
+func (r *runRegistry) setStatus(sagaID, status string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if tracked, ok := r.runs[sagaID]; ok {
+		tracked.run.Status = status
+	}
+}
+
+// finish records the outcome of a run. A run that parked or was cancelled
+// keeps that status.
+func (r *runRegistry) finish(sagaID string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tracked, ok := r.runs[sagaID]
+	if !ok {
+		return
+	}
+
+	tracked.snapshotProgress()
+	tracked.progress = nil
+	tracked.cancel = nil
+	tracked.run.FinishedAt = time.Now()
+	if err != nil {
+		tracked.run.Error = err.Error()
+	}
+	if tracked.run.Status == RunStatusRunning {
+		tracked.run.Status = RunStatusCompleted
+		if err != nil {
+			tracked.run.Status = RunStatusFailed
+		}
+	}
+
+	r.finished = append(r.finished, sagaID)
+	if len(r.finished) > maxFinishedRuns {
+		delete(r.runs, r.finished[0])
+		r.finished = r.finished[1:]
+	}
+}
+
+func (r *runRegistry) get(sagaID string) (Run, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tracked, ok := r.runs[sagaID]
+	if !ok {
+		return Run{}, false
+	}
+	tracked.snapshotProgress()
+	return tracked.run, true
+}
+
+// list returns the runs for appID (all apps when empty), newest first.
+func (r *runRegistry) list(appID string) []Run {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	runs := make([]Run, 0, len(r.runs))
+	for _, tracked := range r.runs {
+		if appID != "" && tracked.run.AppID != appID {
+			continue
+		}
+		tracked.snapshotProgress()
+		runs = append(runs, tracked.run)
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].StartedAt.After(runs[j].StartedAt) })
+	return runs
+}
+
+func (r *runRegistry) cancel(sagaID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tracked, ok := r.runs[sagaID]
+	if !ok {
+		return ErrRunNotFound
+	}
+	if tracked.run.Status != RunStatusRunning || tracked.cancel == nil {
+		return ErrRunNotActive
+	}
+	tracked.run.Status = RunStatusCancelled
+	tracked.cancel(ErrRunCancelled)
+	return nil
+}
+
+func (t *trackedRun) snapshotProgress() {
+	if t.progress == nil {
+		return
+	}
+	progress := t.progress.snapshot(t.run.AppID)
+	t.run.Country = progress.Country
+	t.run.Fetched = progress.Fetched
+}
+
+// track runs fn as the saga sagaID under a cancellable context registered
+// with the run registry, once a saga slot is free.
+func (s *IngestService) track(ctx context.Context, req Request, sagaID string, fn func(context.Context) error) error {
+	release, err := s.acquireSaga(ctx)
+	if err != nil {
+		return fmt.Errorf("saga %s did not start: %w", sagaID, err)
+	}
+	defer release()
+
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	s.runs.start(sagaID, req, cancel)
+	err = fn(runCtx)
+	s.runs.finish(sagaID, err)
+
+	if err != nil && !isCancelled(runCtx) {
+		s.notify(ctx, webhook.Payload{
+			Event:  webhook.EventFailed,
+			SagaID: sagaID,
+			AppID:  req.AppID,
+			Store:  req.Store,
+			Error:  err.Error(),
+		})
+	}
+	return err
+}
+
+// Trigger starts ingesting req in the background, outside of Kafka, and
+// returns the saga ID it runs under.
+func (s *IngestService) Trigger(ctx context.Context, req Request) (string, error) {
+	req = req.WithDefaultWindow(s.ingestCfg.DefaultWindow, time.Now())
+	if err := req.Validate(); err != nil {
+		return "", err
+	}
+	if err := s.checkStores(req); err != nil {
+		return "", err
+	}
+
+	sagaID := uuid.NewString()
+	runCtx := logger.WithAppID(logger.WithSagaID(logger.WithTraceID(context.WithoutCancel(ctx), ""), sagaID), req.AppID)
+	go func() {
+		if err := s.Handle(runCtx, req, sagaID); err != nil {
+			logger.Error(runCtx, "Triggered ingestion failed", err)
+		}
+	}()
+	return sagaID, nil
+}
+
+// Ingest runs a single-store request like Handle but returns the stats of
+// its countries instead of publishing a completion event, so synthetic
+// sagas such as benchmarks stay off the completion topic.
+func (s *IngestService) Ingest(ctx context.Context, req Request, sagaID string) ([]producer.CountryStats, error) {
+	if len(req.Stores) > 0 {
+		return nil, fmt.Errorf("multi-store requests must go through Handle")
+	}
+	collected := &storeLegs{}
+	if err := s.Handle(context.WithValue(ctx, storeLegsKey{}, collected), req, sagaID); err != nil {
+		return nil, err
+	}
+	if len(collected.legs) == 0 {
+		return nil, fmt.Errorf("saga %s did not complete", sagaID)
+	}
+	return collected.legs[0].Countries, nil
+}
+
+// GetRun returns the status of a run started on this instance.
+func (s *IngestService) GetRun(sagaID string) (Run, bool) {
+	return s.runs.get(sagaID)
+}
+
+// ListRuns returns the recent runs on this instance for appID, or for every
+// app when appID is empty, newest first.
+func (s *IngestService) ListRuns(appID string) []Run {
+	return s.runs.list(appID)
+}
+
+// CancelRun cancels a running saga on this instance. The saga checkpoints its
+// progress and publishes an ExtractCancelled event.
+func (s *IngestService) CancelRun(sagaID string) error {
+	return s.runs.cancel(sagaID)
+}
+
+// RollbackRun undoes the reviews and aggregates stored by sagaID, for saga
+// compensation once a later step decides the extraction must be undone.
+// Reviews are flagged as rolled back, or deleted with purge. A run still
+// running on this instance must be cancelled first.
+func (s *IngestService) RollbackRun(ctx context.Context, sagaID string, purge bool) (int64, error) {
+	ctx = logger.WithSagaID(ctx, sagaID)
+	if run, ok := s.runs.get(sagaID); ok && run.Status == RunStatusRunning {
+		return 0, ErrRunActive
+	}
+
+	timer := logger.StartTimer()
+	reviews, err := s.repo.RollbackRun(ctx, sagaID, purge)
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "service.run.rolled_back", "failed", timer(), "purge", purge)
+		return 0, err
+	}
+	logger.LogEventWithLatency(ctx, "service.run.rolled_back", "success", timer(), "purge", purge, "reviews", reviews)
+	s.queueShadowWrite(ctx, shadowWrite{op: shadowOpRollback, runID: sagaID, purge: purge, changed: reviews})
+	return reviews, nil
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+
+	"github.com/quiby-ai/review-ingestor/internal/appstore"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+// retryFailedSaves runs the reviews the persist stage set aside after a
+// transient save failure through the pipeline once more, when the country is
+// fetched. The pool has replaced broken connections by then; reviews that
+// fail again are quarantined and counted as failed under the save error
+// policy, whose error is returned.
+func (s *IngestService) retryFailedSaves(ctx context.Context, handler BatchHandler, newBatch func([]appstore.Review) *ReviewBatch, failed []BatchItem) error {
+	if len(failed) == 0 {
+		return nil
+	}
+
+	reviews := make([]appstore.Review, len(failed))
+	for i, item := range failed {
+		reviews[i] = item.Review
+	}
+	batch := newBatch(reviews)
+	failedBefore := batch.stats.Failed
+
+	timer := logger.StartTimer()
+	err := handler(ctx, batch)
+	stillFailed := batch.stats.Failed - failedBefore
+	outcome := "success"
+	if err != nil || stillFailed > 0 {
+		outcome = "failed"
+	}
+	logger.LogEventWithLatency(ctx, "service.saves.retried", outcome, timer(), "country", batch.Country,
+		"retried", len(failed), "saved", len(failed)-stillFailed, "failed", stillFailed)
+	return err
+}
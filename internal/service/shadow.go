@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// Outcomes of a shadow write, compared with the primary write it mirrors.
+const (
+	shadowMatch    = "match"
+	shadowMismatch = "mismatch"
+	shadowFailed   = "failed"
+	shadowDropped  = "dropped"
+)
+
+// Writes mirrored to the shadow store.
+const (
+	shadowOpInsert    = "insert"
+	shadowOpReplace   = "replace"
+	shadowOpEdit      = "edit"
+	shadowOpRefresh   = "refresh"
+	shadowOpRollback  = "rollback"
+	shadowOpDeletions = "deletions"
+)
+
+// ShadowStore is a secondary review store that receives a copy of every
+// review write, e.g. the target of a storage migration.
+type ShadowStore interface {
+	SaveRawReview(ctx context.Context, review storage.RawReview) (bool, error)
+	ReplaceRawReview(ctx context.Context, review storage.RawReview) (bool, error)
+	UpdateEditedReview(ctx context.Context, review storage.RawReview) (bool, error)
+	RefreshReview(ctx context.Context, review storage.RawReview) (bool, error)
+	RollbackRun(ctx context.Context, runID string, purge bool) (int64, error)
+	ReconcileDeletions(ctx context.Context, window storage.DeletionWindow, mark bool) (int64, error)
+	ReviewFingerprint(ctx context.Context, key storage.ReviewKey) (string, bool, error)
+}
+
+// shadowWrite is a primary write waiting to be mirrored. row is set for
+// writes of one review, runID and purge for rollbacks and window for marked
+// deletions.
+type shadowWrite struct {
+	op     string
+	row    storage.RawReview
+	runID  string
+	purge  bool
+	window storage.DeletionWindow
+	// changed is the primary's outcome: 1 when it stored the review as new
+	// or updated it and 0 otherwise, or the number of reviews a rollback or
+	// deletion reconciliation changed.
+	changed int64
+}
+
+// WithShadowStore mirrors review writes to store. Up to cfg.QueueSize writes
+// wait for RunShadowWrites; the shadow store never delays or fails a saga.
+func WithShadowStore(store ShadowStore, cfg config.ShadowConfig) Option {
+	return func(s *IngestService) {
+		s.shadow = store
+		s.shadowTimeout = cfg.Timeout
+		s.shadowQueue = make(chan shadowWrite, cfg.QueueSize)
+	}
+}
+
+// mirrorReview queues a write of row the primary applied with op, reporting
+// whether it stored the review as new or changed it.
+func (s *IngestService) mirrorReview(ctx context.Context, op string, row storage.RawReview, changed bool) {
+	write := shadowWrite{op: op, row: row}
+	if changed {
+		write.changed = 1
+	}
+	s.queueShadowWrite(logger.WithReviewID(ctx, row.ID), write)
+}
+
+// queueShadowWrite queues a write for the shadow store, dropping it when the
+// queue is full.
+func (s *IngestService) queueShadowWrite(ctx context.Context, write shadowWrite) {
+	if s.shadow == nil {
+		return
+	}
+	select {
+	case s.shadowQueue <- write:
+	default:
+		shadowWrites.Inc(shadowDropped)
+		logger.LogEvent(ctx, "service.shadow.written", "skipped", "op", write.op, "reason", "queue_full")
+	}
+}
+
+// RunShadowWrites applies queued writes to the shadow store until ctx is
+// cancelled.
+func (s *IngestService) RunShadowWrites(ctx context.Context) {
+	if s.shadow == nil {
+		return
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case write := <-s.shadowQueue:
+			s.writeShadow(ctx, write)
+		}
+	}
+}
+
+// writeShadow applies one write and compares its outcome with the primary's.
+// A review the write stored or changed must also hold the same content in
+// the shadow afterwards, checked through its fingerprint. Failures are
+// logged and counted only.
+func (s *IngestService) writeShadow(ctx context.Context, write shadowWrite) {
+	if write.row.ID != "" {
+		ctx = logger.WithAppID(logger.WithReviewID(ctx, write.row.ID), write.row.AppID)
+	}
+	writeCtx, cancel := context.WithTimeout(ctx, s.shadowTimeout)
+	defer cancel()
+
+	timer := logger.StartTimer()
+	changed, err := s.applyShadowWrite(writeCtx, write)
+	if err != nil {
+		shadowWrites.Inc(shadowFailed)
+		logger.LogEventWithLatency(ctx, "service.shadow.written", "failed", timer(), "op", write.op, "error", err.Error())
+		return
+	}
+	if changed != write.changed {
+		shadowWrites.Inc(shadowMismatch)
+		logger.LogEventWithLatency(ctx, "service.shadow.written", "mismatch", timer(), "op", write.op,
+			"primary_changed", write.changed, "shadow_changed", changed)
+		return
+	}
+
+	if write.row.ID != "" && write.changed > 0 && write.row.Fingerprint != "" {
+		fingerprint, found, err := s.shadow.ReviewFingerprint(writeCtx, write.row.Key())
+		if err != nil {
+			shadowWrites.Inc(shadowFailed)
+			logger.LogEventWithLatency(ctx, "service.shadow.written", "failed", timer(), "op", write.op, "error", err.Error())
+			return
+		}
+		if !found || fingerprint != write.row.Fingerprint {
+			shadowWrites.Inc(shadowMismatch)
+			logger.LogEventWithLatency(ctx, "service.shadow.written", "mismatch", timer(), "op", write.op,
+				"reason", "content", "shadow_found", found)
+			return
+		}
+	}
+	shadowWrites.Inc(shadowMatch)
+}
+
+// applyShadowWrite repeats write on the shadow store and returns its outcome
+// in the form of shadowWrite.changed.
+func (s *IngestService) applyShadowWrite(ctx context.Context, write shadowWrite) (int64, error) {
+	var apply func(context.Context, storage.RawReview) (bool, error)
+	switch write.op {
+	case shadowOpRollback:
+		return s.shadow.RollbackRun(ctx, write.runID, write.purge)
+	case shadowOpDeletions:
+		return s.shadow.ReconcileDeletions(ctx, write.window, true)
+	case shadowOpReplace:
+		apply = s.shadow.ReplaceRawReview
+	case shadowOpEdit:
+		apply = s.shadow.UpdateEditedReview
+	case shadowOpRefresh:
+		apply = s.shadow.RefreshReview
+	default:
+		apply = s.shadow.SaveRawReview
+	}
+	changed, err := apply(ctx, write.row)
+	if err != nil || !changed {
+		return 0, err
+	}
+	return 1, nil
+}
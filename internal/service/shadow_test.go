@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// recordingShadow records which write reached it and reports every review
+// write as a change.
+type recordingShadow struct {
+	calls []string
+}
+
+func (r *recordingShadow) record(call string) (bool, error) {
+	r.calls = append(r.calls, call)
+	return true, nil
+}
+
+func (r *recordingShadow) SaveRawReview(context.Context, storage.RawReview) (bool, error) {
+	return r.record("save")
+}
+
+func (r *recordingShadow) ReplaceRawReview(context.Context, storage.RawReview) (bool, error) {
+	return r.record("replace")
+}
+
+func (r *recordingShadow) UpdateEditedReview(context.Context, storage.RawReview) (bool, error) {
+	return r.record("edit")
+}
+
+func (r *recordingShadow) RefreshReview(context.Context, storage.RawReview) (bool, error) {
+	return r.record("refresh")
+}
+
+func (r *recordingShadow) RollbackRun(context.Context, string, bool) (int64, error) {
+	r.calls = append(r.calls, "rollback")
+	return 3, nil
+}
+
+func (r *recordingShadow) ReconcileDeletions(context.Context, storage.DeletionWindow, bool) (int64, error) {
+	r.calls = append(r.calls, "deletions")
+	return 2, nil
+}
+
+func (r *recordingShadow) ReviewFingerprint(context.Context, storage.ReviewKey) (string, bool, error) {
+	return "", false, nil
+}
+
+func TestApplyShadowWrite(t *testing.T) {
+	tests := []struct {
+		op   string
+		call string
+		want int64
+	}{
+		{op: shadowOpInsert, call: "save", want: 1},
+		{op: shadowOpReplace, call: "replace", want: 1},
+		{op: shadowOpEdit, call: "edit", want: 1},
+		{op: shadowOpRefresh, call: "refresh", want: 1},
+		{op: shadowOpRollback, call: "rollback", want: 3},
+		{op: shadowOpDeletions, call: "deletions", want: 2},
+	}
+
+	for _, tt := range tests {
+		shadow := &recordingShadow{}
+		s := &IngestService{shadow: shadow}
+		got, err := s.applyShadowWrite(context.Background(), shadowWrite{op: tt.op, row: storage.RawReview{ID: "r1"}})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.op, err)
+		}
+		if got != tt.want || len(shadow.calls) != 1 || shadow.calls[0] != tt.call {
+			t.Errorf("%s: changed %d through %v, want %d through %s", tt.op, got, shadow.calls, tt.want, tt.call)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/producer"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// checkResponseSLA observes how long the developer took to respond to each
+// answered review in rows and publishes a ResponseSLABreached for those
+// answered later than response_sla.threshold. Publish failures are logged
+// and never fail the saga.
+func (s *IngestService) checkResponseSLA(ctx context.Context, sagaID string, req Request, country string, rows []storage.RawReview) {
+	if !s.slaCfg.Enabled() {
+		return
+	}
+	for _, row := range rows {
+		if row.ResponseDate == nil {
+			continue
+		}
+		responseTime := row.ResponseDate.Sub(row.ReviewedAt)
+		responseSeconds.Observe(responseTime.Seconds(), req.Store)
+		if responseTime <= s.slaCfg.Threshold {
+			continue
+		}
+		slaBreaches.Inc(req.Store)
+
+		reviewCtx := logger.WithReviewID(ctx, row.ID)
+		event := producer.ResponseSLABreached{
+			AppID:            req.AppID,
+			Store:            req.Store,
+			Country:          country,
+			ResponseSeconds:  responseTime.Seconds(),
+			ThresholdSeconds: s.slaCfg.Threshold.Seconds(),
+			Review:           toIngestedReview(row),
+		}
+		if err := s.producer.PublishEvent(reviewCtx, []byte(req.AppID), s.producer.BuildSLAEnvelope(event, sagaID)); err != nil {
+			logger.LogEvent(reviewCtx, "service.response_sla.breached", "failed", "country", country, "response_seconds", event.ResponseSeconds, "error", err.Error())
+			continue
+		}
+		logger.LogEvent(reviewCtx, "service.response_sla.breached", "success", "country", country, "response_seconds", event.ResponseSeconds)
+	}
+}
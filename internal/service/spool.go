@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/quiby-ai/common/pkg/events"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// EventSpool keeps events that could not be published somewhere that does
+// not depend on Kafka.
+type EventSpool interface {
+	Save(event storage.SpooledEvent) error
+	List() ([]storage.SpooledEvent, error)
+	Remove(name string) error
+}
+
+// WithEventSpool enables spooling completion and progress events Kafka did
+// not accept, so that a saga whose reviews are stored still completes.
+func WithEventSpool(spool EventSpool) Option {
+	return func(s *IngestService) {
+		s.spool = spool
+	}
+}
+
+// publishOrSpool publishes envelope and, when that fails and a spool is set,
+// keeps it for RunEventSpool to retry. It returns an error only when the
+// envelope was neither published nor spooled.
+func (s *IngestService) publishOrSpool(ctx context.Context, key []byte, envelope events.Envelope[any]) error {
+	err := s.producer.PublishEvent(ctx, key, envelope)
+	if err == nil || s.spool == nil {
+		return err
+	}
+
+	if spoolErr := s.spool.Save(storage.SpooledEvent{Key: key, Envelope: envelope, SpooledAt: time.Now().UTC()}); spoolErr != nil {
+		logger.Error(ctx, "Failed to spool unpublished event", spoolErr, "message_id", envelope.MessageID)
+		return err
+	}
+	logger.LogEvent(ctx, "service.event.spooled", "success", "message_id", envelope.MessageID, "type", envelope.Type, "error", err.Error())
+	return nil
+}
+
+// RunEventSpool retries the spooled events every
+// ingest.publish_spool_interval, oldest first, until ctx is cancelled.
+func (s *IngestService) RunEventSpool(ctx context.Context) {
+	if s.spool == nil || s.ingestCfg.PublishSpoolInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.ingestCfg.PublishSpoolInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.flushSpool(ctx)
+		}
+	}
+}
+
+// flushSpool publishes spooled events in order and stops at the first one
+// Kafka still rejects, so that they are not delivered out of order.
+func (s *IngestService) flushSpool(ctx context.Context) {
+	spooled, err := s.spool.List()
+	if err != nil {
+		logger.Error(ctx, "Failed to read spooled events", err)
+	}
+	if len(spooled) == 0 {
+		return
+	}
+
+	timer := logger.StartTimer()
+	published := 0
+	for _, event := range spooled {
+		eventCtx := logger.WithSagaID(ctx, event.Envelope.SagaID)
+		if err := s.producer.PublishEvent(eventCtx, event.Key, event.Envelope); err != nil {
+			logger.LogEventWithLatency(ctx, "service.event.spool_flushed", "failed", timer(),
+				"published", published, "remaining", len(spooled)-published, "error", err.Error())
+			return
+		}
+		if err := s.spool.Remove(event.Name); err != nil {
+			logger.Error(eventCtx, "Failed to remove published event from spool", err)
+		}
+		published++
+	}
+	logger.LogEventWithLatency(ctx, "service.event.spool_flushed", "success", timer(), "published", published)
+}
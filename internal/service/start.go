@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/quiby-ai/review-ingestor/internal/appstore"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// applyStartPosition sets where fetching a storefront starts: the request's
+// start_offset and start_before, or the start override an operator set for
+// the storefront, which wins. It returns the override applied, to be
+// completed once the storefront is fetched.
+func (s *IngestService) applyStartPosition(ctx context.Context, req Request, country string, opts *appstore.FetchOptions) (*storage.StartOverride, error) {
+	opts.Offset = req.StartOffset
+	if req.StartBefore != "" {
+		before, err := ParseRequestDate(req.StartBefore)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start_before: %w", err)
+		}
+		opts.Before = &before
+	}
+
+	override, err := s.control.GetStartOverride(ctx, req.Store, req.AppID, country)
+	if err != nil {
+		// The request's own start position still applies.
+		logger.Warn(ctx, "Failed to read start override", "country", country, "error", err.Error())
+		return nil, nil
+	}
+	if override == nil {
+		return nil, nil
+	}
+	opts.Offset, opts.Before = override.Offset, override.Before
+	logger.LogEvent(ctx, "service.start_override.applied", "success", "country", country, "offset", override.Offset, "before", override.Before)
+	return override, nil
+}
+
+// completeStartOverride removes the override a storefront fetch started from
+// once the storefront finished successfully. A failed or cancelled fetch
+// keeps it, so the retry starts from the same position.
+func (s *IngestService) completeStartOverride(ctx context.Context, override *storage.StartOverride) {
+	if override == nil {
+		return
+	}
+	if err := s.control.CompleteStartOverride(ctx, *override); err != nil {
+		logger.Warn(ctx, "Failed to complete start override", "country", override.Country, "error", err.Error())
+	}
+}
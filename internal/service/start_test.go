@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/appstore"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// overrideControl holds one start override and records the ones completed.
+type overrideControl struct {
+	ControlStore
+	override  *storage.StartOverride
+	completed []storage.StartOverride
+}
+
+func (c *overrideControl) GetStartOverride(context.Context, string, string, string) (*storage.StartOverride, error) {
+	return c.override, nil
+}
+
+func (c *overrideControl) CompleteStartOverride(_ context.Context, override storage.StartOverride) error {
+	c.completed = append(c.completed, override)
+	return nil
+}
+
+func TestStartOverrideKeptUntilCompleted(t *testing.T) {
+	override := &storage.StartOverride{Store: StoreAppStore, AppID: "123", Country: "us", Offset: 200, CreatedAt: time.Now()}
+	control := &overrideControl{override: override}
+	s := &IngestService{control: control}
+
+	opts := &appstore.FetchOptions{}
+	applied, err := s.applyStartPosition(context.Background(), Request{Store: StoreAppStore, StartOffset: 20}, "us", opts)
+	if err != nil {
+		t.Fatalf("applyStartPosition: %v", err)
+	}
+	if opts.Offset != 200 || applied != override {
+		t.Fatalf("offset = %d, applied = %v; want the override's 200", opts.Offset, applied)
+	}
+	if len(control.completed) != 0 {
+		t.Fatalf("override completed before the fetch: %v", control.completed)
+	}
+
+	s.completeStartOverride(context.Background(), applied)
+	if len(control.completed) != 1 || control.completed[0] != *override {
+		t.Errorf("completed = %v, want the applied override", control.completed)
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// translationQueueSize bounds how many stored reviews wait for translation.
+// Translation is rate limited far below fetch speed, so reviews arriving
+// while the queue is full are left untranslated rather than slowing sagas.
+const translationQueueSize = 10000
+
+// Translator translates review text from an ISO 639-1 source language into
+// its Target language.
+type Translator interface {
+	Translate(ctx context.Context, text, source string) (string, error)
+	Target() string
+}
+
+// WithTranslator enables translation of newly stored reviews into the
+// translator's target language. RunTranslations does the work.
+func WithTranslator(translator Translator) Option {
+	return func(s *IngestService) {
+		s.translator = translator
+		s.translations = make(chan storage.RawReview, translationQueueSize)
+	}
+}
+
+// queueTranslations queues the rows written in another language than the
+// translator's target. Reviews of unknown language or without text are
+// skipped.
+func (s *IngestService) queueTranslations(ctx context.Context, rows []storage.RawReview) {
+	if s.translator == nil {
+		return
+	}
+	target := s.translator.Target()
+	for _, row := range rows {
+		if row.Language == "" || row.Language == target || row.ContentPlain == "" {
+			continue
+		}
+		select {
+		case s.translations <- row:
+		default:
+			logger.LogEvent(logger.WithReviewID(ctx, row.ID), "service.review.translated", "skipped", "language", row.Language, "reason", "queue_full")
+		}
+	}
+}
+
+// RunTranslations translates queued reviews until ctx is cancelled. Failures
+// are logged and leave the review untranslated; they never fail a saga.
+func (s *IngestService) RunTranslations(ctx context.Context) {
+	if s.translator == nil {
+		return
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case row := <-s.translations:
+			s.translate(ctx, row)
+		}
+	}
+}
+
+func (s *IngestService) translate(ctx context.Context, row storage.RawReview) {
+	ctx = logger.WithAppID(logger.WithReviewID(ctx, row.ID), row.AppID)
+	timer := logger.StartTimer()
+	translated, err := s.translator.Translate(ctx, row.ContentPlain, row.Language)
+	if err == nil {
+		err = s.repo.SaveTranslation(ctx, row.Key(), translated)
+	}
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "service.review.translated", "failed", timer(), "language", row.Language, "error", err.Error())
+		return
+	}
+	logger.LogEventWithLatency(ctx, "service.review.translated", "success", timer(), "language", row.Language)
+}
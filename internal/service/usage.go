@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/budget"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// UsageStore keeps the daily usage rollups of each tenant.
+type UsageStore interface {
+	AddUsage(ctx context.Context, usage storage.UsageRecord) error
+}
+
+// WithUsageStore enables per-tenant usage accounting.
+func WithUsageStore(usage UsageStore) Option {
+	return func(s *IngestService) {
+		s.usage = usage
+	}
+}
+
+// recordUsage adds what fetching a storefront cost to the saga tenant's
+// rollup of the day, even when the fetch failed or was cancelled. Failures
+// are logged and never fail the saga.
+func (s *IngestService) recordUsage(ctx context.Context, req Request, settings sagaSettings, usage budget.Usage) {
+	if s.usage == nil || usage.Requests == 0 {
+		return
+	}
+
+	timer := logger.StartTimer()
+	err := s.usage.AddUsage(context.WithoutCancel(ctx), storage.UsageRecord{
+		Tenant:         settings.tenant,
+		Store:          req.Store,
+		Day:            time.Now(),
+		Requests:       usage.Requests,
+		RateLimited:    usage.RateLimited,
+		Bytes:          usage.Bytes,
+		BackoffSeconds: usage.BackoffSeconds,
+	})
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "service.usage.recorded", "failed", timer(), "tenant", settings.tenant, "error", err.Error())
+		return
+	}
+	logger.LogEventWithLatency(ctx, "service.usage.recorded", "success", timer(), "tenant", settings.tenant, "requests", usage.Requests, "bytes", usage.Bytes)
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// AppVersionStore keeps app releases and tags reviews with the release that
+// was current when they were written.
+type AppVersionStore interface {
+	RecordVersion(ctx context.Context, version storage.AppVersion) (bool, error)
+	TagReviews(ctx context.Context, store, appID string) (int64, error)
+}
+
+// WithAppVersionStore enables release tracking for App Store sagas. It needs
+// the app lookup set with WithAppLookup.
+func WithAppVersionStore(versions AppVersionStore) Option {
+	return func(s *IngestService) {
+		s.versions = versions
+	}
+}
+
+// trackAppVersion records the app's current release as reported by the
+// lookup API in the default storefront and tags the stored reviews with
+// their likely version. Failures are logged and never fail the saga.
+func (s *IngestService) trackAppVersion(ctx context.Context, req Request) {
+	if s.versions == nil || s.lookup == nil || req.Store != StoreAppStore {
+		return
+	}
+
+	timer := logger.StartTimer()
+	info, err := s.lookup.Lookup(ctx, s.appStoreCfg.DefaultStorefront, req.AppID)
+	if err == nil && info != nil {
+		s.recordIdentifiers(ctx, storage.AppIdentifiers{AppleID: req.AppID, BundleID: info.BundleID})
+	}
+	if err != nil || info == nil || info.Version == "" {
+		logger.LogEventWithLatency(ctx, "service.app_version.tracked", "skipped", timer(), "reason", "lookup_unavailable")
+		return
+	}
+	releasedAt, err := time.Parse(time.RFC3339, info.CurrentVersionReleaseDate)
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "service.app_version.tracked", "skipped", timer(), "reason", "invalid_release_date", "version", info.Version)
+		return
+	}
+
+	isNew, err := s.versions.RecordVersion(ctx, storage.AppVersion{
+		Store:        req.Store,
+		AppID:        req.AppID,
+		Version:      info.Version,
+		ReleasedAt:   releasedAt,
+		ReleaseNotes: info.ReleaseNotes,
+	})
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "service.app_version.tracked", "failed", timer(), "version", info.Version, "error", err.Error())
+		return
+	}
+	tagged, err := s.versions.TagReviews(ctx, req.Store, req.AppID)
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "service.app_version.tracked", "failed", timer(), "version", info.Version, "error", err.Error())
+		return
+	}
+	logger.LogEventWithLatency(ctx, "service.app_version.tracked", "success", timer(), "version", info.Version, "new_version", isNew, "tagged_reviews", tagged)
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/producer"
+	"github.com/quiby-ai/review-ingestor/internal/storage"
+)
+
+// Watchlist returns the patterns matching any of texts for an app.
+type Watchlist interface {
+	Match(appID string, texts ...string) []string
+}
+
+// WithWatchlist enables watchlist alerts for newly stored reviews.
+func WithWatchlist(watchlist Watchlist) Option {
+	return func(s *IngestService) {
+		s.watchlist = watchlist
+	}
+}
+
+// checkWatchlist publishes a WatchlistHit for each new review matching the
+// watchlist. Publish failures are logged and never fail the saga.
+func (s *IngestService) checkWatchlist(ctx context.Context, sagaID string, req Request, country string, rows []storage.RawReview) {
+	if s.watchlist == nil {
+		return
+	}
+	for _, row := range rows {
+		matches := s.watchlist.Match(req.AppID, row.Title, row.Content)
+		if len(matches) == 0 {
+			continue
+		}
+
+		reviewCtx := logger.WithReviewID(ctx, row.ID)
+		event := producer.WatchlistHit{
+			AppID:   req.AppID,
+			Store:   req.Store,
+			Country: country,
+			Matches: matches,
+			Review:  toIngestedReview(row),
+		}
+		if err := s.producer.PublishEvent(reviewCtx, []byte(req.AppID), s.producer.BuildWatchlistEnvelope(event, sagaID)); err != nil {
+			logger.LogEvent(reviewCtx, "service.watchlist.hit", "failed", "country", country, "matches", matches, "error", err.Error())
+			continue
+		}
+		logger.LogEvent(reviewCtx, "service.watchlist.hit", "success", "country", country, "matches", matches)
+	}
+}
@@ -0,0 +1,164 @@
+package steam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/appstore"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/pager"
+
+	"github.com/quiby-ai/common/pkg/httpx"
+)
+
+const (
+	dateLayout  = "2006-01-02T15:04:05Z"
+	firstCursor = "*"
+	// Steam reviews are a thumbs up or down; they are stored as 5 and 1 stars.
+	ratingRecommended    = 5
+	ratingNotRecommended = 1
+)
+
+type Review struct {
+	ID                string `json:"recommendationid"`
+	Language          string `json:"language"`
+	Review            string `json:"review"`
+	TimestampCreated  int64  `json:"timestamp_created"`
+	TimestampUpdated  int64  `json:"timestamp_updated"`
+	VotedUp           bool   `json:"voted_up"`
+	DeveloperResponse string `json:"developer_response,omitempty"`
+	DeveloperRespTime int64  `json:"timestamp_dev_responded,omitempty"`
+}
+
+type ReviewsResponse struct {
+	Success int      `json:"success"`
+	Reviews []Review `json:"reviews"`
+	Cursor  string   `json:"cursor"`
+}
+
+// ReviewFetcher pages through Steam user reviews with the appreviews API's
+// cursor. Steam reviews are not split by storefront, so Steam requests name
+// a single country, which the reviews are stored under.
+type ReviewFetcher struct {
+	http httpx.Client
+	cfg  config.SteamConfig
+}
+
+func NewReviewFetcher(http httpx.Client, cfg config.Config) *ReviewFetcher {
+	return &ReviewFetcher{http: http, cfg: cfg.Steam}
+}
+
+func (r *ReviewFetcher) FetchReviews(ctx context.Context, country, appID, cursor string) (*ReviewsResponse, error) {
+	timer := logger.StartTimer()
+	requestURL := r.prepareQuery(appID, cursor)
+	headers := map[string]string{
+		"accept": "application/json",
+	}
+
+	logger.Debug(ctx, "Fetching reviews from Steam", "country", country, "cursor", cursor)
+
+	response, err := r.http.DoGET(ctx, requestURL, nil, headers)
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "steam.reviews.request", "failed", timer(), "country", country, "error", "http_request_failed")
+		return nil, fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+
+	if response.Status != 200 {
+		logger.LogEventWithLatency(ctx, "steam.reviews.request", "failed", timer(), "country", country, "status", response.Status)
+		return nil, fmt.Errorf("unexpected status code: %d", response.Status)
+	}
+
+	var reviewsResp ReviewsResponse
+	if err := json.Unmarshal(response.Body, &reviewsResp); err != nil {
+		logger.LogEventWithLatency(ctx, "steam.reviews.request", "failed", timer(), "country", country, "error", "json_parse_failed")
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	if reviewsResp.Success != 1 {
+		logger.LogEventWithLatency(ctx, "steam.reviews.request", "failed", timer(), "country", country, "error", "unsuccessful_response")
+		return nil, fmt.Errorf("app %s not found or reviews unavailable", appID)
+	}
+
+	logger.LogEventWithLatency(ctx, "steam.reviews.request", "success", timer(), "country", country, "reviews_count", len(reviewsResp.Reviews))
+	return &reviewsResp, nil
+}
+
+// StreamReviews pages through reviews newest first and hands them to flush in
+// batches of at least window reviews, converted to the App Store review
+// shape. opts.Offset is ignored since Steam pages by opaque cursor.
+func (r *ReviewFetcher) StreamReviews(ctx context.Context, country string, appID string, opts *appstore.FetchOptions, window int, flush func(context.Context, []appstore.Review) error) (int, error) {
+	fetch := func(ctx context.Context, cursor string) ([]appstore.Review, string, error) {
+		reviewsResp, err := r.FetchReviews(ctx, country, appID, cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		reviews := make([]appstore.Review, 0, len(reviewsResp.Reviews))
+		for _, review := range reviewsResp.Reviews {
+			reviews = append(reviews, toAppStoreReview(review))
+		}
+		next := reviewsResp.Cursor
+		if len(reviewsResp.Reviews) == 0 {
+			next = ""
+		}
+		return reviews, next, nil
+	}
+
+	return pager.Stream(ctx, firstCursor, opts, window, fetch, flush)
+}
+
+func toAppStoreReview(review Review) appstore.Review {
+	rating := ratingNotRecommended
+	if review.VotedUp {
+		rating = ratingRecommended
+	}
+
+	converted := appstore.Review{
+		ID: review.ID,
+		Attributes: appstore.ReviewAttributes{
+			Date:     time.Unix(review.TimestampCreated, 0).UTC().Format(dateLayout),
+			Rating:   rating,
+			Review:   review.Review,
+			Language: steamLanguages[review.Language],
+		},
+	}
+	if review.TimestampUpdated > review.TimestampCreated {
+		converted.Attributes.IsEdited = true
+		converted.Attributes.Modified = time.Unix(review.TimestampUpdated, 0).UTC().Format(dateLayout)
+	}
+	if review.DeveloperResponse != "" {
+		converted.Attributes.DeveloperResponse = &appstore.DeveloperResponse{
+			Body:     review.DeveloperResponse,
+			Modified: time.Unix(review.DeveloperRespTime, 0).UTC().Format(dateLayout),
+		}
+	}
+	return converted
+}
+
+func (r *ReviewFetcher) prepareQuery(appID, cursor string) string {
+	host := strings.TrimSuffix(r.cfg.APIHost, "/")
+
+	params := url.Values{}
+	params.Set("json", "1")
+	params.Set("cursor", cursor)
+	params.Set("num_per_page", strconv.Itoa(r.cfg.Limit))
+	params.Set("filter", "recent")
+	params.Set("language", "all")
+	params.Set("purchase_type", "all")
+
+	return fmt.Sprintf("%s/appreviews/%s?%s", host, url.PathEscape(appID), params.Encode())
+}
+
+// steamLanguages maps the Steam API language names to ISO 639-1 codes.
+var steamLanguages = map[string]string{
+	"english": "en", "german": "de", "french": "fr", "italian": "it", "spanish": "es", "latam": "es",
+	"brazilian": "pt", "portuguese": "pt", "russian": "ru", "ukrainian": "uk", "polish": "pl", "czech": "cs",
+	"turkish": "tr", "swedish": "sv", "danish": "da", "norwegian": "no", "finnish": "fi", "dutch": "nl",
+	"hungarian": "hu", "romanian": "ro", "greek": "el", "bulgarian": "bg", "japanese": "ja", "koreana": "ko",
+	"schinese": "zh", "tchinese": "zh", "thai": "th", "vietnamese": "vi", "indonesian": "id", "arabic": "ar",
+}
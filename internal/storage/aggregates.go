@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// AggregateRepository keeps the per-country rating snapshot of each saga.
+type AggregateRepository struct {
+	db *sql.DB
+}
+
+func NewAggregateRepository(db *sql.DB) *AggregateRepository {
+	return &AggregateRepository{db: db}
+}
+
+// IngestAggregate is the rating snapshot of one country of a saga.
+type IngestAggregate struct {
+	SagaID     string
+	Store      string
+	AppID      string
+	Country    string
+	Reviews    int
+	Average    float64
+	Promoters  int
+	Passives   int
+	Detractors int
+	NPS        float64
+}
+
+// SaveAggregates stores the snapshots of a saga, replacing those saved by an
+// earlier attempt of the same saga.
+func (r *AggregateRepository) SaveAggregates(ctx context.Context, aggregates []IngestAggregate) error {
+	const query = `
+		INSERT INTO ingest_aggregates (saga_id, store, app_id, country, reviews, average, promoters, passives, detractors, nps, computed_at)
+		SELECT *, NOW() FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::int[], $6::float8[], $7::int[], $8::int[], $9::int[], $10::float8[])
+		ON CONFLICT (saga_id, store, country) DO UPDATE SET
+			reviews = EXCLUDED.reviews,
+			average = EXCLUDED.average,
+			promoters = EXCLUDED.promoters,
+			passives = EXCLUDED.passives,
+			detractors = EXCLUDED.detractors,
+			nps = EXCLUDED.nps,
+			computed_at = EXCLUDED.computed_at;`
+
+	if len(aggregates) == 0 {
+		return nil
+	}
+	n := len(aggregates)
+	sagaIDs, stores, appIDs, countries := make([]string, n), make([]string, n), make([]string, n), make([]string, n)
+	reviews, promoters, passives, detractors := make([]int64, n), make([]int64, n), make([]int64, n), make([]int64, n)
+	averages, nps := make([]float64, n), make([]float64, n)
+	for i, a := range aggregates {
+		sagaIDs[i], stores[i], appIDs[i], countries[i] = a.SagaID, a.Store, a.AppID, a.Country
+		reviews[i], promoters[i], passives[i], detractors[i] = int64(a.Reviews), int64(a.Promoters), int64(a.Passives), int64(a.Detractors)
+		averages[i], nps[i] = a.Average, a.NPS
+	}
+
+	_, err := r.db.ExecContext(ctx, query, pq.Array(sagaIDs), pq.Array(stores), pq.Array(appIDs), pq.Array(countries),
+		pq.Array(reviews), pq.Array(averages), pq.Array(promoters), pq.Array(passives), pq.Array(detractors), pq.Array(nps))
+	if err != nil {
+		return fmt.Errorf("failed to save ingest aggregates: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// AppVersionRepository keeps the releases of apps in app_versions and tags
+// stored reviews with the version that was current when they were written.
+type AppVersionRepository struct {
+	db *sql.DB
+}
+
+func NewAppVersionRepository(db *sql.DB) *AppVersionRepository {
+	return &AppVersionRepository{db: db}
+}
+
+// AppVersion is a release of an app.
+type AppVersion struct {
+	Store        string
+	AppID        string
+	Version      string
+	ReleasedAt   time.Time
+	ReleaseNotes string
+}
+
+// RecordVersion stores a release and reports whether it was new. Releases
+// already stored are left untouched.
+func (r *AppVersionRepository) RecordVersion(ctx context.Context, version AppVersion) (bool, error) {
+	const query = `
+		INSERT INTO app_versions (store, app_id, version, released_at, release_notes, first_seen_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NOW())
+		ON CONFLICT (store, app_id, version) DO NOTHING;`
+
+	result, err := r.db.ExecContext(ctx, query, version.Store, version.AppID, version.Version, version.ReleasedAt.UTC(), version.ReleaseNotes)
+	if err != nil {
+		return false, fmt.Errorf("failed to record app version: %w", err)
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to record app version: %w", err)
+	}
+	return inserted > 0, nil
+}
+
+// TagReviews sets likely_version on the app's reviews to the latest release
+// published before each was written. Only reviews not tagged yet and those
+// written since the latest release are revisited, as earlier tags cannot
+// change; reviews older than the first known release stay untagged. It
+// returns the number of reviews tagged.
+func (r *AppVersionRepository) TagReviews(ctx context.Context, store, appID string) (int64, error) {
+	const query = `
+		WITH latest AS (
+			SELECT MIN(released_at) AS first_released_at, MAX(released_at) AS released_at
+			FROM app_versions WHERE store = $1 AND app_id = $2
+		)
+		UPDATE raw_reviews r SET likely_version = (
+			SELECT v.version FROM app_versions v
+			WHERE v.store = r.store AND v.app_id = r.app_id AND v.released_at <= r.reviewed_at
+			ORDER BY v.released_at DESC
+			LIMIT 1
+		)
+		FROM latest
+		WHERE r.store = $1 AND r.app_id = $2
+		  AND r.reviewed_at >= latest.first_released_at
+		  AND (r.likely_version IS NULL OR r.reviewed_at >= latest.released_at);`
+
+	result, err := r.db.ExecContext(ctx, query, store, appID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to tag review versions: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+type ResponseCache struct {
+	db  *sql.DB
+	ttl time.Duration
+}
+
+func NewResponseCache(db *sql.DB, ttl time.Duration) *ResponseCache {
+	return &ResponseCache{db: db, ttl: ttl}
+}
+
+// Get returns the cached body for url if it was stored within the TTL.
+func (c *ResponseCache) Get(ctx context.Context, url string) ([]byte, bool, error) {
+	const query = `
+		SELECT body FROM http_response_cache
+		WHERE url = $1 AND fetched_at > $2;`
+
+	var body []byte
+	err := c.db.QueryRowContext(ctx, query, url, time.Now().Add(-c.ttl)).Scan(&body)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Debug(ctx, "Response cache miss", "url", url)
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	logger.Debug(ctx, "Response cache hit", "url", url)
+	return body, true, nil
+}
+
+func (c *ResponseCache) Set(ctx context.Context, url string, body []byte) error {
+	const query = `
+		INSERT INTO http_response_cache (url, body, fetched_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (url) DO UPDATE SET body = EXCLUDED.body, fetched_at = EXCLUDED.fetched_at;`
+
+	_, err := c.db.ExecContext(ctx, query, url, body, time.Now())
+	return err
+}
+
+// PurgeExpired removes entries older than the TTL.
+func (c *ResponseCache) PurgeExpired(ctx context.Context) (int64, error) {
+	const query = `DELETE FROM http_response_cache WHERE fetched_at <= $1;`
+
+	result, err := c.db.ExecContext(ctx, query, time.Now().Add(-c.ttl))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+// GlobalScope pauses ingestion for every app.
+const GlobalScope = "*"
+
+// ParkedSaga is a saga that was stopped because ingestion was paused or the
+// saga was cancelled. It keeps the original request together with the
+// countries still to be processed and the encoded progress of the finished
+// ones, so it can continue where it stopped.
+type ParkedSaga struct {
+	SagaID             string
+	AppID              string
+	Request            []byte
+	RemainingCountries []string
+	Progress           []byte
+	ParkedAt           time.Time
+}
+
+type ControlRepository struct {
+	db *sql.DB
+}
+
+func NewControlRepository(db *sql.DB) *ControlRepository {
+	return &ControlRepository{db: db}
+}
+
+// Pause stops ingestion for scope, which is an app ID or GlobalScope.
+func (r *ControlRepository) Pause(ctx context.Context, scope string) error {
+	const query = `
+		INSERT INTO ingestion_pauses (scope, paused_at)
+		VALUES ($1, $2)
+		ON CONFLICT (scope) DO NOTHING;`
+
+	if _, err := r.db.ExecContext(ctx, query, scope, time.Now()); err != nil {
+		return fmt.Errorf("failed to pause %s: %w", scope, err)
+	}
+	logger.LogEvent(ctx, "control.ingestion.paused", "success", "scope", scope)
+	return nil
+}
+
+func (r *ControlRepository) Resume(ctx context.Context, scope string) error {
+	const query = `DELETE FROM ingestion_pauses WHERE scope = $1;`
+
+	if _, err := r.db.ExecContext(ctx, query, scope); err != nil {
+		return fmt.Errorf("failed to resume %s: %w", scope, err)
+	}
+	logger.LogEvent(ctx, "control.ingestion.resumed", "success", "scope", scope)
+	return nil
+}
+
+// IsPaused reports whether ingestion is paused for appID or globally.
+func (r *ControlRepository) IsPaused(ctx context.Context, appID string) (bool, error) {
+	const query = `SELECT EXISTS (SELECT 1 FROM ingestion_pauses WHERE scope IN ($1, $2));`
+
+	var paused bool
+	if err := r.db.QueryRowContext(ctx, query, appID, GlobalScope).Scan(&paused); err != nil {
+		return false, fmt.Errorf("failed to check pause state: %w", err)
+	}
+	return paused, nil
+}
+
+func (r *ControlRepository) ParkSaga(ctx context.Context, saga ParkedSaga) error {
+	const query = `
+		INSERT INTO parked_sagas (saga_id, app_id, request, remaining_countries, progress, parked_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (saga_id) DO UPDATE SET
+			remaining_countries = EXCLUDED.remaining_countries,
+			progress = EXCLUDED.progress,
+			parked_at = EXCLUDED.parked_at;`
+
+	_, err := r.db.ExecContext(ctx, query, saga.SagaID, saga.AppID, saga.Request, pq.Array(saga.RemainingCountries), saga.Progress, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to park saga %s: %w", saga.SagaID, err)
+	}
+	return nil
+}
+
+// CheckpointCancelled records where a cancelled saga stopped.
+func (r *ControlRepository) CheckpointCancelled(ctx context.Context, saga ParkedSaga) error {
+	const query = `
+		INSERT INTO cancelled_sagas (saga_id, app_id, request, remaining_countries, progress, cancelled_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (saga_id) DO UPDATE SET
+			remaining_countries = EXCLUDED.remaining_countries,
+			progress = EXCLUDED.progress,
+			cancelled_at = EXCLUDED.cancelled_at;`
+
+	_, err := r.db.ExecContext(ctx, query, saga.SagaID, saga.AppID, saga.Request, pq.Array(saga.RemainingCountries), saga.Progress, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to checkpoint cancelled saga %s: %w", saga.SagaID, err)
+	}
+	return nil
+}
+
+// TakeParkedSagas removes and returns the parked sagas for scope that are no
+// longer paused. GlobalScope takes sagas of every app without its own pause.
+func (r *ControlRepository) TakeParkedSagas(ctx context.Context, scope string) ([]ParkedSaga, error) {
+	const query = `
+		DELETE FROM parked_sagas p
+		WHERE ($1 = $2 OR p.app_id = $1)
+		  AND NOT EXISTS (SELECT 1 FROM ingestion_pauses WHERE scope IN (p.app_id, $2))
+		RETURNING saga_id, app_id, request, remaining_countries, progress, parked_at;`
+
+	rows, err := r.db.QueryContext(ctx, query, scope, GlobalScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to take parked sagas: %w", err)
+	}
+	defer rows.Close()
+
+	var sagas []ParkedSaga
+	for rows.Next() {
+		var saga ParkedSaga
+		if err := rows.Scan(&saga.SagaID, &saga.AppID, &saga.Request, pq.Array(&saga.RemainingCountries), &saga.Progress, &saga.ParkedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan parked saga: %w", err)
+		}
+		sagas = append(sagas, saga)
+	}
+	return sagas, rows.Err()
+}
@@ -0,0 +1,22 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// DatabaseWrites returns the rows inserted, updated and deleted in the
+// current database since its statistics were last reset. Postgres publishes
+// the counters with a short delay, so differences are approximate.
+func DatabaseWrites(ctx context.Context, db *sql.DB) (int64, error) {
+	const query = `
+		SELECT tup_inserted + tup_updated + tup_deleted
+		FROM pg_stat_database WHERE datname = current_database();`
+
+	var writes int64
+	if err := db.QueryRowContext(ctx, query).Scan(&writes); err != nil {
+		return 0, fmt.Errorf("failed to read database write counters: %w", err)
+	}
+	return writes, nil
+}
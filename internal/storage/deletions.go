@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+// DeletionWindow is the part of a storefront a saga fetched completely: every
+// review of the app written after From and up to To that the store still
+// lists was among Seen. From is exclusive because a fetch cut short may have
+// missed other reviews written at the same instant as the oldest one.
+type DeletionWindow struct {
+	Store   string
+	AppID   string
+	Country string
+	From    time.Time
+	To      time.Time
+	Seen    []string
+}
+
+// ReconcileDeletions counts the stored reviews of window that the store no
+// longer returned, because the user deleted them or the store took them
+// down. With mark they get deleted_at, which hides them from search, exports
+// and counts, and reviews of the window that were marked before but are
+// listed again are restored. It returns the number of disappeared reviews.
+func (r *ReviewRepository) ReconcileDeletions(ctx context.Context, window DeletionWindow, mark bool) (int64, error) {
+	const count = `
+		SELECT COUNT(*) FROM raw_reviews
+		WHERE store = $1 AND app_id = $2 AND country = $3
+		  AND reviewed_at > $4 AND reviewed_at <= $5
+		  AND id <> ALL($6::text[])
+		  AND rolled_back_at IS NULL AND deleted_at IS NULL;`
+	const markDeleted = `
+		UPDATE raw_reviews SET deleted_at = NOW()
+		WHERE store = $1 AND app_id = $2 AND country = $3
+		  AND reviewed_at > $4 AND reviewed_at <= $5
+		  AND id <> ALL($6::text[])
+		  AND rolled_back_at IS NULL AND deleted_at IS NULL;`
+	const restore = `
+		UPDATE raw_reviews SET deleted_at = NULL
+		WHERE store = $1 AND id = ANY($2::text[]) AND deleted_at IS NOT NULL;`
+
+	args := []any{window.Store, window.AppID, window.Country, window.From.UTC(), window.To.UTC(), pq.Array(window.Seen)}
+	timer := logger.StartTimer()
+	if !mark {
+		var disappeared int64
+		if err := r.db.QueryRowContext(ctx, count, args...).Scan(&disappeared); err != nil {
+			logger.LogEventWithLatency(ctx, "storage.reviews.reconciled", "failed", timer(), "country", window.Country, "mark", mark)
+			return 0, fmt.Errorf("failed to count disappeared reviews: %w", err)
+		}
+		logger.LogEventWithLatency(ctx, "storage.reviews.reconciled", "success", timer(), "country", window.Country, "mark", mark, "disappeared", disappeared)
+		return disappeared, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin reconciliation: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, markDeleted, args...)
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "storage.reviews.reconciled", "failed", timer(), "country", window.Country, "mark", mark)
+		return 0, fmt.Errorf("failed to mark disappeared reviews: %w", err)
+	}
+	restored, err := tx.ExecContext(ctx, restore, window.Store, pq.Array(window.Seen))
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "storage.reviews.reconciled", "failed", timer(), "country", window.Country, "mark", mark)
+		return 0, fmt.Errorf("failed to restore relisted reviews: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit reconciliation: %w", err)
+	}
+
+	disappeared, _ := res.RowsAffected()
+	relisted, _ := restored.RowsAffected()
+	logger.LogEventWithLatency(ctx, "storage.reviews.reconciled", "success", timer(), "country", window.Country, "mark", mark,
+		"disappeared", disappeared, "restored", relisted)
+	return disappeared, nil
+}
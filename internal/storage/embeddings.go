@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// EnableEmbeddings installs pgvector and adds the embedding column with the
+// given number of dimensions. It is only run when embeddings are enabled, so
+// databases without the extension keep working otherwise.
+func (r *ReviewRepository) EnableEmbeddings(ctx context.Context, dimensions int) error {
+	statements := []string{
+		`CREATE EXTENSION IF NOT EXISTS vector`,
+		fmt.Sprintf(`ALTER TABLE raw_reviews ADD COLUMN IF NOT EXISTS embedding vector(%d)`, dimensions),
+	}
+	for _, statement := range statements {
+		if _, err := r.db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to enable embeddings: %w", err)
+		}
+	}
+	return nil
+}
+
+// SaveEmbeddings stores vectors[i] as the embedding of review keys[i].
+func (r *ReviewRepository) SaveEmbeddings(ctx context.Context, keys []ReviewKey, vectors [][]float32) error {
+	const query = `
+		UPDATE raw_reviews SET embedding = v.embedding::vector
+		FROM unnest($1::text[], $2::text[], $3::text[]) AS v(store, id, embedding)
+		WHERE raw_reviews.store = v.store AND raw_reviews.id = v.id;`
+
+	stores := make([]string, len(keys))
+	ids := make([]string, len(keys))
+	for i, key := range keys {
+		stores[i], ids[i] = key.Store, key.ID
+	}
+	literals := make([]string, len(vectors))
+	for i, vector := range vectors {
+		literals[i] = vectorLiteral(vector)
+	}
+	if _, err := r.db.ExecContext(ctx, query, pq.Array(stores), pq.Array(ids), pq.Array(literals)); err != nil {
+		return fmt.Errorf("failed to save embeddings: %w", err)
+	}
+	return nil
+}
+
+// vectorLiteral formats a vector in pgvector's text format, [1,2.5,3].
+func vectorLiteral(vector []float32) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, v := range vector {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
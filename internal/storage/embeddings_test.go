@@ -0,0 +1,12 @@
+package storage
+
+import "testing"
+
+func TestVectorLiteral(t *testing.T) {
+	if got := vectorLiteral([]float32{1, -0.25, 3.5e-7}); got != "[1,-0.25,3.5e-07]" {
+		t.Errorf("vectorLiteral = %s", got)
+	}
+	if got := vectorLiteral(nil); got != "[]" {
+		t.Errorf("vectorLiteral(nil) = %s", got)
+	}
+}
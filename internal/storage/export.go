@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// StoredReview is a row of raw_reviews.
+type StoredReview struct {
+	Store           string
+	ID              string
+	AppID           string
+	Country         string
+	Territory       *string
+	Rating          int
+	Title           string
+	Content         string
+	ReviewedAt      time.Time
+	ResponseDate    *time.Time
+	ResponseContent *string
+}
+
+// ExportFilter selects the reviews to export. From and To bound reviewed_at
+// (To is exclusive); zero values leave that side open. An empty Store matches
+// every store.
+type ExportFilter struct {
+	AppID string
+	Store string
+	From  time.Time
+	To    time.Time
+}
+
+// EachReview streams the reviews matching filter to fn in reviewed_at order
+// without loading them all into memory.
+func (r *ReviewRepository) EachReview(ctx context.Context, filter ExportFilter, fn func(StoredReview) error) (int, error) {
+	const query = `
+		SELECT store, id, app_id, country, territory, rating, title, content, reviewed_at, response_date, response_content
+		FROM raw_reviews
+		WHERE app_id = $1
+		  AND ($2 = '' OR store = $2)
+		  AND ($3::timestamptz IS NULL OR reviewed_at >= $3)
+		  AND ($4::timestamptz IS NULL OR reviewed_at < $4)
+		  AND rolled_back_at IS NULL AND deleted_at IS NULL
+		ORDER BY reviewed_at, id;`
+
+	rows, err := r.db.QueryContext(ctx, query, filter.AppID, filter.Store, nullTime(filter.From), nullTime(filter.To))
+	if err != nil {
+		return 0, fmt.Errorf("failed to query reviews: %w", err)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var review StoredReview
+		if err := rows.Scan(&review.Store, &review.ID, &review.AppID, &review.Country, &review.Territory, &review.Rating, &review.Title,
+			&review.Content, &review.ReviewedAt, &review.ResponseDate, &review.ResponseContent); err != nil {
+			return count, fmt.Errorf("failed to scan review: %w", err)
+		}
+		if err := fn(review); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, rows.Err()
+}
+
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// IdentifierRepository maps the identifiers a product has in different
+// stores onto each other: the App Store's numeric app ID, its bundle ID and
+// the Google Play package name.
+type IdentifierRepository struct {
+	db *sql.DB
+}
+
+func NewIdentifierRepository(db *sql.DB) *IdentifierRepository {
+	return &IdentifierRepository{db: db}
+}
+
+// AppIdentifiers are one product's identifiers. Unknown ones are empty.
+type AppIdentifiers struct {
+	AppleID     string
+	BundleID    string
+	PlayPackage string
+}
+
+// FindIdentifiers returns the identifiers of the product that has id as any
+// of them, or nil when it is not mapped.
+func (r *IdentifierRepository) FindIdentifiers(ctx context.Context, id string) (*AppIdentifiers, error) {
+	const query = `
+		SELECT apple_id, COALESCE(bundle_id, ''), COALESCE(play_package, '')
+		FROM app_identifiers
+		WHERE apple_id = $1 OR bundle_id = $1 OR play_package = $1
+		LIMIT 1;`
+
+	var ids AppIdentifiers
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&ids.AppleID, &ids.BundleID, &ids.PlayPackage)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find identifiers of %s: %w", id, err)
+	}
+	return &ids, nil
+}
+
+// SaveIdentifiers records the identifiers of the product with ids.AppleID.
+// Empty identifiers keep the ones already stored.
+func (r *IdentifierRepository) SaveIdentifiers(ctx context.Context, ids AppIdentifiers) error {
+	const query = `
+		INSERT INTO app_identifiers (apple_id, bundle_id, play_package, updated_at)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), NOW())
+		ON CONFLICT (apple_id) DO UPDATE SET
+			bundle_id = COALESCE(EXCLUDED.bundle_id, app_identifiers.bundle_id),
+			play_package = COALESCE(EXCLUDED.play_package, app_identifiers.play_package),
+			updated_at = EXCLUDED.updated_at;`
+
+	if _, err := r.db.ExecContext(ctx, query, ids.AppleID, ids.BundleID, ids.PlayPackage); err != nil {
+		return fmt.Errorf("failed to save identifiers of app %s: %w", ids.AppleID, err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+var (
+	ErrLockNotAcquired = errors.New("ingestion lock held by another worker")
+)
+
+// lockPollInterval is how often a held ingestion lock is tried again.
+const lockPollInterval = 500 * time.Millisecond
+
+// IngestionLocker serializes ingestion of the same storefront (store, app and
+// country) across consumer instances using Postgres session-level advisory
+// locks.
+type IngestionLocker struct {
+	db   *sql.DB
+	wait time.Duration
+}
+
+// NewIngestionLocker returns a locker that waits up to wait for a lock held
+// by another worker.
+func NewIngestionLocker(db *sql.DB, wait time.Duration) *IngestionLocker {
+	return &IngestionLocker{db: db, wait: wait}
+}
+
+// Lock takes the advisory lock for store+appID+country, trying again while
+// another worker holds it for up to the locker's wait, and returns
+// ErrLockNotAcquired when it is still held then. App IDs of different stores
+// may collide, so the store is part of the key. The returned release
+// function must be called once processing is finished.
+func (l *IngestionLocker) Lock(ctx context.Context, store, appID, country string) (func(), error) {
+	key := store + ":" + appID + ":" + country
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection for lock: %w", err)
+	}
+
+	timer := logger.StartTimer()
+	deadline := time.Now().Add(l.wait)
+	for {
+		var acquired bool
+		if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, key).Scan(&acquired); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to acquire lock: %w", err)
+		}
+		if acquired {
+			break
+		}
+		if !time.Now().Before(deadline) {
+			conn.Close()
+			logger.LogEventWithLatency(ctx, "storage.lock.acquired", "failed", timer(), "lock_key", key)
+			return nil, ErrLockNotAcquired
+		}
+		select {
+		case <-ctx.Done():
+			conn.Close()
+			return nil, ctx.Err()
+		case <-time.After(min(lockPollInterval, time.Until(deadline))):
+		}
+	}
+	logger.LogEventWithLatency(ctx, "storage.lock.acquired", "success", timer(), "lock_key", key)
+
+	release := func() {
+		// The context may already be cancelled; unlock with a fresh one so the
+		// connection is returned to the pool without the lock.
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, key); err != nil {
+			logger.Warn(ctx, "Failed to release ingestion lock", "lock_key", key, "error", err.Error())
+		}
+		conn.Close()
+	}
+	return release, nil
+}
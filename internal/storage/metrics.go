@@ -0,0 +1,18 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/metrics"
+)
+
+// reviewWriteSeconds times raw review writes. op is insert, replace, edit or
+// bulk_load and outcome inserted, duplicate, replaced, updated, unchanged,
+// loaded or failed, so the duplicate share of the insert count gives the
+// conflict rate.
+var reviewWriteSeconds = metrics.NewHistogramVec("ingestor_storage_review_write_seconds",
+	"Latency of raw review writes by operation and outcome.", metrics.DefBuckets, "op", "outcome")
+
+func observeWrite(latency time.Duration, op, outcome string) {
+	reviewWriteSeconds.Observe(latency.Seconds(), op, outcome)
+}
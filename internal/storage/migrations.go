@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+// migrationLockKey serializes migrations across instances starting at once.
+const migrationLockKey = "review-ingestor:migrations"
+
+// migration is a schema change applied once, in version order, after the base
+// schema. Concurrent migrations run outside a transaction, as CREATE INDEX
+// CONCURRENTLY requires, so their statements must be safe to rerun after a
+// partial failure.
+type migration struct {
+	version    int
+	name       string
+	statements []string
+	concurrent bool
+}
+
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "raw_reviews_app_country_reviewed_at_idx",
+		// A failed concurrent build leaves an invalid index behind that IF NOT
+		// EXISTS would keep, so it is dropped before building again.
+		statements: []string{
+			`DROP INDEX CONCURRENTLY IF EXISTS raw_reviews_app_country_reviewed_at_idx`,
+			`CREATE INDEX CONCURRENTLY raw_reviews_app_country_reviewed_at_idx ON raw_reviews (app_id, country, reviewed_at DESC)`,
+		},
+		concurrent: true,
+	},
+	{
+		version: 2,
+		name:    "raw_reviews_app_reviewed_at_idx",
+		statements: []string{
+			`DROP INDEX CONCURRENTLY IF EXISTS raw_reviews_app_reviewed_at_idx`,
+			`CREATE INDEX CONCURRENTLY raw_reviews_app_reviewed_at_idx ON raw_reviews (app_id, reviewed_at)`,
+		},
+		concurrent: true,
+	},
+	{
+		version: 3,
+		name:    "raw_reviews_search_vector_backfill",
+		// Rows stored before the search trigger existed; listing language in
+		// SET fires the trigger.
+		statements: []string{
+			`UPDATE raw_reviews SET language = language WHERE search_vector IS NULL`,
+		},
+	},
+	{
+		version: 4,
+		name:    "raw_reviews_search_vector_idx",
+		statements: []string{
+			`DROP INDEX CONCURRENTLY IF EXISTS raw_reviews_search_vector_idx`,
+			`CREATE INDEX CONCURRENTLY raw_reviews_search_vector_idx ON raw_reviews USING GIN (search_vector)`,
+		},
+		concurrent: true,
+	},
+	{
+		version: 5,
+		name:    "raw_reviews_ingest_run_id_idx",
+		statements: []string{
+			`DROP INDEX CONCURRENTLY IF EXISTS raw_reviews_ingest_run_id_idx`,
+			`CREATE INDEX CONCURRENTLY raw_reviews_ingest_run_id_idx ON raw_reviews (ingest_run_id) WHERE ingest_run_id IS NOT NULL`,
+		},
+		concurrent: true,
+	},
+	{
+		version: 6,
+		name:    "ingest_aggregates_store_pkey",
+		// Stores of a multi-store saga may cover the same country.
+		statements: []string{
+			`ALTER TABLE ingest_aggregates DROP CONSTRAINT IF EXISTS ingest_aggregates_pkey`,
+			`ALTER TABLE ingest_aggregates ADD PRIMARY KEY (saga_id, store, country)`,
+		},
+	},
+	{
+		version: 7,
+		name:    "raw_reviews_store_pkey",
+		// Review IDs are only unique within a store: a Steam
+		// recommendationid can equal an App Store review ID. The unique index
+		// is built without blocking writes, and the key is then swapped in one
+		// statement that only takes a brief lock. Attaching the index renames
+		// it to raw_reviews_pkey, so a rerun builds and attaches a new one.
+		statements: []string{
+			`DROP INDEX CONCURRENTLY IF EXISTS raw_reviews_store_id_idx`,
+			`CREATE UNIQUE INDEX CONCURRENTLY raw_reviews_store_id_idx ON raw_reviews (store, id)`,
+			`ALTER TABLE raw_reviews DROP CONSTRAINT IF EXISTS raw_reviews_pkey,
+				ADD CONSTRAINT raw_reviews_pkey PRIMARY KEY USING INDEX raw_reviews_store_id_idx`,
+		},
+		concurrent: true,
+	},
+}
+
+// runMigrations applies the migrations not yet recorded in
+// schema_migrations. It holds an advisory lock for the whole run and has no
+// timeout, since concurrent index builds on large tables take a while.
+func runMigrations(ctx context.Context, db *sql.DB) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection for migrations: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock(hashtext($1))`, migrationLockKey); err != nil {
+		return fmt.Errorf("failed to take migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, migrationLockKey)
+
+	applied := make(map[int]bool)
+	rows, err := conn.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to read applied migrations: %w", err)
+		}
+		applied[version] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	for _, m := range migrations {
+		if applied[m.version] {
+			continue
+		}
+		timer := logger.StartTimer()
+		if err := applyMigration(ctx, conn, m); err != nil {
+			logger.LogEventWithLatency(ctx, "storage.migration.applied", "failed", timer(), "version", m.version, "name", m.name)
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.name, err)
+		}
+		logger.LogEventWithLatency(ctx, "storage.migration.applied", "success", timer(), "version", m.version, "name", m.name)
+	}
+	return nil
+}
+
+func applyMigration(ctx context.Context, conn *sql.Conn, m migration) error {
+	const record = `INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, NOW())`
+
+	if m.concurrent {
+		for _, statement := range m.statements {
+			if _, err := conn.ExecContext(ctx, statement); err != nil {
+				return err
+			}
+		}
+		_, err := conn.ExecContext(ctx, record, m.version, m.name)
+		return err
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, statement := range m.statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, record, m.version, m.name); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
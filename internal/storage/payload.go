@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// payloadHash returns the content address of a raw review payload and the
+// compacted JSON stored under it, so payloads differing only in whitespace
+// share a row. An empty payload has an empty hash.
+func payloadHash(payload json.RawMessage) (string, []byte, error) {
+	if len(payload) == 0 {
+		return "", nil, nil
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, payload); err != nil {
+		return "", nil, err
+	}
+	sum := sha256.Sum256(compact.Bytes())
+	return hex.EncodeToString(sum[:]), compact.Bytes(), nil
+}
@@ -0,0 +1,30 @@
+package storage
+
+import "testing"
+
+func TestPayloadHash(t *testing.T) {
+	hash, compact, err := payloadHash([]byte(`{ "id": "1",
+		"attributes": {"rating": 5} }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(compact) != `{"id":"1","attributes":{"rating":5}}` {
+		t.Errorf("compact = %s", compact)
+	}
+
+	same, _, _ := payloadHash([]byte(`{"id":"1","attributes":{"rating":5}}`))
+	if same != hash {
+		t.Errorf("whitespace changed the hash: %s != %s", same, hash)
+	}
+	other, _, _ := payloadHash([]byte(`{"id":"1","attributes":{"rating":4}}`))
+	if other == hash {
+		t.Error("different payloads share a hash")
+	}
+
+	if empty, _, err := payloadHash(nil); empty != "" || err != nil {
+		t.Errorf("payloadHash(nil) = %q, %v", empty, err)
+	}
+	if _, _, err := payloadHash([]byte(`{broken`)); err == nil {
+		t.Error("invalid JSON hashed without error")
+	}
+}
@@ -3,10 +3,12 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/quiby-ai/review-ingestor/config"
 	"github.com/quiby-ai/review-ingestor/internal/logger"
 )
@@ -31,6 +33,11 @@ func InitPostgres(cfg config.PostgresConfig) (*sql.DB, error) {
 		return nil, fmt.Errorf("failed to migrate schema: %w", err)
 	}
 
+	if err := runMigrations(context.Background(), db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+
 	return db, nil
 }
 
@@ -52,6 +59,184 @@ func migrateSchema(db *sql.DB) error {
 		reviewed_at TIMESTAMPTZ NOT NULL,
 		response_date TIMESTAMPTZ,
 		response_content TEXT
+	);
+
+	ALTER TABLE raw_reviews ADD COLUMN IF NOT EXISTS store TEXT NOT NULL DEFAULT 'appstore';
+	ALTER TABLE raw_reviews ADD COLUMN IF NOT EXISTS territory TEXT;
+	ALTER TABLE raw_reviews ADD COLUMN IF NOT EXISTS app_version TEXT;
+	ALTER TABLE raw_reviews ADD COLUMN IF NOT EXISTS reviewed_at_offset_minutes INTEGER;
+	ALTER TABLE raw_reviews ADD COLUMN IF NOT EXISTS payload_hash TEXT;
+	ALTER TABLE raw_reviews ADD COLUMN IF NOT EXISTS quality_flag TEXT;
+	ALTER TABLE raw_reviews ADD COLUMN IF NOT EXISTS content_plain TEXT;
+	ALTER TABLE raw_reviews ADD COLUMN IF NOT EXISTS language TEXT;
+	ALTER TABLE raw_reviews ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+	ALTER TABLE raw_reviews ADD COLUMN IF NOT EXISTS content_translated TEXT;
+	ALTER TABLE raw_reviews ADD COLUMN IF NOT EXISTS likely_version TEXT;
+	ALTER TABLE raw_reviews ADD COLUMN IF NOT EXISTS ingest_run_id TEXT;
+	ALTER TABLE raw_reviews ADD COLUMN IF NOT EXISTS rolled_back_at TIMESTAMPTZ;
+	ALTER TABLE raw_reviews ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+	ALTER TABLE raw_reviews ADD COLUMN IF NOT EXISTS is_edited BOOLEAN NOT NULL DEFAULT FALSE;
+	ALTER TABLE raw_reviews ADD COLUMN IF NOT EXISTS edited_at TIMESTAMPTZ;
+	ALTER TABLE raw_reviews ADD COLUMN IF NOT EXISTS content_fingerprint TEXT;
+
+	-- review_search_config picks the text search configuration for an ISO
+	-- 639-1 language code; languages Postgres has no stemmer for use simple.
+	CREATE OR REPLACE FUNCTION review_search_config(language TEXT) RETURNS regconfig AS $$
+		SELECT (CASE language
+			WHEN 'ar' THEN 'arabic' WHEN 'da' THEN 'danish' WHEN 'de' THEN 'german' WHEN 'el' THEN 'greek'
+			WHEN 'en' THEN 'english' WHEN 'es' THEN 'spanish' WHEN 'fi' THEN 'finnish' WHEN 'fr' THEN 'french'
+			WHEN 'hu' THEN 'hungarian' WHEN 'id' THEN 'indonesian' WHEN 'it' THEN 'italian' WHEN 'lt' THEN 'lithuanian'
+			WHEN 'ne' THEN 'nepali' WHEN 'nl' THEN 'dutch' WHEN 'no' THEN 'norwegian' WHEN 'pt' THEN 'portuguese'
+			WHEN 'ro' THEN 'romanian' WHEN 'ru' THEN 'russian' WHEN 'sv' THEN 'swedish' WHEN 'tr' THEN 'turkish'
+			ELSE 'simple'
+		END)::regconfig
+	$$ LANGUAGE SQL IMMUTABLE;
+
+	CREATE OR REPLACE FUNCTION raw_reviews_search_vector() RETURNS trigger AS $$
+	BEGIN
+		NEW.search_vector :=
+			setweight(to_tsvector(review_search_config(NEW.language), COALESCE(NEW.title, '')), 'A') ||
+			setweight(to_tsvector(review_search_config(NEW.language), COALESCE(NEW.content_plain, NEW.content, '')), 'B');
+		RETURN NEW;
+	END
+	$$ LANGUAGE plpgsql;
+
+	CREATE OR REPLACE TRIGGER raw_reviews_search_vector_trg
+		BEFORE INSERT OR UPDATE OF title, content, content_plain, language ON raw_reviews
+		FOR EACH ROW EXECUTE FUNCTION raw_reviews_search_vector();
+
+	CREATE INDEX IF NOT EXISTS raw_reviews_reviewed_at_idx ON raw_reviews (reviewed_at);
+
+	CREATE TABLE IF NOT EXISTS raw_payloads (
+		hash TEXT PRIMARY KEY,
+		payload JSONB NOT NULL,
+		first_seen_at TIMESTAMPTZ NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS storefront_health (
+		store TEXT NOT NULL,
+		country TEXT NOT NULL,
+		consecutive_failures INTEGER NOT NULL DEFAULT 0,
+		last_failure_at TIMESTAMPTZ,
+		cooldown_until TIMESTAMPTZ,
+		PRIMARY KEY (store, country)
+	);
+
+	CREATE TABLE IF NOT EXISTS app_storefronts (
+		app_id TEXT PRIMARY KEY,
+		storefronts TEXT[] NOT NULL,
+		discovered_at TIMESTAMPTZ NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS http_response_cache (
+		url TEXT PRIMARY KEY,
+		body BYTEA NOT NULL,
+		fetched_at TIMESTAMPTZ NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS ingestion_pauses (
+		scope TEXT PRIMARY KEY,
+		paused_at TIMESTAMPTZ NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS start_overrides (
+		store TEXT NOT NULL,
+		app_id TEXT NOT NULL,
+		country TEXT NOT NULL,
+		start_offset INTEGER NOT NULL DEFAULT 0,
+		start_before TIMESTAMPTZ,
+		created_at TIMESTAMPTZ NOT NULL,
+		PRIMARY KEY (store, app_id, country)
+	);
+
+	CREATE TABLE IF NOT EXISTS parked_sagas (
+		saga_id TEXT PRIMARY KEY,
+		app_id TEXT NOT NULL,
+		request JSONB NOT NULL,
+		remaining_countries TEXT[] NOT NULL,
+		progress JSONB NOT NULL,
+		parked_at TIMESTAMPTZ NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS cancelled_sagas (
+		saga_id TEXT PRIMARY KEY,
+		app_id TEXT NOT NULL,
+		request JSONB NOT NULL,
+		remaining_countries TEXT[] NOT NULL,
+		progress JSONB NOT NULL,
+		cancelled_at TIMESTAMPTZ NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS quarantined_reviews (
+		id BIGSERIAL PRIMARY KEY,
+		store TEXT NOT NULL,
+		review_id TEXT NOT NULL,
+		app_id TEXT NOT NULL,
+		country VARCHAR(2) NOT NULL,
+		raw JSONB NOT NULL,
+		reason TEXT NOT NULL,
+		quarantined_at TIMESTAMPTZ NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS schema_drift_samples (
+		id BIGSERIAL PRIMARY KEY,
+		store TEXT NOT NULL,
+		review_id TEXT NOT NULL,
+		app_id TEXT NOT NULL,
+		country VARCHAR(2) NOT NULL,
+		issues TEXT[] NOT NULL,
+		raw JSONB NOT NULL,
+		detected_at TIMESTAMPTZ NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS app_versions (
+		store TEXT NOT NULL,
+		app_id TEXT NOT NULL,
+		version TEXT NOT NULL,
+		released_at TIMESTAMPTZ NOT NULL,
+		release_notes TEXT,
+		first_seen_at TIMESTAMPTZ NOT NULL,
+		PRIMARY KEY (store, app_id, version)
+	);
+
+	CREATE TABLE IF NOT EXISTS ingest_aggregates (
+		saga_id TEXT NOT NULL,
+		store TEXT NOT NULL,
+		app_id TEXT NOT NULL,
+		country TEXT NOT NULL,
+		reviews INTEGER NOT NULL,
+		average DOUBLE PRECISION NOT NULL,
+		promoters INTEGER NOT NULL,
+		passives INTEGER NOT NULL,
+		detractors INTEGER NOT NULL,
+		nps DOUBLE PRECISION NOT NULL,
+		computed_at TIMESTAMPTZ NOT NULL,
+		PRIMARY KEY (saga_id, store, country)
+	);
+
+	CREATE TABLE IF NOT EXISTS usage_accounting (
+		tenant TEXT NOT NULL,
+		day DATE NOT NULL,
+		store TEXT NOT NULL,
+		requests BIGINT NOT NULL DEFAULT 0,
+		rate_limited BIGINT NOT NULL DEFAULT 0,
+		bytes BIGINT NOT NULL DEFAULT 0,
+		backoff_seconds DOUBLE PRECISION NOT NULL DEFAULT 0,
+		updated_at TIMESTAMPTZ NOT NULL,
+		PRIMARY KEY (tenant, day, store)
+	);
+
+	CREATE TABLE IF NOT EXISTS app_identifiers (
+		apple_id TEXT PRIMARY KEY,
+		bundle_id TEXT UNIQUE,
+		play_package TEXT UNIQUE,
+		updated_at TIMESTAMPTZ NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL
 	);`
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -65,33 +250,506 @@ func migrateSchema(db *sql.DB) error {
 }
 
 type ReviewRepository struct {
-	db *sql.DB
+	db     *sql.DB
+	cfg    config.PostgresConfig
+	faults FaultInjector
+}
+
+// FaultInjector fails review writes on purpose for resilience testing.
+type FaultInjector interface {
+	DBError(ctx context.Context) error
+}
+
+func NewReviewRepository(db *sql.DB, cfg config.PostgresConfig) *ReviewRepository {
+	return &ReviewRepository{db: db, cfg: cfg}
 }
 
-func NewReviewRepository(db *sql.DB) *ReviewRepository {
-	return &ReviewRepository{db: db}
+// SetFaultInjector makes each write attempt first ask faults for an injected
+// error.
+func (r *ReviewRepository) SetFaultInjector(faults FaultInjector) {
+	r.faults = faults
+}
+
+// insertRawReview stores a review and its payload, leaving reviews that are
+// already stored untouched unless their run was rolled back, which are
+// restored. It returns the review's ID when it was inserted or restored.
+const insertRawReview = `
+	WITH payload AS (
+		INSERT INTO raw_payloads (hash, payload, first_seen_at)
+		SELECT $14, $15::jsonb, NOW() WHERE $14 <> ''
+		ON CONFLICT (hash) DO NOTHING
+	)
+	INSERT INTO raw_reviews (id, app_id, country, rating, title, content, reviewed_at, response_date, response_content, store, territory, app_version, reviewed_at_offset_minutes, payload_hash, quality_flag, content_plain, language, ingest_run_id, is_edited, edited_at, content_fingerprint)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), NULLIF($12, ''), $13, NULLIF($14, ''), NULLIF($16, ''), $17, NULLIF($18, ''), NULLIF($19, ''), $20::timestamptz IS NOT NULL, $20, NULLIF($21, ''))
+	ON CONFLICT (store, id) DO UPDATE SET ingest_run_id = EXCLUDED.ingest_run_id, rolled_back_at = NULL
+		WHERE raw_reviews.rolled_back_at IS NOT NULL
+	RETURNING id;`
+
+// rawReviewArgs returns the parameters of insertRawReview and
+// ReplaceRawReview for review.
+func rawReviewArgs(review RawReview) ([]any, error) {
+	hash, compact, err := payloadHash(review.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid review payload: %w", err)
+	}
+	reviewedAt, offset := utcWithOffset(review.ReviewedAt)
+	return []any{review.ID, review.AppID, review.Country, review.Rating, review.Title, review.Content, reviewedAt, utcPtr(review.ResponseDate), review.ResponseContent,
+		review.Store, review.Territory, review.AppVersion, offset, hash, nullIfEmpty(string(compact)), review.QualityFlag, review.ContentPlain, review.Language,
+		review.IngestRunID, utcPtr(review.EditedAt), review.Fingerprint}, nil
+}
+
+// SaveRawReview inserts a review and reports whether it was new. Reviews that
+// are already stored are left untouched and reported as not inserted, unless
+// their run was rolled back: those are restored and reported as new.
+func (r *ReviewRepository) SaveRawReview(ctx context.Context, review RawReview) (bool, error) {
+	args, err := rawReviewArgs(review)
+	if err != nil {
+		return false, err
+	}
+
+	timer := logger.StartTimer()
+	var insertedID string
+	err = r.withRetry(ctx, "insert", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, insertRawReview, args...).Scan(&insertedID)
+	})
+
+	latency := timer()
+	if errors.Is(err, sql.ErrNoRows) {
+		observeWrite(latency, "insert", "duplicate")
+		logger.LogEventWithLatency(ctx, "storage.review.duplicate", "skipped", latency, "review_id", review.ID)
+		return false, nil
+	}
+	if err != nil {
+		observeWrite(latency, "insert", "failed")
+		logger.LogEventWithLatency(ctx, "storage.review.saved", "failed", latency, "review_id", review.ID)
+		return false, err
+	}
+
+	observeWrite(latency, "insert", "inserted")
+	logger.LogEventWithLatency(ctx, "storage.review.saved", "success", latency, "review_id", review.ID)
+	return true, nil
+}
+
+// SaveRawReviews saves reviews like SaveRawReview, in one transaction with
+// one statement each, and returns the IDs of the reviews inserted or
+// restored. When it fails none of them are saved. BulkLoadRawReviews is
+// faster for large batches.
+func (r *ReviewRepository) SaveRawReviews(ctx context.Context, reviews []RawReview) ([]string, error) {
+	args := make([][]any, len(reviews))
+	for i, review := range reviews {
+		reviewArgs, err := rawReviewArgs(review)
+		if err != nil {
+			return nil, fmt.Errorf("review %s: %w", review.ID, err)
+		}
+		args[i] = reviewArgs
+	}
+
+	timer := logger.StartTimer()
+	var inserted []string
+	err := r.withRetry(ctx, "insert_batch", func(ctx context.Context) error {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		stmt, err := tx.PrepareContext(ctx, insertRawReview)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		var ids []string
+		for _, reviewArgs := range args {
+			var id string
+			err := stmt.QueryRowContext(ctx, reviewArgs...).Scan(&id)
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		inserted = ids
+		return nil
+	})
+
+	latency := timer()
+	if err != nil {
+		observeWrite(latency, "insert_batch", "failed")
+		logger.LogEventWithLatency(ctx, "storage.reviews.saved", "failed", latency, "reviews", len(reviews))
+		return nil, fmt.Errorf("failed to save reviews: %w", err)
+	}
+	observeWrite(latency, "insert_batch", "saved")
+	logger.LogEventWithLatency(ctx, "storage.reviews.saved", "success", latency, "reviews", len(reviews), "inserted", len(inserted))
+	return inserted, nil
 }
 
-func (r *ReviewRepository) SaveRawReview(ctx context.Context, id, appID, country string, rating int, title, content string, reviewedAt time.Time, responseDate *time.Time, responseContent *string) error {
+// ReplaceRawReview inserts a review or overwrites the stored copy, and reports
+// whether it was new. Used when replaying archived pages so parsing fixes
+// reach reviews that are already stored. The stored IngestRunID is kept.
+func (r *ReviewRepository) ReplaceRawReview(ctx context.Context, review RawReview) (bool, error) {
 	const query = `
-		INSERT INTO raw_reviews (id, app_id, country, rating, title, content, reviewed_at, response_date, response_content)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		ON CONFLICT (id) DO NOTHING;`
+		WITH payload AS (
+			INSERT INTO raw_payloads (hash, payload, first_seen_at)
+			SELECT $14, $15::jsonb, NOW() WHERE $14 <> ''
+			ON CONFLICT (hash) DO NOTHING
+		)
+		INSERT INTO raw_reviews (id, app_id, country, rating, title, content, reviewed_at, response_date, response_content, store, territory, app_version, reviewed_at_offset_minutes, payload_hash, quality_flag, content_plain, language, ingest_run_id, is_edited, edited_at, content_fingerprint)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), NULLIF($12, ''), $13, NULLIF($14, ''), NULLIF($16, ''), $17, NULLIF($18, ''), NULLIF($19, ''), $20::timestamptz IS NOT NULL, $20, NULLIF($21, ''))
+		ON CONFLICT (store, id) DO UPDATE SET
+			app_id = EXCLUDED.app_id,
+			country = EXCLUDED.country,
+			rating = EXCLUDED.rating,
+			title = EXCLUDED.title,
+			content = EXCLUDED.content,
+			reviewed_at = EXCLUDED.reviewed_at,
+			response_date = EXCLUDED.response_date,
+			response_content = EXCLUDED.response_content,
+			store = EXCLUDED.store,
+			territory = EXCLUDED.territory,
+			app_version = EXCLUDED.app_version,
+			reviewed_at_offset_minutes = EXCLUDED.reviewed_at_offset_minutes,
+			payload_hash = COALESCE(EXCLUDED.payload_hash, raw_reviews.payload_hash),
+			quality_flag = EXCLUDED.quality_flag,
+			content_plain = EXCLUDED.content_plain,
+			language = EXCLUDED.language,
+			is_edited = EXCLUDED.is_edited,
+			edited_at = EXCLUDED.edited_at,
+			content_fingerprint = EXCLUDED.content_fingerprint,
+			rolled_back_at = NULL
+		RETURNING (xmax = 0);`
+
+	args, err := rawReviewArgs(review)
+	if err != nil {
+		return false, err
+	}
 
 	timer := logger.StartTimer()
-	result, err := r.db.ExecContext(ctx, query, id, appID, country, rating, title, content, reviewedAt, responseDate, responseContent)
+	var inserted bool
+	err = r.withRetry(ctx, "replace", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, query, args...).Scan(&inserted)
+	})
+	latency := timer()
+	if err != nil {
+		observeWrite(latency, "replace", "failed")
+		logger.LogEventWithLatency(ctx, "storage.review.replaced", "failed", latency, "review_id", review.ID)
+		return false, err
+	}
+
+	outcome := "replaced"
+	if inserted {
+		outcome = "inserted"
+	}
+	observeWrite(latency, "replace", outcome)
+	logger.LogEventWithLatency(ctx, "storage.review.replaced", "success", latency, "review_id", review.ID, "inserted", inserted)
+	return inserted, nil
+}
 
+// RefreshReview overwrites the rating, text and developer response of a
+// stored review when any of them differ, and records review.EditedAt and
+// review.Fingerprint when set. It reports whether the review was updated.
+func (r *ReviewRepository) RefreshReview(ctx context.Context, review RawReview) (bool, error) {
+	const query = `
+		UPDATE raw_reviews SET
+			rating = $2,
+			title = $3,
+			content = $4,
+			content_plain = $5,
+			quality_flag = NULLIF($6, ''),
+			response_date = $7,
+			response_content = $8,
+			is_edited = is_edited OR $9::timestamptz IS NOT NULL,
+			edited_at = COALESCE($9::timestamptz, edited_at),
+			content_fingerprint = COALESCE(NULLIF($10, ''), content_fingerprint)
+		WHERE store = $11 AND id = $1 AND (rating IS DISTINCT FROM $2 OR title IS DISTINCT FROM $3 OR content IS DISTINCT FROM $4
+			OR response_date IS DISTINCT FROM $7 OR response_content IS DISTINCT FROM $8);`
+
+	timer := logger.StartTimer()
+	var updated int64
+	err := r.withRetry(ctx, "refresh", func(ctx context.Context) error {
+		res, err := r.db.ExecContext(ctx, query, review.ID, review.Rating, review.Title, review.Content, review.ContentPlain, review.QualityFlag,
+			utcPtr(review.ResponseDate), review.ResponseContent, utcPtr(review.EditedAt), review.Fingerprint, review.Store)
+		if err != nil {
+			return err
+		}
+		updated, err = res.RowsAffected()
+		return err
+	})
+	latency := timer()
 	if err != nil {
-		logger.LogEventWithLatency(ctx, "storage.review.saved", "failed", timer(), "review_id", id)
+		observeWrite(latency, "refresh", "failed")
+		logger.LogEventWithLatency(ctx, "storage.review.refreshed", "failed", latency, "review_id", review.ID)
+		return false, err
+	}
+	if updated == 0 {
+		observeWrite(latency, "refresh", "unchanged")
+		return false, nil
+	}
+	observeWrite(latency, "refresh", "updated")
+	logger.LogEventWithLatency(ctx, "storage.review.refreshed", "success", latency, "review_id", review.ID)
+	return true, nil
+}
+
+// UpdateEditedReview applies an edit to a stored review: its rating and text
+// are overwritten when its fingerprint differs from the stored one, which
+// tells an edit from a redelivery of the same review without comparing text.
+// A review the store reports as edited must also have a newer
+// review.EditedAt than the last edit stored; one edited without the store
+// saying so is recorded as edited now. Reviews stored before fingerprints
+// only take edits the store reports. It reports whether the review was
+// updated.
+func (r *ReviewRepository) UpdateEditedReview(ctx context.Context, review RawReview) (bool, error) {
+	const query = `
+		UPDATE raw_reviews SET
+			rating = $2,
+			title = $3,
+			content = $4,
+			content_plain = $5,
+			quality_flag = NULLIF($6, ''),
+			is_edited = TRUE,
+			edited_at = COALESCE($7, NOW()),
+			content_fingerprint = $8
+		WHERE store = $9 AND id = $1 AND content_fingerprint IS DISTINCT FROM $8 AND CASE
+			WHEN $7::timestamptz IS NULL THEN content_fingerprint IS NOT NULL
+			ELSE edited_at IS NULL OR edited_at < $7
+		END;`
+
+	if review.Fingerprint == "" {
+		return false, nil
+	}
+
+	timer := logger.StartTimer()
+	var updated int64
+	err := r.withRetry(ctx, "edit", func(ctx context.Context) error {
+		res, err := r.db.ExecContext(ctx, query, review.ID, review.Rating, review.Title, review.Content, review.ContentPlain, review.QualityFlag, utcPtr(review.EditedAt), review.Fingerprint, review.Store)
+		if err != nil {
+			return err
+		}
+		updated, err = res.RowsAffected()
 		return err
+	})
+	latency := timer()
+	if err != nil {
+		observeWrite(latency, "edit", "failed")
+		logger.LogEventWithLatency(ctx, "storage.review.edited", "failed", latency, "review_id", review.ID)
+		return false, err
+	}
+	if updated == 0 {
+		observeWrite(latency, "edit", "unchanged")
+		return false, nil
+	}
+	observeWrite(latency, "edit", "updated")
+	logger.LogEventWithLatency(ctx, "storage.review.edited", "success", latency, "review_id", review.ID)
+	return true, nil
+}
+
+// ReviewFingerprint returns the content fingerprint stored for a review and
+// whether the review is stored at all. Reviews stored before fingerprints
+// have an empty one.
+func (r *ReviewRepository) ReviewFingerprint(ctx context.Context, key ReviewKey) (string, bool, error) {
+	const query = `SELECT COALESCE(content_fingerprint, '') FROM raw_reviews WHERE store = $1 AND id = $2;`
+
+	var fingerprint string
+	err := r.db.QueryRowContext(ctx, query, key.Store, key.ID).Scan(&fingerprint)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read review fingerprint: %w", err)
 	}
+	return fingerprint, true, nil
+}
+
+// ReviewKey identifies a stored review. Review IDs are only unique within a
+// store.
+type ReviewKey struct {
+	Store string
+	ID    string
+}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		logger.LogEventWithLatency(ctx, "storage.review.duplicate", "skipped", timer(), "review_id", id)
-	} else {
-		logger.LogEventWithLatency(ctx, "storage.review.saved", "success", timer(), "review_id", id)
+// RawReview is a review row as written to raw_reviews. Territory and
+// AppVersion are stored as NULL when empty.
+type RawReview struct {
+	Store string
+	ID    string
+	AppID string
+	// Country is the storefront that was queried and Territory the origin
+	// the store reported for the review, if any.
+	Country    string
+	Territory  string
+	AppVersion string
+	Rating     int
+	Title      string
+	// Content is the body as normalize.Text returns it.
+	Content string
+	// ReviewedAt is stored in UTC together with its original offset, so set
+	// it in the zone the source reported.
+	ReviewedAt      time.Time
+	ResponseDate    *time.Time
+	ResponseContent *string
+	// Payload is the raw review JSON, stored by content hash when set.
+	Payload json.RawMessage
+	// QualityFlag marks reviews downstream NLP should skip, see
+	// normalize.QualityFlag. Stored as NULL when empty.
+	QualityFlag string
+	// ContentPlain is Content without markup, entities and emoji, for full
+	// text search and embeddings.
+	ContentPlain string
+	// Language is the ISO 639-1 code of the review's language, see
+	// normalize.Language; it picks the full-text search stemmer. Stored as
+	// NULL when empty.
+	Language string
+	// IngestRunID is the saga that first stored the review, so the run can
+	// be rolled back. Empty for reviews stored outside a saga.
+	IngestRunID string
+	// EditedAt is when the author last edited the review, or nil when the
+	// store does not report it as edited.
+	EditedAt *time.Time
+	// Fingerprint is the normalize.Fingerprint of the rating, title and
+	// body. Stored as NULL when empty.
+	Fingerprint string
+}
+
+// Key returns the key the review is stored under.
+func (r RawReview) Key() ReviewKey {
+	return ReviewKey{Store: r.Store, ID: r.ID}
+}
+
+var rawReviewColumns = []string{
+	"id", "app_id", "country", "rating", "title", "content", "reviewed_at", "response_date",
+	"response_content", "store", "territory", "app_version", "reviewed_at_offset_minutes", "payload_hash", "quality_flag",
+	"content_plain", "language", "ingest_run_id", "is_edited", "edited_at", "content_fingerprint",
+}
+
+// BulkLoadRawReviews stages reviews with COPY into a temporary table and
+// merges them into raw_reviews in one transaction, which is much faster than
+// row-by-row inserts for backfills. Reviews that are already stored are left
+// untouched, except those of a rolled-back run, which are restored. It
+// returns the IDs of the reviews inserted or restored.
+func (r *ReviewRepository) BulkLoadRawReviews(ctx context.Context, reviews []RawReview) ([]string, error) {
+	const merge = `
+		INSERT INTO raw_reviews (id, app_id, country, rating, title, content, reviewed_at, response_date, response_content, store, territory, app_version, reviewed_at_offset_minutes, payload_hash, quality_flag, content_plain, language, ingest_run_id, is_edited, edited_at, content_fingerprint)
+		SELECT DISTINCT ON (store, id) id, app_id, country, rating, title, content, reviewed_at, response_date, response_content, store, territory, app_version, reviewed_at_offset_minutes, payload_hash, quality_flag, content_plain, language, ingest_run_id, is_edited, edited_at, content_fingerprint
+		FROM raw_reviews_stage
+		ORDER BY store, id
+		ON CONFLICT (store, id) DO UPDATE SET ingest_run_id = EXCLUDED.ingest_run_id, rolled_back_at = NULL
+			WHERE raw_reviews.rolled_back_at IS NOT NULL
+		RETURNING id;`
+	const storePayloads = `
+		INSERT INTO raw_payloads (hash, payload, first_seen_at)
+		SELECT hash, payload::jsonb, NOW() FROM unnest($1::text[], $2::text[]) AS p(hash, payload)
+		ON CONFLICT (hash) DO NOTHING;`
+
+	hashes := make([]string, len(reviews))
+	var payloadHashes, payloads []string
+	for i, review := range reviews {
+		hash, compact, err := payloadHash(review.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid payload of review %s: %w", review.ID, err)
+		}
+		hashes[i] = hash
+		if hash != "" {
+			payloadHashes = append(payloadHashes, hash)
+			payloads = append(payloads, string(compact))
+		}
 	}
 
-	return nil
+	timer := logger.StartTimer()
+	var inserted []string
+	err := r.withRetry(ctx, "bulk_load", func(ctx context.Context) error {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.ExecContext(ctx, `CREATE TEMP TABLE raw_reviews_stage (LIKE raw_reviews INCLUDING DEFAULTS) ON COMMIT DROP`); err != nil {
+			return err
+		}
+		stmt, err := tx.PrepareContext(ctx, pq.CopyIn("raw_reviews_stage", rawReviewColumns...))
+		if err != nil {
+			return err
+		}
+		for i, review := range reviews {
+			reviewedAt, offset := utcWithOffset(review.ReviewedAt)
+			if _, err := stmt.ExecContext(ctx, review.ID, review.AppID, review.Country, review.Rating, review.Title, review.Content, reviewedAt,
+				utcPtr(review.ResponseDate), review.ResponseContent, review.Store, nullIfEmpty(review.Territory), nullIfEmpty(review.AppVersion), offset,
+				nullIfEmpty(hashes[i]), nullIfEmpty(review.QualityFlag), review.ContentPlain, nullIfEmpty(review.Language), nullIfEmpty(review.IngestRunID), review.EditedAt != nil, utcPtr(review.EditedAt), nullIfEmpty(review.Fingerprint)); err != nil {
+				stmt.Close()
+				return err
+			}
+		}
+		if _, err := stmt.ExecContext(ctx); err != nil {
+			stmt.Close()
+			return err
+		}
+		if err := stmt.Close(); err != nil {
+			return err
+		}
+		if len(payloadHashes) > 0 {
+			if _, err := tx.ExecContext(ctx, storePayloads, pq.Array(payloadHashes), pq.Array(payloads)); err != nil {
+				return err
+			}
+		}
+
+		rows, err := tx.QueryContext(ctx, merge)
+		if err != nil {
+			return err
+		}
+		var ids []string
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		inserted = ids
+		return nil
+	})
+
+	latency := timer()
+	if err != nil {
+		observeWrite(latency, "bulk_load", "failed")
+		logger.LogEventWithLatency(ctx, "storage.reviews.bulk_loaded", "failed", latency, "reviews", len(reviews))
+		return nil, fmt.Errorf("failed to bulk load reviews: %w", err)
+	}
+	observeWrite(latency, "bulk_load", "loaded")
+	logger.LogEventWithLatency(ctx, "storage.reviews.bulk_loaded", "success", latency, "reviews", len(reviews), "inserted", len(inserted))
+	return inserted, nil
+}
+
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// utcWithOffset converts t to UTC and returns the offset of its original
+// zone in minutes.
+func utcWithOffset(t time.Time) (time.Time, int) {
+	_, offset := t.Zone()
+	return t.UTC(), offset / 60
+}
+
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
 }
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/quiby-ai/common/pkg/events"
+)
+
+// SpooledEvent is an event envelope that could not be published, kept until
+// Kafka accepts it. Name identifies the spool file.
+type SpooledEvent struct {
+	Name      string               `json:"-"`
+	Key       []byte               `json:"key"`
+	Envelope  events.Envelope[any] `json:"envelope"`
+	SpooledAt time.Time            `json:"spooled_at"`
+}
+
+// PublishSpool keeps unpublished events as files in a local directory, named
+// so that they list in the order they were spooled.
+type PublishSpool struct {
+	dir string
+}
+
+func NewPublishSpool(dir string) (*PublishSpool, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create publish spool %s: %w", dir, err)
+	}
+	return &PublishSpool{dir: dir}, nil
+}
+
+// Save writes event to the spool.
+func (s *PublishSpool) Save(event SpooledEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode spooled event %s: %w", event.Envelope.MessageID, err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".event-*")
+	if err != nil {
+		return fmt.Errorf("failed to spool event %s: %w", event.Envelope.MessageID, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to spool event %s: %w", event.Envelope.MessageID, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to spool event %s: %w", event.Envelope.MessageID, err)
+	}
+
+	// The temporary suffix keeps names unique for events spooled in the
+	// same nanosecond.
+	name := fmt.Sprintf("%020d-%s.json", event.SpooledAt.UnixNano(), strings.TrimPrefix(filepath.Base(tmp.Name()), ".event-"))
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, name)); err != nil {
+		return fmt.Errorf("failed to spool event %s: %w", event.Envelope.MessageID, err)
+	}
+	return nil
+}
+
+// List returns the spooled events, oldest first. Files that cannot be
+// decoded are skipped and reported in the error alongside the readable
+// events.
+func (s *PublishSpool) List() ([]SpooledEvent, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list publish spool: %w", err)
+	}
+
+	var spooled []SpooledEvent
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var event SpooledEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			errs = append(errs, fmt.Errorf("failed to decode %s: %w", entry.Name(), err))
+			continue
+		}
+		event.Name = entry.Name()
+		spooled = append(spooled, event)
+	}
+	return spooled, errors.Join(errs...)
+}
+
+// Remove deletes the spooled event named name, if any.
+func (s *PublishSpool) Remove(name string) error {
+	if err := os.Remove(filepath.Join(s.dir, filepath.Base(name))); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove spooled event %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+// QuarantinedReview is a review that could not be parsed or saved, kept with
+// its raw payload so it can be reprocessed later.
+type QuarantinedReview struct {
+	ID            int64
+	Store         string
+	ReviewID      string
+	AppID         string
+	Country       string
+	Raw           []byte
+	Reason        string
+	QuarantinedAt time.Time
+}
+
+type QuarantineRepository struct {
+	db *sql.DB
+}
+
+func NewQuarantineRepository(db *sql.DB) *QuarantineRepository {
+	return &QuarantineRepository{db: db}
+}
+
+func (r *QuarantineRepository) Quarantine(ctx context.Context, review QuarantinedReview) error {
+	const query = `
+		INSERT INTO quarantined_reviews (store, review_id, app_id, country, raw, reason, quarantined_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);`
+
+	_, err := r.db.ExecContext(ctx, query, review.Store, review.ReviewID, review.AppID, review.Country, review.Raw, review.Reason, time.Now())
+	if err != nil {
+		logger.LogEvent(ctx, "storage.review.quarantined", "failed", "reason", review.Reason)
+		return fmt.Errorf("failed to quarantine review %s: %w", review.ReviewID, err)
+	}
+	logger.LogEvent(ctx, "storage.review.quarantined", "success", "reason", review.Reason)
+	return nil
+}
+
+// List returns up to limit quarantined reviews with an ID greater than afterID,
+// oldest first.
+func (r *QuarantineRepository) List(ctx context.Context, afterID int64, limit int) ([]QuarantinedReview, error) {
+	const query = `
+		SELECT id, store, review_id, app_id, country, raw, reason, quarantined_at
+		FROM quarantined_reviews
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2;`
+
+	rows, err := r.db.QueryContext(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quarantined reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var reviews []QuarantinedReview
+	for rows.Next() {
+		var review QuarantinedReview
+		if err := rows.Scan(&review.ID, &review.Store, &review.ReviewID, &review.AppID, &review.Country, &review.Raw, &review.Reason, &review.QuarantinedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan quarantined review: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, rows.Err()
+}
+
+func (r *QuarantineRepository) Delete(ctx context.Context, id int64) error {
+	const query = `DELETE FROM quarantined_reviews WHERE id = $1;`
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete quarantined review %d: %w", id, err)
+	}
+	return nil
+}
+
+// RecordDrift keeps a raw review whose structure differs from what the parser
+// expects, so the drift can be inspected and the parser updated.
+func (r *QuarantineRepository) RecordDrift(ctx context.Context, store, appID, country, reviewID string, issues []string, raw []byte) error {
+	const query = `
+		INSERT INTO schema_drift_samples (store, review_id, app_id, country, issues, raw, detected_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);`
+
+	_, err := r.db.ExecContext(ctx, query, store, reviewID, appID, country, pq.Array(issues), raw, time.Now())
+	if err != nil {
+		logger.LogEvent(ctx, "storage.drift.recorded", "failed", "review_id", reviewID)
+		return fmt.Errorf("failed to record schema drift sample: %w", err)
+	}
+	logger.LogEvent(ctx, "storage.drift.recorded", "success", "review_id", reviewID, "issues", len(issues))
+	return nil
+}
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RatingAverage is the mean rating of Count reviews.
+type RatingAverage struct {
+	Average float64
+	Count   int
+}
+
+// RatingAverages returns the average rating of the reviews of appID in a
+// storefront written in [recentFrom, until) and in [trailingFrom,
+// recentFrom).
+func (r *ReviewRepository) RatingAverages(ctx context.Context, store, appID, country string, trailingFrom, recentFrom, until time.Time) (recent, trailing RatingAverage, err error) {
+	const query = `
+		SELECT
+			COALESCE(AVG(rating) FILTER (WHERE reviewed_at >= $5), 0), COUNT(*) FILTER (WHERE reviewed_at >= $5),
+			COALESCE(AVG(rating) FILTER (WHERE reviewed_at < $5), 0), COUNT(*) FILTER (WHERE reviewed_at < $5)
+		FROM raw_reviews
+		WHERE store = $1 AND app_id = $2 AND country = $3
+		  AND reviewed_at >= $4 AND reviewed_at < $6
+		  AND rolled_back_at IS NULL AND deleted_at IS NULL;`
+
+	err = r.db.QueryRowContext(ctx, query, store, appID, country, trailingFrom.UTC(), recentFrom.UTC(), until.UTC()).
+		Scan(&recent.Average, &recent.Count, &trailing.Average, &trailing.Count)
+	if err != nil {
+		return recent, trailing, fmt.Errorf("failed to compute rating averages: %w", err)
+	}
+	return recent, trailing, nil
+}
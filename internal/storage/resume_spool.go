@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SuspendedSaga is a saga checkpoint taken because Postgres or Kafka were
+// unavailable. ParkedAt is when it was suspended.
+type SuspendedSaga struct {
+	ParkedSaga
+	Reason string
+}
+
+// ResumeSpool keeps suspended sagas as files in a local directory, since the
+// database may be the thing that is down.
+type ResumeSpool struct {
+	dir string
+}
+
+func NewResumeSpool(dir string) (*ResumeSpool, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create resume spool %s: %w", dir, err)
+	}
+	return &ResumeSpool{dir: dir}, nil
+}
+
+// Save writes saga, replacing an earlier checkpoint of the same saga.
+func (s *ResumeSpool) Save(saga SuspendedSaga) error {
+	data, err := json.Marshal(saga)
+	if err != nil {
+		return fmt.Errorf("failed to encode suspended saga %s: %w", saga.SagaID, err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".suspended-*")
+	if err != nil {
+		return fmt.Errorf("failed to spool suspended saga %s: %w", saga.SagaID, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to spool suspended saga %s: %w", saga.SagaID, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to spool suspended saga %s: %w", saga.SagaID, err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(saga.SagaID)); err != nil {
+		return fmt.Errorf("failed to spool suspended saga %s: %w", saga.SagaID, err)
+	}
+	return nil
+}
+
+// List returns the spooled sagas. Files that cannot be decoded are skipped
+// and reported in the error alongside the readable sagas.
+func (s *ResumeSpool) List() ([]SuspendedSaga, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resume spool: %w", err)
+	}
+
+	var sagas []SuspendedSaga
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var saga SuspendedSaga
+		if err := json.Unmarshal(data, &saga); err != nil {
+			errs = append(errs, fmt.Errorf("failed to decode %s: %w", entry.Name(), err))
+			continue
+		}
+		sagas = append(sagas, saga)
+	}
+	return sagas, errors.Join(errs...)
+}
+
+// Remove deletes the checkpoint of sagaID, if any.
+func (s *ResumeSpool) Remove(sagaID string) error {
+	if err := os.Remove(s.path(sagaID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove suspended saga %s: %w", sagaID, err)
+	}
+	return nil
+}
+
+func (s *ResumeSpool) path(sagaID string) string {
+	return filepath.Join(s.dir, filepath.Base(sagaID)+".json")
+}
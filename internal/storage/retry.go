@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+// transientCodes are Postgres errors that go away when the statement is run
+// again: serialization failures, deadlocks, shutdowns during a failover and
+// connection limits.
+var transientCodes = map[pq.ErrorCode]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+	"57P01": true, // admin_shutdown
+	"57P02": true, // crash_shutdown
+	"57P03": true, // cannot_connect_now
+	"53300": true, // too_many_connections
+}
+
+// IsTransient reports whether a failed statement is worth retrying: the
+// connection broke or Postgres asked for the statement to be run again.
+func IsTransient(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return transientCodes[pqErr.Code] || pqErr.Code.Class() == "08" // connection_exception
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
+
+// IsUnavailable reports whether err means Postgres could not be reached at
+// all, as opposed to a statement that failed.
+func IsUnavailable(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "57P01", "57P02", "57P03":
+			return true
+		}
+		return pqErr.Code.Class() == "08"
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
+
+// withRetry runs fn with the configured statement timeout, retrying transient
+// failures with exponential backoff. A retried insert whose first attempt was
+// committed before the connection dropped reports a duplicate.
+func (r *ReviewRepository) withRetry(ctx context.Context, op string, fn func(ctx context.Context) error) error {
+	backoff := r.cfg.BackoffInitial
+	var err error
+	for attempt := 0; attempt <= r.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff = time.Duration(math.Min(float64(backoff*2), float64(r.cfg.BackoffMax)))
+		}
+
+		err = r.attempt(ctx, fn)
+		if err == nil || ctx.Err() != nil || !IsTransient(err) {
+			return err
+		}
+		logger.LogEvent(ctx, "storage.query.retried", "in_progress", "op", op, "attempt", attempt+1, "error", err.Error())
+	}
+	return err
+}
+
+func (r *ReviewRepository) attempt(ctx context.Context, fn func(ctx context.Context) error) error {
+	if r.cfg.StatementTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.cfg.StatementTimeout)
+		defer cancel()
+	}
+	if r.faults != nil {
+		if err := r.faults.DBError(ctx); err != nil {
+			return err
+		}
+	}
+	return fn(ctx)
+}
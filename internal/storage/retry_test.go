@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"serialization failure", &pq.Error{Code: "40001"}, true},
+		{"connection failure", &pq.Error{Code: "08006"}, true},
+		{"failover shutdown", fmt.Errorf("save: %w", &pq.Error{Code: "57P01"}), true},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"statement timeout", context.DeadlineExceeded, true},
+		{"unique violation", &pq.Error{Code: "23505"}, false},
+		{"no rows", sql.ErrNoRows, false},
+		{"other", errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		if got := IsTransient(tt.err); got != tt.want {
+			t.Errorf("%s: IsTransient = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIsUnavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"connection refused", fmt.Errorf("lock: %w", syscall.ECONNREFUSED), true},
+		{"connection failure", &pq.Error{Code: "08006"}, true},
+		{"starting up", &pq.Error{Code: "57P03"}, true},
+		{"serialization failure", &pq.Error{Code: "40001"}, false},
+		{"statement timeout", context.DeadlineExceeded, false},
+		{"other", errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		if got := IsUnavailable(tt.err); got != tt.want {
+			t.Errorf("%s: IsUnavailable = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+// RollbackRun undoes what a saga stored: the reviews it inserted and its
+// rating aggregates. Reviews are marked rolled back, which hides them from
+// search, exports and counts, or deleted when purge is set. Reviews the run
+// found already stored belong to an earlier run and are kept. It returns the
+// number of reviews rolled back.
+func (r *ReviewRepository) RollbackRun(ctx context.Context, runID string, purge bool) (int64, error) {
+	const markReviews = `
+		UPDATE raw_reviews SET rolled_back_at = NOW()
+		WHERE ingest_run_id = $1 AND rolled_back_at IS NULL;`
+	const deleteReviews = `DELETE FROM raw_reviews WHERE ingest_run_id = $1;`
+	const deleteAggregates = `DELETE FROM ingest_aggregates WHERE saga_id = $1;`
+
+	query := markReviews
+	if purge {
+		query = deleteReviews
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin rollback: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, query, runID)
+	if err != nil {
+		logger.LogEvent(ctx, "storage.run.rolled_back", "failed", "purge", purge)
+		return 0, fmt.Errorf("failed to roll back reviews of run %s: %w", runID, err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteAggregates, runID); err != nil {
+		logger.LogEvent(ctx, "storage.run.rolled_back", "failed", "purge", purge)
+		return 0, fmt.Errorf("failed to delete aggregates of run %s: %w", runID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit rollback of run %s: %w", runID, err)
+	}
+
+	rolledBack, _ := res.RowsAffected()
+	logger.LogEvent(ctx, "storage.run.rolled_back", "success", "purge", purge, "reviews", rolledBack)
+	return rolledBack, nil
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+)
+
+// SearchQuery selects reviews of an app whose title or body matches Query,
+// written in web search syntax ("quoted phrases", OR, -excluded). Language,
+// when set, limits the search to reviews in that ISO 639-1 language and
+// lets Postgres use the full-text index; otherwise each review is matched
+// with its own language's stemming. An empty Store matches every store.
+type SearchQuery struct {
+	AppID    string
+	Store    string
+	Query    string
+	Language string
+	Limit    int
+}
+
+// SearchResult is a matching review with its relevance rank.
+type SearchResult struct {
+	StoredReview
+	Language *string
+	Rank     float64
+}
+
+// SearchReviews returns up to query.Limit reviews matching query, best
+// matches first.
+func (r *ReviewRepository) SearchReviews(ctx context.Context, query SearchQuery) ([]SearchResult, error) {
+	const search = `
+		SELECT store, id, app_id, country, territory, rating, title, content, reviewed_at, response_date, response_content, language,
+			ts_rank(search_vector, q) AS rank
+		FROM raw_reviews, LATERAL %s AS q
+		WHERE app_id = $1
+		  AND ($2 = '' OR store = $2)
+		  AND ($4 = '' OR language = $4)
+		  AND search_vector @@ q
+		  AND rolled_back_at IS NULL AND deleted_at IS NULL
+		ORDER BY rank DESC, reviewed_at DESC
+		LIMIT $5;`
+
+	// A query that does not depend on the row is what lets the GIN index
+	// on search_vector be used.
+	tsquery := `websearch_to_tsquery(review_search_config(language), $3)`
+	if query.Language != "" {
+		tsquery = `websearch_to_tsquery(review_search_config($4), $3)`
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(search, tsquery), query.AppID, query.Store, query.Query, query.Language, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var result SearchResult
+		if err := rows.Scan(&result.Store, &result.ID, &result.AppID, &result.Country, &result.Territory, &result.Rating, &result.Title,
+			&result.Content, &result.ReviewedAt, &result.ResponseDate, &result.ResponseContent, &result.Language, &result.Rank); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+// StartOverride is where an operator wants the next fetch of a storefront
+// to start: at page or item Offset, as the store counts it, and only with
+// reviews written before Before when set. CreatedAt tells it apart from an
+// override set again for the same storefront.
+type StartOverride struct {
+	Store     string
+	AppID     string
+	Country   string
+	Offset    int
+	Before    *time.Time
+	CreatedAt time.Time
+}
+
+// SetStartOverride stores an override, replacing an earlier one for the same
+// storefront.
+func (r *ControlRepository) SetStartOverride(ctx context.Context, override StartOverride) error {
+	const query = `
+		INSERT INTO start_overrides (store, app_id, country, start_offset, start_before, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (store, app_id, country) DO UPDATE SET
+			start_offset = EXCLUDED.start_offset,
+			start_before = EXCLUDED.start_before,
+			created_at = EXCLUDED.created_at;`
+
+	country := strings.ToLower(override.Country)
+	if _, err := r.db.ExecContext(ctx, query, override.Store, override.AppID, country, override.Offset, override.Before); err != nil {
+		return fmt.Errorf("failed to set start override for %s in %s: %w", override.AppID, country, err)
+	}
+	logger.LogEvent(ctx, "control.start_override.set", "success", "store", override.Store, "country", country, "offset", override.Offset)
+	return nil
+}
+
+// ClearStartOverride removes the override of a storefront, if any.
+func (r *ControlRepository) ClearStartOverride(ctx context.Context, store, appID, country string) error {
+	const query = `DELETE FROM start_overrides WHERE store = $1 AND app_id = $2 AND country = $3;`
+
+	if _, err := r.db.ExecContext(ctx, query, store, appID, strings.ToLower(country)); err != nil {
+		return fmt.Errorf("failed to clear start override for %s in %s: %w", appID, country, err)
+	}
+	return nil
+}
+
+// GetStartOverride returns the override of a storefront, or nil when there
+// is none. It stays in place until CompleteStartOverride removes it.
+func (r *ControlRepository) GetStartOverride(ctx context.Context, store, appID, country string) (*StartOverride, error) {
+	const query = `
+		SELECT start_offset, start_before, created_at FROM start_overrides
+		WHERE store = $1 AND app_id = $2 AND country = $3;`
+
+	override := StartOverride{Store: store, AppID: appID, Country: strings.ToLower(country)}
+	var before sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, store, appID, override.Country).Scan(&override.Offset, &before, &override.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get start override for %s in %s: %w", appID, country, err)
+	}
+	if before.Valid {
+		override.Before = &before.Time
+	}
+	return &override, nil
+}
+
+// CompleteStartOverride removes override once the fetch it applied to has
+// finished, so it applies to one fetch only. An override set again since it
+// was read is kept for the next fetch.
+func (r *ControlRepository) CompleteStartOverride(ctx context.Context, override StartOverride) error {
+	const query = `
+		DELETE FROM start_overrides
+		WHERE store = $1 AND app_id = $2 AND country = $3 AND created_at = $4;`
+
+	if _, err := r.db.ExecContext(ctx, query, override.Store, override.AppID, override.Country, override.CreatedAt); err != nil {
+		return fmt.Errorf("failed to complete start override for %s in %s: %w", override.AppID, override.Country, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// StatsQuery selects the stored reviews of an app written in [From, To),
+// optionally for one store and storefront. Empty Store and Country match
+// all of them.
+type StatsQuery struct {
+	AppID   string
+	Store   string
+	Country string
+	From    time.Time
+	To      time.Time
+}
+
+// DailyStats summarizes the reviews of one storefront written on one UTC
+// day: how many there are, their average rating and how many the developer
+// responded to.
+type DailyStats struct {
+	Day       time.Time
+	Country   string
+	Reviews   int
+	AvgRating float64
+	Responded int
+}
+
+// DailyStats returns the per-day, per-storefront review statistics of
+// query, oldest day first.
+func (r *ReviewRepository) DailyStats(ctx context.Context, query StatsQuery) ([]DailyStats, error) {
+	const stats = `
+		SELECT (reviewed_at AT TIME ZONE 'UTC')::date AS day, country, COUNT(*), AVG(rating),
+			COUNT(*) FILTER (WHERE response_content IS NOT NULL AND response_content <> '')
+		FROM raw_reviews
+		WHERE app_id = $1
+		  AND ($2 = '' OR store = $2)
+		  AND ($3 = '' OR LOWER(country) = LOWER($3))
+		  AND reviewed_at >= $4 AND reviewed_at < $5
+		  AND rolled_back_at IS NULL AND deleted_at IS NULL
+		GROUP BY day, country
+		ORDER BY day, country;`
+
+	rows, err := r.db.QueryContext(ctx, stats, query.AppID, query.Store, query.Country, query.From, query.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily review stats: %w", err)
+	}
+	defer rows.Close()
+
+	var days []DailyStats
+	for rows.Next() {
+		var day DailyStats
+		if err := rows.Scan(&day.Day, &day.Country, &day.Reviews, &day.AvgRating, &day.Responded); err != nil {
+			return nil, fmt.Errorf("failed to scan daily review stats: %w", err)
+		}
+		days = append(days, day)
+	}
+	return days, rows.Err()
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// StorefrontHealthRepository counts consecutive failures per store and
+// storefront and puts storefronts that keep failing into a cooldown shared
+// by all replicas.
+type StorefrontHealthRepository struct {
+	db *sql.DB
+}
+
+func NewStorefrontHealthRepository(db *sql.DB) *StorefrontHealthRepository {
+	return &StorefrontHealthRepository{db: db}
+}
+
+// CooldownUntil returns when the cooldown of country in store ends, or the
+// zero time when it is not cooling down.
+func (r *StorefrontHealthRepository) CooldownUntil(ctx context.Context, store, country string) (time.Time, error) {
+	const query = `
+		SELECT cooldown_until FROM storefront_health
+		WHERE store = $1 AND country = $2 AND cooldown_until > NOW();`
+
+	var until time.Time
+	err := r.db.QueryRowContext(ctx, query, store, country).Scan(&until)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read storefront cooldown: %w", err)
+	}
+	return until, nil
+}
+
+// RecordFailure counts a failure of country in store. Once threshold
+// consecutive failures are reached the counter resets and the storefront
+// cools down for cooldown; the returned time is when it ends, or zero when
+// the threshold was not reached.
+func (r *StorefrontHealthRepository) RecordFailure(ctx context.Context, store, country string, threshold int, cooldown time.Duration) (time.Time, error) {
+	const query = `
+		INSERT INTO storefront_health (store, country, consecutive_failures, last_failure_at)
+		VALUES ($1, $2, 1, NOW())
+		ON CONFLICT (store, country) DO UPDATE SET
+			consecutive_failures = storefront_health.consecutive_failures + 1,
+			last_failure_at = NOW()
+		RETURNING consecutive_failures;`
+
+	const startCooldown = `
+		UPDATE storefront_health
+		SET consecutive_failures = 0, cooldown_until = NOW() + $3 * INTERVAL '1 second'
+		WHERE store = $1 AND country = $2
+		RETURNING cooldown_until;`
+
+	var failures int
+	if err := r.db.QueryRowContext(ctx, query, store, country).Scan(&failures); err != nil {
+		return time.Time{}, fmt.Errorf("failed to record storefront failure: %w", err)
+	}
+	if failures < threshold {
+		return time.Time{}, nil
+	}
+
+	var until time.Time
+	if err := r.db.QueryRowContext(ctx, startCooldown, store, country, cooldown.Seconds()).Scan(&until); err != nil {
+		return time.Time{}, fmt.Errorf("failed to start storefront cooldown: %w", err)
+	}
+	return until, nil
+}
+
+// RecordSuccess resets the consecutive failures of country in store.
+func (r *StorefrontHealthRepository) RecordSuccess(ctx context.Context, store, country string) error {
+	const query = `
+		UPDATE storefront_health SET consecutive_failures = 0
+		WHERE store = $1 AND country = $2 AND consecutive_failures > 0;`
+
+	if _, err := r.db.ExecContext(ctx, query, store, country); err != nil {
+		return fmt.Errorf("failed to reset storefront failures: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// StorefrontRepository caches the storefronts apps were discovered in.
+type StorefrontRepository struct {
+	db *sql.DB
+}
+
+func NewStorefrontRepository(db *sql.DB) *StorefrontRepository {
+	return &StorefrontRepository{db: db}
+}
+
+// GetStorefronts returns the storefronts discovered for appID within maxAge,
+// and whether there were any.
+func (r *StorefrontRepository) GetStorefronts(ctx context.Context, appID string, maxAge time.Duration) ([]string, bool, error) {
+	const query = `
+		SELECT storefronts FROM app_storefronts
+		WHERE app_id = $1 AND discovered_at > $2;`
+
+	var storefronts []string
+	err := r.db.QueryRowContext(ctx, query, appID, time.Now().Add(-maxAge)).Scan(pq.Array(&storefronts))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read storefronts: %w", err)
+	}
+	return storefronts, true, nil
+}
+
+func (r *StorefrontRepository) SaveStorefronts(ctx context.Context, appID string, storefronts []string) error {
+	const query = `
+		INSERT INTO app_storefronts (app_id, storefronts, discovered_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (app_id) DO UPDATE SET storefronts = EXCLUDED.storefronts, discovered_at = EXCLUDED.discovered_at;`
+
+	if _, err := r.db.ExecContext(ctx, query, appID, pq.Array(storefronts)); err != nil {
+		return fmt.Errorf("failed to save storefronts: %w", err)
+	}
+	return nil
+}
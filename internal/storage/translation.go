@@ -0,0 +1,17 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+)
+
+// SaveTranslation stores the translation of a review's body in
+// content_translated.
+func (r *ReviewRepository) SaveTranslation(ctx context.Context, key ReviewKey, translated string) error {
+	const query = `UPDATE raw_reviews SET content_translated = $3 WHERE store = $1 AND id = $2;`
+
+	if _, err := r.db.ExecContext(ctx, query, key.Store, key.ID, translated); err != nil {
+		return fmt.Errorf("failed to save translation of review %s: %w", key.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// UsageRepository rolls up store usage per tenant and day for billing and
+// throttling in multi-tenant deployments.
+type UsageRepository struct {
+	db *sql.DB
+}
+
+func NewUsageRepository(db *sql.DB) *UsageRepository {
+	return &UsageRepository{db: db}
+}
+
+// UsageRecord is store usage to add to a tenant's rollup of Day.
+type UsageRecord struct {
+	Tenant         string
+	Store          string
+	Day            time.Time
+	Requests       int64
+	RateLimited    int64
+	Bytes          int64
+	BackoffSeconds float64
+}
+
+// AddUsage adds usage to the tenant's rollup of its day, in UTC.
+func (r *UsageRepository) AddUsage(ctx context.Context, usage UsageRecord) error {
+	const query = `
+		INSERT INTO usage_accounting (tenant, day, store, requests, rate_limited, bytes, backoff_seconds, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		ON CONFLICT (tenant, day, store) DO UPDATE SET
+			requests = usage_accounting.requests + EXCLUDED.requests,
+			rate_limited = usage_accounting.rate_limited + EXCLUDED.rate_limited,
+			bytes = usage_accounting.bytes + EXCLUDED.bytes,
+			backoff_seconds = usage_accounting.backoff_seconds + EXCLUDED.backoff_seconds,
+			updated_at = EXCLUDED.updated_at;`
+
+	day := usage.Day.UTC().Format(time.DateOnly)
+	_, err := r.db.ExecContext(ctx, query, usage.Tenant, day, usage.Store, usage.Requests, usage.RateLimited, usage.Bytes, usage.BackoffSeconds)
+	if err != nil {
+		return fmt.Errorf("failed to record usage of tenant %s: %w", usage.Tenant, err)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+)
+
+// CountByCountry returns how many reviews are stored for appID in store,
+// keyed by the storefront they were fetched from.
+func (r *ReviewRepository) CountByCountry(ctx context.Context, store, appID string) (map[string]int, error) {
+	const query = `
+		SELECT country, COUNT(*) FROM raw_reviews
+		WHERE store = $1 AND app_id = $2 AND rolled_back_at IS NULL AND deleted_at IS NULL
+		GROUP BY country;`
+
+	rows, err := r.db.QueryContext(ctx, query, store, appID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reviews by country: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var country string
+		var count int
+		if err := rows.Scan(&country, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan review count: %w", err)
+		}
+		counts[country] = count
+	}
+	return counts, rows.Err()
+}
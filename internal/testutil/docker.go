@@ -0,0 +1,57 @@
+package testutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+)
+
+// newPool connects to the Docker daemon, skipping the test when none is
+// reachable.
+func newPool(t testing.TB) *dockertest.Pool {
+	t.Helper()
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		t.Skipf("docker is not available: %v", err)
+	}
+	if err := pool.Client.Ping(); err != nil {
+		t.Skipf("docker is not available: %v", err)
+	}
+	return pool
+}
+
+// runContainer starts a container with its ports published on random
+// loopback ports and removes it when the test ends.
+func runContainer(t testing.TB, pool *dockertest.Pool, opts *dockertest.RunOptions) *dockertest.Resource {
+	t.Helper()
+	resource, err := pool.RunWithOptions(opts, func(hc *docker.HostConfig) {
+		hc.AutoRemove = true
+		hc.RestartPolicy = docker.RestartPolicy{Name: "no"}
+		hc.PortBindings = make(map[docker.Port][]docker.PortBinding, len(opts.ExposedPorts))
+		for _, port := range opts.ExposedPorts {
+			hc.PortBindings[docker.Port(port)] = []docker.PortBinding{{HostIP: "127.0.0.1"}}
+		}
+	})
+	if err != nil {
+		t.Fatalf("failed to start %s:%s: %v", opts.Repository, opts.Tag, err)
+	}
+	t.Cleanup(func() { pool.Purge(resource) })
+	return resource
+}
+
+// waitFor retries check with backoff until it succeeds or timeout passes.
+func waitFor(t testing.TB, pool *dockertest.Pool, what string, timeout time.Duration, check func(ctx context.Context) error) {
+	t.Helper()
+	pool.MaxWait = timeout
+	err := pool.Retry(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return check(ctx)
+	})
+	if err != nil {
+		t.Fatalf("%s not ready after %s: %v", what, timeout, err)
+	}
+}
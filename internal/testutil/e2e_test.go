@@ -0,0 +1,68 @@
+//go:build integration
+
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/producer"
+	"github.com/quiby-ai/review-ingestor/internal/service"
+)
+
+const sagaTimeout = 2 * time.Minute
+
+func extractRequest(appID string, countries ...string) service.Request {
+	var req service.Request
+	req.AppID = appID
+	req.AppName = "Mock App " + appID
+	req.Countries = countries
+	req.DateFrom = "2025-01-01"
+	req.DateTo = "2025-06-30"
+	return req
+}
+
+func TestExtractRequestEndToEnd(t *testing.T) {
+	h := New(t)
+	h.StartIngestor(t)
+	completed := h.Consume(t, CompletedTopic)
+
+	h.Publish(t, RequestTopic, "saga-e2e-1", extractRequest("1234", "us", "gb"))
+
+	var event producer.ExtractCompleted
+	envelope := completed.Next(t, "saga-e2e-1", sagaTimeout, &event)
+
+	want := 2 * ReviewsPerStorefront
+	if event.New != want || event.Count != want {
+		t.Errorf("completion reported %d new of %d reviews, want %d", event.New, event.Count, want)
+	}
+	if len(event.CountriesCovered) != 2 {
+		t.Errorf("CountriesCovered = %v, want us and gb", event.CountriesCovered)
+	}
+	if got := h.CountReviews(t, "1234"); got != want {
+		t.Errorf("stored %d reviews, want %d", got, want)
+	}
+	if envelope.MessageID != producer.MessageID("saga-e2e-1", envelope.Type, 0) {
+		t.Errorf("completion message ID %s is not the deterministic one", envelope.MessageID)
+	}
+}
+
+func TestRepeatedRequestStoresNothingNew(t *testing.T) {
+	h := New(t)
+	h.StartIngestor(t)
+	completed := h.Consume(t, CompletedTopic)
+
+	h.Publish(t, RequestTopic, "saga-e2e-first", extractRequest("5678", "us"))
+	completed.Next(t, "saga-e2e-first", sagaTimeout, nil)
+
+	h.Publish(t, RequestTopic, "saga-e2e-second", extractRequest("5678", "us"))
+	var event producer.ExtractCompleted
+	completed.Next(t, "saga-e2e-second", sagaTimeout, &event)
+
+	if event.New != 0 || event.Duplicates != ReviewsPerStorefront {
+		t.Errorf("second saga reported %d new and %d duplicates, want 0 and %d", event.New, event.Duplicates, ReviewsPerStorefront)
+	}
+	if got := h.CountReviews(t, "5678"); got != ReviewsPerStorefront {
+		t.Errorf("stored %d reviews, want %d", got, ReviewsPerStorefront)
+	}
+}
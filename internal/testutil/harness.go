@@ -0,0 +1,342 @@
+// Package testutil runs the ingestor end to end for integration tests: a
+// throwaway Postgres and Kafka in Docker, the mock App Store in process and
+// the ingestor binary built from ./cmd, configured through its environment.
+// Tests using it skip when Docker is not available.
+package testutil
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/quiby-ai/common/pkg/events"
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/mockstore"
+	"github.com/segmentio/kafka-go"
+
+	_ "github.com/lib/pq"
+)
+
+const (
+	postgresRepository = "postgres"
+	postgresTag        = "16-alpine"
+	kafkaRepository    = "apache/kafka"
+	kafkaTag           = "3.8.0"
+
+	// ReviewsPerStorefront is how many reviews every mock storefront holds.
+	ReviewsPerStorefront = 30
+)
+
+// Topics the harness creates and points the ingestor at.
+const (
+	RequestTopic   = "pipeline.extract_reviews.request"
+	PriorityTopic  = "pipeline.extract_reviews.request.priority"
+	CompletedTopic = "pipeline.extract_reviews.completed"
+	CancelledTopic = "pipeline.extract_reviews.cancelled"
+	ProgressTopic  = "pipeline.extract_reviews.progress"
+	CancelTopic    = "pipeline.extract_reviews.cancel"
+	ResumeTopic    = "pipeline.extract_reviews.resume"
+)
+
+// Harness is one isolated environment: its own database, broker and store.
+type Harness struct {
+	DSN      string
+	Brokers  []string
+	StoreURL string
+	DB       *sql.DB
+}
+
+// New starts Postgres, Kafka and the mock store and creates the topics. They
+// are torn down when the test ends.
+func New(t testing.TB) *Harness {
+	t.Helper()
+	pool := newPool(t)
+
+	h := &Harness{}
+	h.startPostgres(t, pool)
+	h.startKafka(t, pool)
+	h.startMockStore(t)
+	return h
+}
+
+func (h *Harness) startPostgres(t testing.TB, pool *dockertest.Pool) {
+	resource := runContainer(t, pool, &dockertest.RunOptions{
+		Repository:   postgresRepository,
+		Tag:          postgresTag,
+		Env:          []string{"POSTGRES_PASSWORD=ingestor", "POSTGRES_DB=ingestor"},
+		ExposedPorts: []string{"5432/tcp"},
+	})
+
+	h.DSN = fmt.Sprintf("postgres://postgres:ingestor@%s/ingestor?sslmode=disable", resource.GetHostPort("5432/tcp"))
+	db, err := sql.Open("postgres", h.DSN)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	waitFor(t, pool, "postgres", time.Minute, db.PingContext)
+	h.DB = db
+}
+
+// kafkaAdvertisedFile is where the Kafka container waits for its advertised
+// listener. The broker has to advertise the published host port, which is
+// only known once the container runs.
+const kafkaAdvertisedFile = "/tmp/advertised-listeners"
+
+func (h *Harness) startKafka(t testing.TB, pool *dockertest.Pool) {
+	resource := runContainer(t, pool, &dockertest.RunOptions{
+		Repository: kafkaRepository,
+		Tag:        kafkaTag,
+		Entrypoint: []string{"sh", "-c", fmt.Sprintf(
+			"while [ ! -f %[1]s ]; do sleep 0.1; done; KAFKA_ADVERTISED_LISTENERS=$(cat %[1]s) exec /etc/kafka/docker/run",
+			kafkaAdvertisedFile)},
+		Env: []string{
+			"KAFKA_NODE_ID=1",
+			"KAFKA_PROCESS_ROLES=broker,controller",
+			"KAFKA_LISTENERS=PLAINTEXT://:9092,CONTROLLER://:9093",
+			"KAFKA_CONTROLLER_LISTENER_NAMES=CONTROLLER",
+			"KAFKA_LISTENER_SECURITY_PROTOCOL_MAP=CONTROLLER:PLAINTEXT,PLAINTEXT:PLAINTEXT",
+			"KAFKA_CONTROLLER_QUORUM_VOTERS=1@localhost:9093",
+			"KAFKA_OFFSETS_TOPIC_REPLICATION_FACTOR=1",
+			"KAFKA_TRANSACTION_STATE_LOG_REPLICATION_FACTOR=1",
+			"KAFKA_TRANSACTION_STATE_LOG_MIN_ISR=1",
+			"KAFKA_GROUP_INITIAL_REBALANCE_DELAY_MS=0",
+		},
+		ExposedPorts: []string{"9092/tcp"},
+	})
+
+	h.Brokers = []string{resource.GetHostPort("9092/tcp")}
+	write := fmt.Sprintf("echo PLAINTEXT://%[1]s > %[2]s.tmp && mv %[2]s.tmp %[2]s", h.Brokers[0], kafkaAdvertisedFile)
+	if code, err := resource.Exec([]string{"sh", "-c", write}, dockertest.ExecOptions{}); err != nil || code != 0 {
+		t.Fatalf("failed to set kafka advertised listener (exit %d): %v", code, err)
+	}
+
+	waitFor(t, pool, "kafka", 2*time.Minute, func(ctx context.Context) error {
+		conn, err := kafka.DialContext(ctx, "tcp", h.Brokers[0])
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		_, err = conn.Brokers()
+		return err
+	})
+	h.CreateTopics(t, RequestTopic, PriorityTopic, CompletedTopic, CancelledTopic, ProgressTopic, CancelTopic, ResumeTopic)
+}
+
+func (h *Harness) startMockStore(t testing.TB) {
+	store := mockstore.NewServer(config.MockStoreConfig{
+		ReviewsPerStorefront: ReviewsPerStorefront,
+		Token:                "mock-token",
+	})
+	srv := httptest.NewServer(store.Handler())
+	t.Cleanup(srv.Close)
+	h.StoreURL = srv.URL
+}
+
+// CreateTopics creates single-partition topics on the broker.
+func (h *Harness) CreateTopics(t testing.TB, topics ...string) {
+	t.Helper()
+	conn, err := kafka.Dial("tcp", h.Brokers[0])
+	if err != nil {
+		t.Fatalf("failed to dial kafka: %v", err)
+	}
+	defer conn.Close()
+
+	controller, err := conn.Controller()
+	if err != nil {
+		t.Fatalf("failed to find kafka controller: %v", err)
+	}
+	controllerConn, err := kafka.Dial("tcp", net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port)))
+	if err != nil {
+		t.Fatalf("failed to dial kafka controller: %v", err)
+	}
+	defer controllerConn.Close()
+
+	configs := make([]kafka.TopicConfig, len(topics))
+	for i, topic := range topics {
+		configs[i] = kafka.TopicConfig{Topic: topic, NumPartitions: 1, ReplicationFactor: 1}
+	}
+	if err := controllerConn.CreateTopics(configs...); err != nil {
+		t.Fatalf("failed to create topics: %v", err)
+	}
+}
+
+// Env is the environment that points the ingestor at the harness.
+func (h *Harness) Env() []string {
+	return []string{
+		"PG_DSN=" + h.DSN,
+		"KAFKA_BROKERS=" + h.Brokers[0],
+		"KAFKA_REQUEST_TOPIC=" + RequestTopic,
+		"KAFKA_PRIORITY_TOPIC=" + PriorityTopic,
+		"KAFKA_COMPLETED_TOPIC=" + CompletedTopic,
+		"KAFKA_CANCELLED_TOPIC=" + CancelledTopic,
+		"KAFKA_PROGRESS_TOPIC=" + ProgressTopic,
+		"KAFKA_CANCEL_TOPIC=" + CancelTopic,
+		"KAFKA_RESUME_TOPIC=" + ResumeTopic,
+		"APP_STORE_API_HOST=" + h.StoreURL,
+		"APP_STORE_LANDING_HOST=" + h.StoreURL,
+		"APP_STORE_LOOKUP_URL=" + h.StoreURL + "/lookup",
+		"APP_STORE_PAGE_DELAY=0s",
+		"RATELIMIT_ENABLED=false",
+		"ADMIN_ENABLED=false",
+		"GRPC_ENABLED=false",
+	}
+}
+
+var (
+	buildOnce sync.Once
+	binary    string
+	buildErr  error
+)
+
+// moduleRoot is the repository root, where the ingestor finds config.toml.
+func moduleRoot() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..")
+}
+
+// buildIngestor compiles ./cmd once per test binary.
+func buildIngestor(t testing.TB) string {
+	t.Helper()
+	buildOnce.Do(func() {
+		dir, err := os.MkdirTemp("", "ingestor-e2e-")
+		if err != nil {
+			buildErr = err
+			return
+		}
+		binary = filepath.Join(dir, "ingestor")
+		cmd := exec.Command("go", "build", "-o", binary, "./cmd")
+		cmd.Dir = moduleRoot()
+		if out, err := cmd.CombinedOutput(); err != nil {
+			buildErr = fmt.Errorf("%w: %s", err, out)
+		}
+	})
+	if buildErr != nil {
+		t.Fatalf("failed to build ingestor: %v", buildErr)
+	}
+	return binary
+}
+
+// StartIngestor runs the ingestor against the harness, with env overriding
+// the harness settings. Its output is written to the test log once the test
+// ends, and it is stopped with SIGTERM like a deployment would stop it.
+func (h *Harness) StartIngestor(t testing.TB, env ...string) {
+	t.Helper()
+	logPath := filepath.Join(t.TempDir(), "ingestor.log")
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		t.Fatalf("failed to create ingestor log: %v", err)
+	}
+
+	cmd := exec.Command(buildIngestor(t))
+	cmd.Dir = moduleRoot()
+	cmd.Env = append(append(os.Environ(), h.Env()...), env...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		logFile.Close()
+		t.Fatalf("failed to start ingestor: %v", err)
+	}
+
+	t.Cleanup(func() {
+		cmd.Process.Signal(syscall.SIGTERM)
+		done := make(chan error, 1)
+		go func() { done <- cmd.Wait() }()
+		select {
+		case <-done:
+		case <-time.After(30 * time.Second):
+			cmd.Process.Kill()
+			<-done
+		}
+		logFile.Close()
+		if out, err := os.ReadFile(logPath); err == nil {
+			t.Logf("ingestor output:\n%s", out)
+		}
+	})
+}
+
+// Publish sends payload to topic wrapped in an event envelope for sagaID.
+func (h *Harness) Publish(t testing.TB, topic, sagaID string, payload any) {
+	t.Helper()
+	envelope := events.BuildEnvelope(payload, topic, sagaID)
+	value, err := json.Marshal(envelope)
+	if err != nil {
+		t.Fatalf("failed to encode envelope: %v", err)
+	}
+
+	writer := &kafka.Writer{Addr: kafka.TCP(h.Brokers...), Topic: topic, RequiredAcks: kafka.RequireAll}
+	defer writer.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := writer.WriteMessages(ctx, kafka.Message{Key: []byte(sagaID), Value: value}); err != nil {
+		t.Fatalf("failed to publish to %s: %v", topic, err)
+	}
+}
+
+// Consumer reads a topic from its first offset.
+type Consumer struct {
+	reader *kafka.Reader
+}
+
+// Consume starts reading topic from the beginning, independent of the
+// ingestor's consumer group.
+func (h *Harness) Consume(t testing.TB, topic string) *Consumer {
+	t.Helper()
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     h.Brokers,
+		Topic:       topic,
+		StartOffset: kafka.FirstOffset,
+		MaxWait:     500 * time.Millisecond,
+	})
+	t.Cleanup(func() { reader.Close() })
+	return &Consumer{reader: reader}
+}
+
+// Next waits up to timeout for the next event of sagaID, skipping events of
+// other sagas, and decodes its payload into out.
+func (c *Consumer) Next(t testing.TB, sagaID string, timeout time.Duration, out any) events.Envelope[json.RawMessage] {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	for {
+		msg, err := c.reader.ReadMessage(ctx)
+		if err != nil {
+			t.Fatalf("no event for saga %s on %s: %v", sagaID, c.reader.Config().Topic, err)
+		}
+		var envelope events.Envelope[json.RawMessage]
+		if err := json.Unmarshal(msg.Value, &envelope); err != nil {
+			t.Fatalf("failed to decode envelope: %v", err)
+		}
+		if envelope.SagaID != sagaID {
+			continue
+		}
+		if out != nil {
+			if err := json.Unmarshal(envelope.Payload, out); err != nil {
+				t.Fatalf("failed to decode payload: %v", err)
+			}
+		}
+		return envelope
+	}
+}
+
+// CountReviews returns how many reviews of appID are stored.
+func (h *Harness) CountReviews(t testing.TB, appID string) int {
+	t.Helper()
+	var count int
+	if err := h.DB.QueryRow(`SELECT COUNT(*) FROM raw_reviews WHERE app_id = $1`, appID).Scan(&count); err != nil {
+		t.Fatalf("failed to count reviews: %v", err)
+	}
+	return count
+}
@@ -0,0 +1,114 @@
+package translate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+// Client translates review text through a LibreTranslate compatible
+// endpoint. Requests are spaced evenly so the instance stays within the
+// configured requests per minute.
+type Client struct {
+	client   *http.Client
+	cfg      config.TranslateConfig
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func NewClient(cfg config.TranslateConfig) *Client {
+	return &Client{
+		client:   &http.Client{Timeout: cfg.Timeout},
+		cfg:      cfg,
+		interval: time.Minute / time.Duration(cfg.RequestsPerMinute),
+	}
+}
+
+// Target is the language reviews are translated into.
+func (c *Client) Target() string {
+	return c.cfg.TargetLanguage
+}
+
+type request struct {
+	Q      string `json:"q"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Format string `json:"format"`
+	APIKey string `json:"api_key,omitempty"`
+}
+
+type response struct {
+	TranslatedText string `json:"translatedText"`
+}
+
+// Translate returns text translated from source, an ISO 639-1 code, into
+// the target language. It waits for its turn under the rate limit first.
+func (c *Client) Translate(ctx context.Context, text, source string) (string, error) {
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	body, err := json.Marshal(request{Q: text, Source: source, Target: c.cfg.TargetLanguage, Format: "text", APIKey: c.cfg.APIKey})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode translation request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to build translation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	timer := logger.StartTimer()
+	resp, err := c.client.Do(req)
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "translate.request", "failed", timer(), "source", source, "error", err.Error())
+		return "", fmt.Errorf("translation request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		logger.LogEventWithLatency(ctx, "translate.request", "failed", timer(), "source", source, "status", resp.StatusCode)
+		return "", fmt.Errorf("translation service returned status %d", resp.StatusCode)
+	}
+	var decoded response
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		logger.LogEventWithLatency(ctx, "translate.request", "failed", timer(), "source", source, "error", err.Error())
+		return "", fmt.Errorf("failed to decode translation response: %w", err)
+	}
+	logger.LogEventWithLatency(ctx, "translate.request", "success", timer(), "source", source)
+	return decoded.TranslatedText, nil
+}
+
+// wait blocks until the next request slot, or until ctx is done.
+func (c *Client) wait(ctx context.Context) error {
+	c.mu.Lock()
+	now := time.Now()
+	slot := c.next
+	if slot.Before(now) {
+		slot = now
+	}
+	c.next = slot.Add(c.interval)
+	c.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
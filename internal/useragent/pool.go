@@ -0,0 +1,41 @@
+// Package useragent keeps the pool of browser user agents requests rotate
+// through, optionally refreshed from a remote list.
+package useragent
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// Pool holds the current user agents. It starts with the baked-in list and
+// is replaced wholesale on every successful refresh.
+type Pool struct {
+	mu     sync.RWMutex
+	agents []string
+}
+
+func NewPool(agents []string) *Pool {
+	return &Pool{agents: agents}
+}
+
+// Agents returns the current user agents. The slice must not be modified.
+func (p *Pool) Agents() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.agents
+}
+
+// Random returns a user agent at random, or "" when the pool is empty.
+func (p *Pool) Random() string {
+	agents := p.Agents()
+	if len(agents) == 0 {
+		return ""
+	}
+	return agents[rand.Intn(len(agents))]
+}
+
+func (p *Pool) set(agents []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.agents = agents
+}
@@ -0,0 +1,155 @@
+package useragent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+)
+
+// maxUserAgentLength bounds a single entry; longer lines are not user agents.
+const maxUserAgentLength = 512
+
+// maxSourceBytes caps how much of a source is read.
+const maxSourceBytes = 1 << 20
+
+// Refresher periodically reloads a Pool from http.user_agents_source, a URL
+// or a file path holding a JSON array or one user agent per line. Failed or
+// empty loads keep the current list, which is the baked-in one until the
+// first successful refresh.
+type Refresher struct {
+	pool   *Pool
+	cfg    config.HTTPConfig
+	client *http.Client
+}
+
+func NewRefresher(pool *Pool, cfg config.HTTPConfig) *Refresher {
+	return &Refresher{pool: pool, cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}}
+}
+
+// Run refreshes the pool right away and then every UserAgentsRefresh until
+// ctx is cancelled.
+func (r *Refresher) Run(ctx context.Context) error {
+	r.Refresh(ctx)
+	if r.cfg.UserAgentsRefresh <= 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(r.cfg.UserAgentsRefresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.Refresh(ctx)
+		}
+	}
+}
+
+// Refresh loads the source once and swaps the pool when it yields at least
+// one valid user agent.
+func (r *Refresher) Refresh(ctx context.Context) {
+	timer := logger.StartTimer()
+	body, err := r.load(ctx)
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "useragent.pool.refreshed", "failed", timer(), "error", err.Error(), "agents", len(r.pool.Agents()))
+		return
+	}
+
+	agents, rejected, err := parseUserAgents(body)
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "useragent.pool.refreshed", "failed", timer(), "error", err.Error(), "agents", len(r.pool.Agents()))
+		return
+	}
+	if len(agents) == 0 {
+		logger.LogEventWithLatency(ctx, "useragent.pool.refreshed", "failed", timer(), "error", "no valid user agents", "rejected", rejected, "agents", len(r.pool.Agents()))
+		return
+	}
+
+	r.pool.set(agents)
+	logger.LogEventWithLatency(ctx, "useragent.pool.refreshed", "success", timer(), "agents", len(agents), "rejected", rejected)
+}
+
+func (r *Refresher) load(ctx context.Context) ([]byte, error) {
+	source := r.cfg.UserAgentsSource
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open user agent source: %w", err)
+		}
+		defer f.Close()
+		return io.ReadAll(io.LimitReader(f, maxSourceBytes))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build user agent source request: %w", err)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user agent source: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("user agent source returned status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxSourceBytes))
+}
+
+// parseUserAgents reads a JSON array of strings or one user agent per line,
+// skipping blank lines and # comments. Entries that do not look like browser
+// user agents are dropped and counted as rejected.
+func parseUserAgents(body []byte) ([]string, int, error) {
+	var entries []string
+	trimmed := strings.TrimSpace(string(body))
+	if strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal([]byte(trimmed), &entries); err != nil {
+			return nil, 0, fmt.Errorf("failed to decode user agent list: %w", err)
+		}
+	} else {
+		for _, line := range strings.Split(trimmed, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			entries = append(entries, line)
+		}
+	}
+
+	agents := make([]string, 0, len(entries))
+	seen := make(map[string]bool, len(entries))
+	rejected := 0
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if !validUserAgent(entry) {
+			rejected++
+			continue
+		}
+		if !seen[entry] {
+			seen[entry] = true
+			agents = append(agents, entry)
+		}
+	}
+	return agents, rejected, nil
+}
+
+func validUserAgent(agent string) bool {
+	if !strings.HasPrefix(agent, "Mozilla/") || len(agent) > maxUserAgentLength {
+		return false
+	}
+	for _, r := range agent {
+		if r > unicode.MaxASCII || unicode.IsControl(r) {
+			return false
+		}
+	}
+	return true
+}
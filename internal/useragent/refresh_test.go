@@ -0,0 +1,35 @@
+package useragent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseUserAgentsLines(t *testing.T) {
+	body := []byte("# rotated weekly\nMozilla/5.0 (Windows NT 10.0) Chrome/133.0\n\ncurl/8.0\nMozilla/5.0 (Windows NT 10.0) Chrome/133.0\nMozilla/5.0 (Macintosh) Safari/605.1.15\n")
+	agents, rejected, err := parseUserAgents(body)
+	if err != nil {
+		t.Fatalf("parseUserAgents() error = %v", err)
+	}
+	want := []string{"Mozilla/5.0 (Windows NT 10.0) Chrome/133.0", "Mozilla/5.0 (Macintosh) Safari/605.1.15"}
+	if !reflect.DeepEqual(agents, want) {
+		t.Errorf("agents = %q, want %q", agents, want)
+	}
+	if rejected != 1 {
+		t.Errorf("rejected = %d, want 1", rejected)
+	}
+}
+
+func TestParseUserAgentsJSON(t *testing.T) {
+	agents, rejected, err := parseUserAgents([]byte(`["Mozilla/5.0 (X11; Linux x86_64) Firefox/135.0", "Mozilla/5.0\u0000"]`))
+	if err != nil {
+		t.Fatalf("parseUserAgents() error = %v", err)
+	}
+	if len(agents) != 1 || rejected != 1 {
+		t.Errorf("agents = %q, rejected = %d; want one of each", agents, rejected)
+	}
+
+	if _, _, err := parseUserAgents([]byte(`["Mozilla/5.0"`)); err == nil {
+		t.Error("parseUserAgents() of malformed JSON succeeded")
+	}
+}
@@ -0,0 +1,61 @@
+package watchlist
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/quiby-ai/review-ingestor/config"
+)
+
+// Watchlist matches review text against case-insensitive patterns: the
+// global ones for every app plus those configured for the app.
+type Watchlist struct {
+	global []*regexp.Regexp
+	apps   map[string][]*regexp.Regexp
+}
+
+// New compiles the configured patterns. It fails on the first invalid one.
+func New(cfg config.WatchlistConfig) (*Watchlist, error) {
+	global, err := compile(cfg.Patterns)
+	if err != nil {
+		return nil, err
+	}
+	w := &Watchlist{global: global, apps: make(map[string][]*regexp.Regexp, len(cfg.Apps))}
+	for appID, patterns := range cfg.Apps {
+		compiled, err := compile(patterns)
+		if err != nil {
+			return nil, fmt.Errorf("watchlist of app %s: %w", appID, err)
+		}
+		w.apps[appID] = compiled
+	}
+	return w, nil
+}
+
+func compile(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid watchlist pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// Match returns the patterns that match any of texts for appID, each once,
+// global patterns first.
+func (w *Watchlist) Match(appID string, texts ...string) []string {
+	var matches []string
+	for _, patterns := range [][]*regexp.Regexp{w.global, w.apps[appID]} {
+		for _, re := range patterns {
+			for _, text := range texts {
+				if re.MatchString(text) {
+					matches = append(matches, re.String()[len("(?i)"):])
+					break
+				}
+			}
+		}
+	}
+	return matches
+}
@@ -0,0 +1,34 @@
+package watchlist
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/quiby-ai/review-ingestor/config"
+)
+
+func TestMatch(t *testing.T) {
+	w, err := New(config.WatchlistConfig{
+		Patterns: []string{"crash", `refund(ed)?`},
+		Apps:     map[string][]string{"42": {"log ?in loop"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := w.Match("42", "Keeps CRASHING", "stuck in a login loop"); !slices.Equal(got, []string{"crash", "log ?in loop"}) {
+		t.Errorf("Match = %v", got)
+	}
+	if got := w.Match("7", "stuck in a login loop"); got != nil {
+		t.Errorf("app patterns leaked to another app: %v", got)
+	}
+	if got := w.Match("7", "I want a refund", "refunded, crash"); !slices.Equal(got, []string{"crash", `refund(ed)?`}) {
+		t.Errorf("Match = %v", got)
+	}
+}
+
+func TestNewRejectsInvalidPattern(t *testing.T) {
+	if _, err := New(config.WatchlistConfig{Patterns: []string{"("}}); err == nil {
+		t.Error("invalid pattern accepted")
+	}
+}
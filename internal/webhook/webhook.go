@@ -0,0 +1,121 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/producer"
+)
+
+const (
+	EventCompleted = "extract.completed"
+	EventFailed    = "extract.failed"
+
+	signatureHeader = "X-Ingestor-Signature"
+	timestampHeader = "X-Ingestor-Timestamp"
+)
+
+// Payload is the JSON body posted to the webhook.
+type Payload struct {
+	Event      string                     `json:"event"`
+	SagaID     string                     `json:"saga_id"`
+	AppID      string                     `json:"app_id"`
+	Store      string                     `json:"store"`
+	Error      string                     `json:"error,omitempty"`
+	Completed  *producer.ExtractCompleted `json:"completed,omitempty"`
+	OccurredAt time.Time                  `json:"occurred_at"`
+}
+
+// Notifier posts saga outcomes to a webhook. Bodies are signed with
+// HMAC-SHA256 over "<timestamp>.<body>" using the configured secret, sent as
+// hex in the X-Ingestor-Signature header.
+type Notifier struct {
+	client *http.Client
+	cfg    config.WebhookConfig
+}
+
+func NewNotifier(cfg config.WebhookConfig) *Notifier {
+	return &Notifier{client: &http.Client{Timeout: cfg.Timeout}, cfg: cfg}
+}
+
+// Notify posts payload, retrying with exponential backoff on transport errors
+// and non-2xx responses other than 4xx client errors.
+func (n *Notifier) Notify(ctx context.Context, payload Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	backoff := n.cfg.BackoffInitial
+	var lastErr error
+	for attempt := 0; attempt <= n.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff = time.Duration(math.Min(float64(backoff*2), float64(n.cfg.BackoffMax)))
+		}
+
+		timer := logger.StartTimer()
+		retry, err := n.post(ctx, body)
+		if err == nil {
+			logger.LogEventWithLatency(ctx, "webhook.delivered", "success", timer(), "event", payload.Event, "attempt", attempt+1)
+			return nil
+		}
+		lastErr = err
+		logger.LogEventWithLatency(ctx, "webhook.delivered", "failed", timer(), "event", payload.Event, "attempt", attempt+1, "error", err.Error())
+		if !retry {
+			break
+		}
+	}
+	return fmt.Errorf("failed to deliver webhook: %w", lastErr)
+}
+
+// post sends body once and reports whether a failure is worth retrying.
+func (n *Notifier) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(timestampHeader, timestamp)
+	if n.cfg.Secret != "" {
+		req.Header.Set(signatureHeader, Sign(n.cfg.Secret, timestamp, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<body>" keyed by secret.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
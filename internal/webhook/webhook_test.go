@@ -0,0 +1,11 @@
+package webhook
+
+import "testing"
+
+func TestSign(t *testing.T) {
+	got := Sign("secret", "1700000000", []byte(`{"event":"extract.completed"}`))
+	want := "1728999de92561c212e2bc5411f9ea24363b8e0f71ce4b6a58b86e4f8228094a"
+	if got != want {
+		t.Errorf("Sign() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,218 @@
+package webreviews
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/config"
+	"github.com/quiby-ai/review-ingestor/internal/appstore"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/pager"
+
+	"github.com/quiby-ai/common/pkg/httpx"
+)
+
+const dateLayout = "2006-01-02T15:04:05Z"
+
+type TrustpilotReview struct {
+	ID           string        `json:"id"`
+	Stars        int           `json:"stars"`
+	Title        string        `json:"title"`
+	Text         string        `json:"text"`
+	CreatedAt    time.Time     `json:"createdAt"`
+	CompanyReply *CompanyReply `json:"companyReply,omitempty"`
+}
+
+type CompanyReply struct {
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type Link struct {
+	Rel  string `json:"rel"`
+	Href string `json:"href"`
+}
+
+type TrustpilotReviewsResponse struct {
+	Reviews []TrustpilotReview `json:"reviews"`
+	Links   []Link             `json:"links"`
+}
+
+type businessUnit struct {
+	ID string `json:"id"`
+}
+
+// TrustpilotFetcher ingests Trustpilot reviews through the same pipeline as
+// app store reviews. The request's app ID is the business domain (for
+// example "example.com"), and the country narrows reviews to those written by
+// consumers in that country.
+type TrustpilotFetcher struct {
+	http httpx.Client
+	cfg  config.TrustpilotConfig
+
+	mu    sync.Mutex
+	units map[string]string
+}
+
+func NewTrustpilotFetcher(http httpx.Client, cfg config.Config) *TrustpilotFetcher {
+	return &TrustpilotFetcher{http: http, cfg: cfg.Trustpilot, units: make(map[string]string)}
+}
+
+// businessUnitID resolves a business domain to its Trustpilot business unit.
+func (t *TrustpilotFetcher) businessUnitID(ctx context.Context, domain string) (string, error) {
+	t.mu.Lock()
+	id, ok := t.units[domain]
+	t.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	timer := logger.StartTimer()
+	params := url.Values{}
+	params.Set("name", domain)
+	requestURL := t.baseURL() + "/v1/business-units/find?" + params.Encode()
+
+	response, err := t.http.DoGET(ctx, requestURL, nil, t.headers())
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "trustpilot.business_unit.resolved", "failed", timer(), "error", "http_request_failed")
+		return "", fmt.Errorf("failed to resolve business unit: %w", err)
+	}
+	if response.Status == 404 {
+		logger.LogEventWithLatency(ctx, "trustpilot.business_unit.resolved", "failed", timer(), "status", 404)
+		return "", fmt.Errorf("business unit not found for %s", domain)
+	}
+	if response.Status != 200 {
+		logger.LogEventWithLatency(ctx, "trustpilot.business_unit.resolved", "failed", timer(), "status", response.Status)
+		return "", fmt.Errorf("unexpected status code: %d", response.Status)
+	}
+
+	var unit businessUnit
+	if err := json.Unmarshal(response.Body, &unit); err != nil || unit.ID == "" {
+		logger.LogEventWithLatency(ctx, "trustpilot.business_unit.resolved", "failed", timer(), "error", "json_parse_failed")
+		return "", fmt.Errorf("failed to parse business unit response for %s", domain)
+	}
+
+	t.mu.Lock()
+	t.units[domain] = unit.ID
+	t.mu.Unlock()
+
+	logger.LogEventWithLatency(ctx, "trustpilot.business_unit.resolved", "success", timer())
+	return unit.ID, nil
+}
+
+func (t *TrustpilotFetcher) FetchReviews(ctx context.Context, country, unitID string, page int) (*TrustpilotReviewsResponse, error) {
+	timer := logger.StartTimer()
+
+	params := url.Values{}
+	params.Set("page", strconv.Itoa(page))
+	params.Set("perPage", strconv.Itoa(t.cfg.Limit))
+	params.Set("orderBy", "createdat.desc")
+	if country != "" {
+		params.Set("countryCode", strings.ToUpper(country))
+	}
+	requestURL := fmt.Sprintf("%s/v1/business-units/%s/reviews?%s", t.baseURL(), url.PathEscape(unitID), params.Encode())
+
+	logger.Debug(ctx, "Fetching reviews from Trustpilot", "country", country, "page", page)
+
+	response, err := t.http.DoGET(ctx, requestURL, nil, t.headers())
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "trustpilot.reviews.request", "failed", timer(), "country", country, "error", "http_request_failed")
+		return nil, fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+
+	if response.Status != 200 {
+		logger.LogEventWithLatency(ctx, "trustpilot.reviews.request", "failed", timer(), "country", country, "status", response.Status)
+		return nil, fmt.Errorf("unexpected status code: %d", response.Status)
+	}
+
+	var reviewsResp TrustpilotReviewsResponse
+	if err := json.Unmarshal(response.Body, &reviewsResp); err != nil {
+		logger.LogEventWithLatency(ctx, "trustpilot.reviews.request", "failed", timer(), "country", country, "error", "json_parse_failed")
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	logger.LogEventWithLatency(ctx, "trustpilot.reviews.request", "success", timer(), "country", country, "reviews_count", len(reviewsResp.Reviews))
+	return &reviewsResp, nil
+}
+
+// StreamReviews pages through the business domain's reviews and hands them to
+// flush in batches of at least window reviews, converted to the App Store
+// review shape. Pages are 1-based; opts.Offset selects the first page when set.
+func (t *TrustpilotFetcher) StreamReviews(ctx context.Context, country string, domain string, opts *appstore.FetchOptions, window int, flush func(context.Context, []appstore.Review) error) (int, error) {
+	unitID, err := t.businessUnitID(ctx, domain)
+	if err != nil {
+		return 0, err
+	}
+
+	first := 1
+	if opts != nil && opts.Offset > 0 {
+		first = opts.Offset
+	}
+
+	fetch := func(ctx context.Context, cursor string) ([]appstore.Review, string, error) {
+		page, err := strconv.Atoi(cursor)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid page cursor %q: %w", cursor, err)
+		}
+		reviewsResp, err := t.FetchReviews(ctx, country, unitID, page)
+		if err != nil {
+			return nil, "", err
+		}
+		reviews := make([]appstore.Review, 0, len(reviewsResp.Reviews))
+		for _, review := range reviewsResp.Reviews {
+			reviews = append(reviews, trustpilotToAppStoreReview(review))
+		}
+		next := ""
+		if hasNextPage(reviewsResp.Links) {
+			next = strconv.Itoa(page + 1)
+		}
+		return reviews, next, nil
+	}
+
+	return pager.Stream(ctx, strconv.Itoa(first), opts, window, fetch, flush)
+}
+
+func hasNextPage(links []Link) bool {
+	for _, link := range links {
+		if link.Rel == "next-page" {
+			return true
+		}
+	}
+	return false
+}
+
+func trustpilotToAppStoreReview(review TrustpilotReview) appstore.Review {
+	converted := appstore.Review{
+		ID: review.ID,
+		Attributes: appstore.ReviewAttributes{
+			Date:   review.CreatedAt.UTC().Format(dateLayout),
+			Rating: review.Stars,
+			Review: review.Text,
+			Title:  review.Title,
+		},
+	}
+	if review.CompanyReply != nil {
+		converted.Attributes.DeveloperResponse = &appstore.DeveloperResponse{
+			Body:     review.CompanyReply.Text,
+			Modified: review.CompanyReply.CreatedAt.UTC().Format(dateLayout),
+		}
+	}
+	return converted
+}
+
+func (t *TrustpilotFetcher) baseURL() string {
+	return strings.TrimSuffix(t.cfg.APIHost, "/")
+}
+
+func (t *TrustpilotFetcher) headers() map[string]string {
+	return map[string]string{
+		"accept": "application/json",
+		"apikey": t.cfg.APIKey,
+	}
+}
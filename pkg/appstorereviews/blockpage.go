@@ -0,0 +1,88 @@
+package appstorereviews
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBlocked is returned when Apple answers with a bot-detection or CAPTCHA
+// interstitial instead of review JSON.
+var ErrBlocked = errors.New("blocked by bot detection")
+
+// userAgentBlockTTL is how long a user agent that triggered a block page is
+// left out of rotation.
+const userAgentBlockTTL = 30 * time.Minute
+
+// blockPageSignatures are lower-case fragments of known interstitials.
+var blockPageSignatures = [][]byte{
+	[]byte("captcha"),
+	[]byte("are you a robot"),
+	[]byte("unusual traffic"),
+	[]byte("verify you are human"),
+	[]byte("access denied"),
+	[]byte("request blocked"),
+	[]byte("cf-chl"),
+	[]byte("perimeterx"),
+	[]byte("_incapsula_"),
+}
+
+// isBlockPage reports whether body is an HTML interstitial served instead of
+// the JSON API response.
+func isBlockPage(body []byte) bool {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || trimmed[0] != '<' {
+		return false
+	}
+	lower := bytes.ToLower(trimmed)
+	for _, signature := range blockPageSignatures {
+		if bytes.Contains(lower, signature) {
+			return true
+		}
+	}
+	return false
+}
+
+// userAgentPool hands out user agents at random, skipping those that were
+// recently served a block page for as long as any other remains. agents
+// returns the current list, which may change between calls.
+type userAgentPool struct {
+	mu      sync.Mutex
+	agents  func() []string
+	blocked map[string]time.Time
+	intn    func(int) int
+}
+
+func newUserAgentPool(agents func() []string, intn func(int) int) *userAgentPool {
+	return &userAgentPool{agents: agents, blocked: make(map[string]time.Time), intn: intn}
+}
+
+func (p *userAgentPool) pick() string {
+	agents := p.agents()
+	if len(agents) == 0 {
+		return ""
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	available := make([]string, 0, len(agents))
+	for _, agent := range agents {
+		if until, ok := p.blocked[agent]; ok && now.Before(until) {
+			continue
+		}
+		available = append(available, agent)
+	}
+	if len(available) == 0 {
+		available = agents
+	}
+	return available[p.intn(len(available))]
+}
+
+func (p *userAgentPool) block(agent string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.blocked[agent] = time.Now().Add(userAgentBlockTTL)
+}
@@ -0,0 +1,43 @@
+package appstorereviews
+
+import "testing"
+
+func TestIsBlockPage(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{name: "json", body: `{"data":[]}`, want: false},
+		{name: "empty", body: "", want: false},
+		{name: "captcha", body: "<!DOCTYPE html><html><body><div id=\"captcha\"></div></body></html>", want: true},
+		{name: "robot check", body: "\n  <html><title>Are you a robot?</title></html>", want: true},
+		{name: "access denied", body: "<HTML><HEAD><TITLE>Access Denied</TITLE></HEAD></HTML>", want: true},
+		{name: "unrelated html", body: "<html><body>Service Unavailable</body></html>", want: false},
+		{name: "json mentioning captcha", body: `{"data":[{"attributes":{"review":"captcha everywhere"}}]}`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isBlockPage([]byte(tt.body)); got != tt.want {
+				t.Errorf("isBlockPage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserAgentPoolSkipsBlocked(t *testing.T) {
+	pool := newUserAgentPool(func() []string { return []string{"a", "b"} }, func(int) int { return 0 })
+
+	if got := pool.pick(); got != "a" {
+		t.Fatalf("pick() = %q, want a", got)
+	}
+	pool.block("a")
+	if got := pool.pick(); got != "b" {
+		t.Fatalf("pick() after blocking a = %q, want b", got)
+	}
+	pool.block("b")
+	if got := pool.pick(); got != "a" {
+		t.Fatalf("pick() with every agent blocked = %q, want a", got)
+	}
+}
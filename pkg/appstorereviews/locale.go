@@ -0,0 +1,35 @@
+package appstorereviews
+
+import "strings"
+
+// fallbackLocale is sent for storefronts without a preferred locale.
+const fallbackLocale = "en-GB"
+
+// storefrontLocales maps lower-case storefronts to the display locale the
+// App Store web client uses there. The locale decides which reviews and
+// response texts the API returns.
+var storefrontLocales = map[string]string{
+	"us": "en-US", "gb": "en-GB", "ca": "en-CA", "au": "en-AU", "nz": "en-NZ",
+	"ie": "en-GB", "in": "en-GB", "sg": "en-GB", "za": "en-GB",
+	"de": "de-DE", "at": "de-DE", "ch": "de-CH",
+	"fr": "fr-FR", "be": "fr-FR", "es": "es-ES", "mx": "es-MX", "ar": "es-MX",
+	"co": "es-MX", "cl": "es-MX", "it": "it-IT", "pt": "pt-PT", "br": "pt-BR",
+	"nl": "nl-NL", "se": "sv-SE", "no": "nb-NO", "dk": "da-DK", "fi": "fi-FI",
+	"pl": "pl-PL", "cz": "cs-CZ", "sk": "sk-SK", "hu": "hu-HU", "ro": "ro-RO",
+	"gr": "el-GR", "tr": "tr-TR", "ru": "ru-RU", "ua": "uk-UA", "il": "he-IL",
+	"sa": "ar-SA", "ae": "ar-SA", "eg": "ar-SA",
+	"jp": "ja-JP", "kr": "ko-KR", "cn": "zh-Hans-CN", "tw": "zh-Hant-TW",
+	"hk": "zh-Hant-HK", "th": "th-TH", "vn": "vi-VN", "id": "id-ID", "my": "ms-MY",
+}
+
+// localeFor returns the l parameter for country, preferring overrides.
+func localeFor(country string, overrides map[string]string) string {
+	key := strings.ToLower(country)
+	if locale, ok := overrides[key]; ok && locale != "" {
+		return locale
+	}
+	if locale, ok := storefrontLocales[key]; ok {
+		return locale
+	}
+	return fallbackLocale
+}
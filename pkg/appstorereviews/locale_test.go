@@ -0,0 +1,24 @@
+package appstorereviews
+
+import "testing"
+
+func TestLocaleFor(t *testing.T) {
+	overrides := map[string]string{"ch": "fr-FR", "de": ""}
+
+	tests := []struct {
+		country string
+		want    string
+	}{
+		{country: "jp", want: "ja-JP"},
+		{country: "JP", want: "ja-JP"},
+		{country: "ch", want: "fr-FR"},
+		{country: "de", want: "de-DE"},
+		{country: "xx", want: fallbackLocale},
+	}
+
+	for _, tt := range tests {
+		if got := localeFor(tt.country, overrides); got != tt.want {
+			t.Errorf("localeFor(%q) = %q, want %q", tt.country, got, tt.want)
+		}
+	}
+}
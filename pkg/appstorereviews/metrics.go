@@ -0,0 +1,52 @@
+package appstorereviews
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quiby-ai/common/pkg/httpx"
+	"github.com/quiby-ai/review-ingestor/internal/metrics"
+)
+
+var requestSeconds = metrics.NewHistogramVec("ingestor_appstore_request_seconds",
+	"Latency of App Store review page requests, by response status (error when none was received).", metrics.DefBuckets, "status")
+
+func observeRequest(latency time.Duration, response httpx.Response, err error) {
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(response.Status)
+	}
+	requestSeconds.Observe(latency.Seconds(), status)
+}
+
+// schemaDrift counts the structural differences detectDrift finds, by kind,
+// so a change of the page format shows up before it is noticed in the data.
+var schemaDrift = metrics.NewCounterVec("ingestor_appstore_schema_drift_total",
+	"Structural differences between App Store review pages and the expected schema, by kind.", "kind")
+
+func observeDrift(issues []string, samples []DriftSample) {
+	for _, issue := range issues {
+		schemaDrift.Inc(driftKind(issue))
+	}
+	for _, sample := range samples {
+		for _, issue := range sample.Issues {
+			schemaDrift.Inc(driftKind(issue))
+		}
+	}
+}
+
+// driftKind maps an issue to a label of bounded cardinality: issues name the
+// fields involved, which the label must not.
+func driftKind(issue string) string {
+	switch {
+	case strings.HasPrefix(issue, "unknown field "):
+		return "unknown_field"
+	case strings.HasPrefix(issue, "missing field "):
+		return "missing_field"
+	case strings.HasPrefix(issue, "attributes.date "):
+		return "date_format"
+	default:
+		return "malformed"
+	}
+}
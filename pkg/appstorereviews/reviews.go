@@ -0,0 +1,595 @@
+// Package appstorereviews fetches App Store reviews through the web client's
+// catalog API: a bearer token is extracted from an app's landing page and
+// review pages are requested with it, paging, backing off on rate limits and
+// bot-detection pages, and reporting schema drift. It is configured with
+// Options alone, so tools other than the ingestor can use it.
+package appstorereviews
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quiby-ai/review-ingestor/internal/budget"
+	"github.com/quiby-ai/review-ingestor/internal/logger"
+	"github.com/quiby-ai/review-ingestor/internal/normalize"
+
+	"github.com/quiby-ai/common/pkg/httpx"
+)
+
+type Review struct {
+	ID         string           `json:"id"`
+	Attributes ReviewAttributes `json:"attributes"`
+	// Raw is the JSON the review was decoded from, kept so fields can be
+	// re-derived later. It is empty for reviews converted from other stores.
+	Raw json.RawMessage `json:"-"`
+}
+
+func (r *Review) UnmarshalJSON(data []byte) error {
+	type plain Review
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*r = Review(decoded)
+	r.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+type ReviewAttributes struct {
+	Date              string             `json:"date"`
+	Rating            int                `json:"rating"`
+	Review            string             `json:"review"`
+	Title             string             `json:"title"`
+	DeveloperResponse *DeveloperResponse `json:"developerResponse,omitempty"`
+	// Territory is the storefront the review was written in. Reviews can be
+	// syndicated to other storefronts, so it may differ from the queried one.
+	Territory string `json:"territory,omitempty"`
+	// AppVersion is the app version the review was written for, when the
+	// store reports it.
+	AppVersion string `json:"appVersion,omitempty"`
+	// Language is the ISO 639-1 code of the review's language, for stores
+	// that report it. The App Store does not.
+	Language string `json:"language,omitempty"`
+	// IsEdited is set when the author edited the review after posting it.
+	// Modified is when, for stores that report it apart from Date; the App
+	// Store reports an edited review's Date as the time of the edit.
+	IsEdited bool   `json:"isEdited,omitempty"`
+	Modified string `json:"modified,omitempty"`
+}
+
+type DeveloperResponse struct {
+	Body     string `json:"body"`
+	Modified string `json:"modified"`
+}
+
+type ReviewsResponse struct {
+	Next string   `json:"next,omitempty"`
+	Data []Review `json:"data"`
+}
+
+type FetchOptions struct {
+	Limit  int
+	Offset int
+	After  *time.Time
+	// Before, when set, skips reviews written after it, as if paging
+	// started there.
+	Before   *time.Time
+	MaxLimit int
+	Sleep    *time.Duration
+	// Jitter adds a random delay in [0, Jitter) on top of Sleep between pages.
+	Jitter time.Duration
+	// OnPage, when set, is called after every page with the page offset and
+	// the number of reviews accepted so far.
+	OnPage func(offset, fetched int)
+	// Token, when set, authorizes the requests in place of the fetcher's
+	// own token, so that concurrent callers sharing a fetcher can each use
+	// the token extracted for their app.
+	Token string
+}
+
+// ResponseCache stores raw review page bodies keyed by request URL.
+type ResponseCache interface {
+	Get(ctx context.Context, url string) ([]byte, bool, error)
+	Set(ctx context.Context, url string, body []byte) error
+}
+
+// RateLimiter coordinates the request budget and 429 cooldowns, possibly
+// across several ingestor replicas.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+	Cooldown(ctx context.Context, d time.Duration) error
+}
+
+// DriftRecorder keeps samples of reviews whose structure no longer matches
+// the parser.
+type DriftRecorder interface {
+	RecordDrift(ctx context.Context, store, appID, country, reviewID string, issues []string, raw []byte) error
+}
+
+// FailureCapturer keeps failed App Store requests and their responses for
+// diagnosis. status is 0 when no response was received.
+type FailureCapturer interface {
+	CaptureFailure(ctx context.Context, requestURL string, headers map[string]string, status int, body []byte, reason string)
+}
+
+// Tuner adapts the delay between pages and bounds how many storefronts are
+// streamed at once, based on the latency and rate limiting it observes.
+type Tuner interface {
+	Observe(ctx context.Context, latency time.Duration, limited bool)
+	Acquire(ctx context.Context) error
+	Release()
+	Delay() time.Duration
+}
+
+// Defaults for Options fields left empty.
+const (
+	DefaultAPIPath  = "v1/catalog/{country}/apps/{app_id}/reviews"
+	DefaultReferrer = "https://apps.apple.com/"
+)
+
+// Options configures a Fetcher.
+type Options struct {
+	// APIHost is the scheme and host of the catalog API and APIPath the
+	// reviews path under it, with {country} and {app_id} placeholders.
+	// APIHost is required.
+	APIHost string
+	APIPath string
+	// Referrer is sent with every review request.
+	Referrer string
+	// Locales overrides the display locale sent for a storefront, keyed by
+	// lower-case storefront. The locale decides which reviews the API
+	// returns.
+	Locales map[string]string
+	// DateLayouts are the accepted review timestamp layouts, tried in
+	// order. Empty accepts RFC 3339 and its common variants.
+	DateLayouts []string
+	// UserAgents are rotated through, skipping those recently served a
+	// bot-detection page. Empty sends no User-Agent.
+	UserAgents []string
+}
+
+// Fetcher pages through the reviews of an app in a storefront. It is safe
+// for concurrent use once configured.
+type Fetcher struct {
+	http       httpx.Client
+	mu         sync.RWMutex
+	token      string
+	cache      ResponseCache
+	limiter    RateLimiter
+	drift      DriftRecorder
+	capturer   FailureCapturer
+	userAgents *userAgentPool
+	tuner      Tuner
+	sampled    map[string]bool
+	opts       Options
+}
+
+// New returns a fetcher sending its requests through http with token, which
+// may also be set later with SetToken or per call with FetchOptions.Token.
+func New(http httpx.Client, token string, opts Options) *Fetcher {
+	if opts.APIPath == "" {
+		opts.APIPath = DefaultAPIPath
+	}
+	if opts.Referrer == "" {
+		opts.Referrer = DefaultReferrer
+	}
+	agents := opts.UserAgents
+	return &Fetcher{http: http, token: token, userAgents: newUserAgentPool(func() []string { return agents }, rand.Intn), opts: opts}
+}
+
+// SetUserAgents makes the fetcher rotate through the user agents agents
+// returns, which may change while it runs.
+func (r *Fetcher) SetUserAgents(agents func() []string) {
+	r.userAgents = newUserAgentPool(agents, rand.Intn)
+}
+
+func (r *Fetcher) SetToken(token string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.token = token
+}
+
+func (r *Fetcher) currentToken() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.token
+}
+
+func (r *Fetcher) SetCache(cache ResponseCache) {
+	r.cache = cache
+}
+
+func (r *Fetcher) SetRateLimiter(limiter RateLimiter) {
+	r.limiter = limiter
+}
+
+// SetCapturer enables debug capture of failed review requests.
+func (r *Fetcher) SetCapturer(capturer FailureCapturer) {
+	r.capturer = capturer
+}
+
+// SetDriftRecorder stores a sample of every new kind of schema drift seen in
+// review pages.
+func (r *Fetcher) SetDriftRecorder(recorder DriftRecorder) {
+	r.drift = recorder
+	r.sampled = make(map[string]bool)
+}
+
+// SetTuner lets tuner adapt the page delay and bound the storefronts
+// streamed at once.
+func (r *Fetcher) SetTuner(tuner Tuner) {
+	r.tuner = tuner
+}
+
+func (r *Fetcher) FetchReviews(ctx context.Context, country, appID string, opts *FetchOptions) (*ReviewsResponse, error) {
+	if opts == nil {
+		opts = &FetchOptions{
+			Limit:  20,
+			Offset: 0,
+		}
+	}
+
+	timer := logger.StartTimer()
+	requestURL, headers := r.prepareQuery(country, appID, opts)
+
+	logger.Debug(ctx, "Fetching reviews from App Store", "country", country, "limit", opts.Limit, "offset", opts.Offset)
+
+	if body, ok := r.cachedPage(ctx, requestURL); ok {
+		var reviewsResp ReviewsResponse
+		if err := json.Unmarshal(body, &reviewsResp); err == nil {
+			logger.LogEventWithLatency(ctx, "appstore.reviews.request", "cached", timer(), "country", country, "reviews_count", len(reviewsResp.Data))
+			return &reviewsResp, nil
+		}
+	}
+
+	if r.limiter != nil {
+		if err := r.limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("rate limiter wait failed: %w", err)
+		}
+	}
+
+	requestTimer := logger.StartTimer()
+	response, err := r.http.DoGET(ctx, requestURL, nil, headers)
+	latency := requestTimer()
+	observeRequest(latency, response, err)
+	if r.tuner != nil {
+		r.tuner.Observe(ctx, latency, err == nil && (response.Status == 429 || isBlockPage(response.Body)))
+	}
+	if err != nil {
+		logger.LogEventWithLatency(ctx, "appstore.reviews.request", "failed", timer(), "country", country, "error", "http_request_failed")
+		r.captureFailure(ctx, requestURL, headers, httpx.Response{}, "http_request_failed")
+		return nil, fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+
+	if response.Status == 404 {
+		logger.LogEventWithLatency(ctx, "appstore.reviews.request", "failed", timer(), "country", country, "status", 404)
+		r.captureFailure(ctx, requestURL, headers, response, "not_found")
+		return nil, fmt.Errorf("app not found or not available in country %s", country)
+	}
+
+	if isBlockPage(response.Body) {
+		if response.Status != 429 {
+			budget.From(ctx).RateLimited()
+		}
+		logger.LogEventWithLatency(ctx, "appstore.reviews.request", "blocked", timer(), "country", country, "status", response.Status)
+		logger.LogEvent(ctx, "appstore.blocked", "detected", "country", country, "status", response.Status)
+		r.userAgents.block(headers["User-Agent"])
+		r.captureFailure(ctx, requestURL, headers, response, "blocked")
+		return nil, fmt.Errorf("%w: status %d in country %s", ErrBlocked, response.Status, country)
+	}
+
+	if response.Status != 200 {
+		logger.LogEventWithLatency(ctx, "appstore.reviews.request", "failed", timer(), "country", country, "status", response.Status)
+		r.captureFailure(ctx, requestURL, headers, response, "unexpected_status")
+		return nil, fmt.Errorf("unexpected status code: %d", response.Status)
+	}
+
+	var reviewsResp ReviewsResponse
+	if err := json.Unmarshal(response.Body, &reviewsResp); err != nil {
+		logger.LogEventWithLatency(ctx, "appstore.reviews.request", "failed", timer(), "country", country, "error", "json_parse_failed")
+		r.captureFailure(ctx, requestURL, headers, response, "json_parse_failed")
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	r.checkDrift(ctx, country, appID, response.Body)
+	r.cachePage(ctx, requestURL, response.Body)
+
+	logger.LogEventWithLatency(ctx, "appstore.reviews.request", "success", timer(), "country", country, "reviews_count", len(reviewsResp.Data))
+	return &reviewsResp, nil
+}
+
+// captureFailure records a failed request; response is zero when none was
+// received.
+func (r *Fetcher) captureFailure(ctx context.Context, requestURL string, headers map[string]string, response httpx.Response, reason string) {
+	if r.capturer == nil {
+		return
+	}
+	r.capturer.CaptureFailure(ctx, requestURL, headers, response.Status, response.Body, reason)
+}
+
+// checkDrift reports structural differences between a fetched page and the
+// expected schema, and records one sample per kind of drift.
+func (r *Fetcher) checkDrift(ctx context.Context, country, appID string, body []byte) {
+	issues, samples := detectDrift(body, r.opts.DateLayouts)
+	if len(issues) == 0 && len(samples) == 0 {
+		return
+	}
+	logger.LogEvent(ctx, "appstore.schema.drift", "detected", "country", country, "page_issues", issues, "reviews", len(samples))
+	observeDrift(issues, samples)
+
+	if r.drift == nil {
+		return
+	}
+	for _, sample := range samples {
+		signature := driftSignature(sample.Issues)
+		r.mu.Lock()
+		seen := r.sampled[signature]
+		r.sampled[signature] = true
+		r.mu.Unlock()
+		if seen {
+			continue
+		}
+		if err := r.drift.RecordDrift(ctx, "appstore", appID, country, sample.ReviewID, sample.Issues, sample.Raw); err != nil {
+			logger.Warn(ctx, "Failed to record schema drift sample", "error", err.Error())
+		}
+	}
+}
+
+func (r *Fetcher) cachedPage(ctx context.Context, requestURL string) ([]byte, bool) {
+	if r.cache == nil {
+		return nil, false
+	}
+	body, ok, err := r.cache.Get(ctx, requestURL)
+	if err != nil {
+		logger.Warn(ctx, "Failed to read response cache", "error", err.Error())
+		return nil, false
+	}
+	return body, ok
+}
+
+func (r *Fetcher) cachePage(ctx context.Context, requestURL string, body []byte) {
+	if r.cache == nil {
+		return
+	}
+	if err := r.cache.Set(ctx, requestURL, body); err != nil {
+		logger.Warn(ctx, "Failed to write response cache", "error", err.Error())
+	}
+}
+
+func (r *Fetcher) FetchAllReviews(ctx context.Context, country string, appID string, opts *FetchOptions) ([]Review, error) {
+	var allReviews []Review
+	_, err := r.StreamReviews(ctx, country, appID, opts, 0, func(_ context.Context, batch []Review) error {
+		allReviews = append(allReviews, batch...)
+		return nil
+	})
+	return allReviews, err
+}
+
+// StreamReviews pages through reviews like FetchAllReviews but hands them to
+// flush in batches of at least window reviews (every page when window is 0)
+// instead of accumulating them, so memory stays bounded for large apps. It
+// returns the number of reviews accepted. Reviews buffered when an error
+// occurs are not flushed.
+func (r *Fetcher) StreamReviews(ctx context.Context, country string, appID string, opts *FetchOptions, window int, flush func(context.Context, []Review) error) (int, error) {
+	if opts == nil {
+		opts = &FetchOptions{
+			Limit:  20,
+			Offset: 0,
+		}
+	}
+
+	if r.tuner != nil {
+		if err := r.tuner.Acquire(ctx); err != nil {
+			return 0, err
+		}
+		defer r.tuner.Release()
+	}
+
+	var buffered []Review
+	fetchedCount := 0
+	currentOffset := opts.Offset
+
+	backoffDelay := 1 * time.Second
+	maxBackoffDelay := 60 * time.Second
+	maxRetries := 5
+	currentRetries := 0
+
+	flushBuffered := func() error {
+		if len(buffered) == 0 {
+			return nil
+		}
+		if err := flush(ctx, buffered); err != nil {
+			return err
+		}
+		buffered = nil
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fetchedCount, ctx.Err()
+		default:
+		}
+
+		currentOpts := &FetchOptions{
+			Limit:    opts.Limit,
+			Offset:   currentOffset,
+			After:    opts.After,
+			Before:   opts.Before,
+			MaxLimit: opts.MaxLimit,
+			Sleep:    opts.Sleep,
+			Jitter:   opts.Jitter,
+			OnPage:   opts.OnPage,
+		}
+
+		reviewsResp, err := r.FetchReviews(ctx, country, appID, currentOpts)
+		if err != nil {
+			blocked := errors.Is(err, ErrBlocked)
+			if blocked || strings.Contains(strings.ToLower(err.Error()), "429") || strings.Contains(strings.ToLower(err.Error()), "too many") {
+				if currentRetries >= maxRetries {
+					logger.LogEvent(ctx, "appstore.retry.backoff", "failed", "attempt", currentRetries, "max_retries", maxRetries)
+					return fetchedCount, fmt.Errorf("maximum retry attempts exceeded: %w", err)
+				}
+
+				// Block pages are retried like rate limits; the next attempt
+				// goes out with a different user agent.
+				event := "appstore.rate_limited"
+				if blocked {
+					event = "appstore.blocked"
+				}
+				logger.LogEvent(ctx, event, "retrying", "attempt", currentRetries, "backoff_delay", backoffDelay.Seconds())
+				if r.limiter != nil {
+					if err := r.limiter.Cooldown(ctx, backoffDelay); err != nil {
+						logger.Warn(ctx, "Failed to share rate limit cooldown", "error", err.Error())
+					}
+				}
+				budget.From(ctx).Backoff(backoffDelay)
+				time.Sleep(backoffDelay)
+				backoffDelay = time.Duration(math.Min(float64(backoffDelay*2), float64(maxBackoffDelay)))
+				currentRetries++
+				continue
+			}
+			return fetchedCount, err
+		}
+
+		backoffDelay = 1 * time.Second
+		currentRetries = 0
+
+		newReviewsAdded := false
+		skippedNewer := false
+		limitReached := false
+		for _, review := range reviewsResp.Data {
+			// Reviews with an unparsable date are passed on rather than
+			// dropped, so they fail to save and end up in quarantine.
+			reviewDate, err := normalize.Timestamp(review.Attributes.Date, r.opts.DateLayouts)
+			if err == nil && opts.After != nil && reviewDate.Before(*opts.After) {
+				continue
+			}
+			if err == nil && opts.Before != nil && reviewDate.After(*opts.Before) {
+				skippedNewer = true
+				continue
+			}
+
+			buffered = append(buffered, review)
+			fetchedCount++
+			newReviewsAdded = true
+
+			if opts.MaxLimit > 0 && fetchedCount >= opts.MaxLimit {
+				limitReached = true
+				break
+			}
+		}
+
+		if opts.OnPage != nil {
+			opts.OnPage(currentOffset, fetchedCount)
+		}
+
+		if limitReached {
+			break
+		}
+
+		if len(buffered) >= window {
+			if err := flushBuffered(); err != nil {
+				return fetchedCount, err
+			}
+		}
+
+		if reviewsResp.Next == "" {
+			break
+		}
+
+		if opts.After != nil && !newReviewsAdded && !skippedNewer {
+			break
+		}
+
+		nextOffset, err := parseOffsetFromURL(reviewsResp.Next)
+		if err != nil {
+			break
+		}
+		currentOffset = nextOffset
+
+		delay := pageDelay(opts)
+		if r.tuner != nil {
+			delay = r.tuner.Delay() + pageDelay(&FetchOptions{Jitter: opts.Jitter})
+		}
+		if delay > 0 {
+			select {
+			case <-ctx.Done():
+				return fetchedCount, ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+	}
+
+	return fetchedCount, flushBuffered()
+}
+
+// pageDelay returns the politeness delay to wait before requesting the next page.
+func pageDelay(opts *FetchOptions) time.Duration {
+	var delay time.Duration
+	if opts.Sleep != nil {
+		delay = *opts.Sleep
+	}
+	if opts.Jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(opts.Jitter)))
+	}
+	return delay
+}
+
+func (r *Fetcher) prepareQuery(country, appID string, opts *FetchOptions) (string, map[string]string) {
+	host := strings.TrimSuffix(r.opts.APIHost, "/")
+	path := r.opts.APIPath
+	path = strings.ReplaceAll(path, "{country}", url.PathEscape(country))
+	path = strings.ReplaceAll(path, "{app_id}", url.PathEscape(appID))
+	path = strings.TrimPrefix(path, "/")
+	baseURL := fmt.Sprintf("%s/%s", host, path)
+
+	params := url.Values{}
+	params.Set("l", localeFor(country, r.opts.Locales))
+	params.Set("offset", strconv.Itoa(opts.Offset))
+	params.Set("sort", "recent")
+	params.Set("limit", strconv.Itoa(opts.Limit))
+	params.Set("platform", "web")
+	params.Set("additionalPlatforms", "appletv,ipad,iphone,mac")
+	params.Set("meta", "robots")
+
+	requestURL := baseURL + "?" + params.Encode()
+
+	headers := map[string]string{
+		"accept":             "*/*",
+		"accept-language":    "en-US,en;q=0.9",
+		"Authorization":      cmp.Or(opts.Token, r.currentToken()),
+		"origin":             "https://apps.apple.com",
+		"referer":            r.opts.Referrer,
+		"sec-ch-ua":          `"Not(A:Brand";v="99", "Google Chrome";v="133", "Chromium";v="133"`,
+		"sec-ch-ua-mobile":   "?1",
+		"sec-ch-ua-platform": `"Android"`,
+		"sec-fetch-dest":     "empty",
+		"sec-fetch-mode":     "cors",
+		"sec-fetch-site":     "same-site",
+		"User-Agent":         r.userAgents.pick(),
+	}
+
+	return requestURL, headers
+}
+
+func parseOffsetFromURL(urlStr string) (int, error) {
+	re := regexp.MustCompile(`offset=(\d+)`)
+	matches := re.FindStringSubmatch(urlStr)
+	if len(matches) < 2 {
+		return 0, fmt.Errorf("offset not found in URL")
+	}
+	return strconv.Atoi(matches[1])
+}
@@ -0,0 +1,116 @@
+package appstorereviews
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/quiby-ai/review-ingestor/internal/normalize"
+)
+
+var (
+	knownPageFields      = map[string]bool{"next": true, "data": true, "meta": true}
+	knownReviewFields    = map[string]bool{"id": true, "type": true, "href": true, "attributes": true}
+	knownAttributeFields = map[string]bool{
+		"date": true, "rating": true, "review": true, "title": true, "userName": true,
+		"isEdited": true, "developerResponse": true, "territory": true, "appVersion": true,
+	}
+	requiredAttributeFields = []string{"date", "rating", "review", "title"}
+)
+
+// DriftSample is a review whose structure differs from what the parser
+// expects, together with what differs.
+type DriftSample struct {
+	ReviewID string
+	Issues   []string
+	Raw      json.RawMessage
+}
+
+// detectDrift compares a raw review page with the structure ReviewsResponse
+// expects. It returns page-level issues and the reviews that have unknown
+// fields, missing required attributes or a date matching none of layouts.
+func detectDrift(body []byte, layouts []string) ([]string, []DriftSample) {
+	var page map[string]json.RawMessage
+	if err := json.Unmarshal(body, &page); err != nil {
+		return []string{"page is not a JSON object"}, nil
+	}
+
+	var issues []string
+	for _, field := range unknownFields(page, knownPageFields) {
+		issues = append(issues, "unknown field "+field)
+	}
+
+	data, ok := page["data"]
+	if !ok {
+		return append(issues, "missing field data"), nil
+	}
+	var reviews []json.RawMessage
+	if err := json.Unmarshal(data, &reviews); err != nil {
+		return append(issues, "data is not an array"), nil
+	}
+
+	var samples []DriftSample
+	for _, raw := range reviews {
+		if sample, ok := reviewDrift(raw, layouts); ok {
+			samples = append(samples, sample)
+		}
+	}
+	return issues, samples
+}
+
+func reviewDrift(raw json.RawMessage, layouts []string) (DriftSample, bool) {
+	sample := DriftSample{Raw: raw}
+
+	var review map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &review); err != nil {
+		sample.Issues = []string{"review is not a JSON object"}
+		return sample, true
+	}
+	json.Unmarshal(review["id"], &sample.ReviewID)
+
+	for _, field := range unknownFields(review, knownReviewFields) {
+		sample.Issues = append(sample.Issues, "unknown field "+field)
+	}
+
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(review["attributes"], &attributes); err != nil || attributes == nil {
+		sample.Issues = append(sample.Issues, "missing field attributes")
+		return sample, true
+	}
+	for _, field := range unknownFields(attributes, knownAttributeFields) {
+		sample.Issues = append(sample.Issues, "unknown field attributes."+field)
+	}
+	for _, field := range requiredAttributeFields {
+		if _, ok := attributes[field]; !ok {
+			sample.Issues = append(sample.Issues, "missing field attributes."+field)
+		}
+	}
+	if rawDate, ok := attributes["date"]; ok {
+		var date string
+		if err := json.Unmarshal(rawDate, &date); err != nil {
+			sample.Issues = append(sample.Issues, "attributes.date is not a string")
+		} else if _, err := normalize.Timestamp(date, layouts); err != nil {
+			sample.Issues = append(sample.Issues, "attributes.date has unexpected format")
+		}
+	}
+
+	return sample, len(sample.Issues) > 0
+}
+
+// unknownFields returns the keys of fields missing from known, sorted.
+func unknownFields(fields map[string]json.RawMessage, known map[string]bool) []string {
+	var unknown []string
+	for field := range fields {
+		if !known[field] {
+			unknown = append(unknown, field)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// driftSignature identifies a kind of drift so only one sample is recorded
+// per kind.
+func driftSignature(issues []string) string {
+	return strings.Join(issues, ";")
+}
@@ -0,0 +1,81 @@
+package appstorereviews
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectDrift(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		pageIssues []string
+		samples    map[string][]string
+	}{
+		{
+			name: "expected structure",
+			body: `{"next":"/v1/x?offset=20","data":[{"id":"1","type":"user-reviews","attributes":{"date":"2024-01-02T03:04:05Z","rating":5,"review":"great","title":"ok","userName":"a","isEdited":false}}]}`,
+		},
+		{
+			name:       "unknown page field",
+			body:       `{"data":[],"paging":{}}`,
+			pageIssues: []string{"unknown field paging"},
+		},
+		{
+			name:       "missing data",
+			body:       `{"results":[]}`,
+			pageIssues: []string{"unknown field results", "missing field data"},
+		},
+		{
+			name: "renamed attribute",
+			body: `{"data":[{"id":"2","attributes":{"date":"2024-01-02T03:04:05Z","stars":4,"review":"x","title":"y"}}]}`,
+			samples: map[string][]string{
+				"2": {"unknown field attributes.stars", "missing field attributes.rating"},
+			},
+		},
+		{
+			name: "missing attributes and bad date",
+			body: `{"data":[{"id":"3"},{"id":"4","attributes":{"date":"02/01/2024","rating":1,"review":"x","title":"y"}}]}`,
+			samples: map[string][]string{
+				"3": {"missing field attributes"},
+				"4": {"attributes.date has unexpected format"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, samples := detectDrift([]byte(tt.body), nil)
+			if !reflect.DeepEqual(issues, tt.pageIssues) {
+				t.Errorf("page issues = %v, want %v", issues, tt.pageIssues)
+			}
+			got := make(map[string][]string)
+			for _, s := range samples {
+				got[s.ReviewID] = s.Issues
+			}
+			if len(got) == 0 {
+				got = nil
+			}
+			if !reflect.DeepEqual(got, tt.samples) {
+				t.Errorf("samples = %v, want %v", got, tt.samples)
+			}
+		})
+	}
+}
+
+func TestDriftKind(t *testing.T) {
+	tests := map[string]string{
+		"unknown field paging":                  "unknown_field",
+		"unknown field attributes.stars":        "unknown_field",
+		"missing field attributes.rating":       "missing_field",
+		"attributes.date has unexpected format": "date_format",
+		"attributes.date is not a string":       "date_format",
+		"data is not an array":                  "malformed",
+		"review is not a JSON object":           "malformed",
+	}
+	for issue, want := range tests {
+		if got := driftKind(issue); got != want {
+			t.Errorf("driftKind(%q) = %q, want %q", issue, got, want)
+		}
+	}
+}
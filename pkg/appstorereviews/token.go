@@ -1,10 +1,11 @@
-package appstore
+package appstorereviews
 
 import (
 	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 
 	landingx "github.com/quiby-ai/common/pkg/appstore/landing"
 	tokenx "github.com/quiby-ai/common/pkg/appstore/token"
@@ -17,27 +18,60 @@ var (
 )
 
 type TokenExtractor struct {
-	http httpx.Client
+	http        httpx.Client
+	capturer    FailureCapturer
+	landingHost *url.URL
 }
 
 func NewTokenExtractor(http httpx.Client) *TokenExtractor {
 	return &TokenExtractor{http: http}
 }
 
+// SetCapturer enables debug capture of failed landing page requests.
+func (t *TokenExtractor) SetCapturer(capturer FailureCapturer) {
+	t.capturer = capturer
+}
+
+// SetLandingHost makes landing pages load from host (scheme://host[:port])
+// instead of apps.apple.com.
+func (t *TokenExtractor) SetLandingHost(host string) error {
+	parsed, err := url.Parse(host)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return fmt.Errorf("invalid landing host %q", host)
+	}
+	t.landingHost = parsed
+	return nil
+}
+
+func (t *TokenExtractor) landingURL(country, appName, appID string) string {
+	landing, _ := landingx.BuildLandingURL(country, appName, appID)
+	if t.landingHost == nil {
+		return landing
+	}
+	parsed, err := url.Parse(landing)
+	if err != nil {
+		return landing
+	}
+	parsed.Scheme, parsed.Host = t.landingHost.Scheme, t.landingHost.Host
+	return parsed.String()
+}
+
 func (t *TokenExtractor) ExtractToken(ctx context.Context, country, appName, appID string) (string, error) {
 	timer := logger.StartTimer()
 
 	logger.Debug(ctx, "Extracting token from App Store", "country", country, "app_name", appName)
 
-	url, _ := landingx.BuildLandingURL(country, appName, appID)
+	url := t.landingURL(country, appName, appID)
 	response, err := t.http.DoGET(ctx, url, nil, nil)
 	if err != nil {
 		logger.LogEventWithLatency(ctx, "appstore.token.extracted", "failed", timer(), "country", country, "error", "http_request_failed")
+		t.captureFailure(ctx, url, httpx.Response{}, "token_request_failed")
 		return "", fmt.Errorf("extract token failed: %w", err)
 	}
 
 	if response.Status != http.StatusOK {
 		logger.LogEventWithLatency(ctx, "appstore.token.extracted", "failed", timer(), "country", country, "status", response.Status)
+		t.captureFailure(ctx, url, response, "token_unexpected_status")
 		return "", fmt.Errorf("unexpected status: %d", response.Status)
 	}
 
@@ -48,5 +82,13 @@ func (t *TokenExtractor) ExtractToken(ctx context.Context, country, appName, app
 	}
 
 	logger.LogEventWithLatency(ctx, "appstore.token.extracted", "failed", timer(), "country", country, "error", "token_not_found")
+	t.captureFailure(ctx, url, response, "token_not_found")
 	return "", ErrTokenNotFound
 }
+
+func (t *TokenExtractor) captureFailure(ctx context.Context, url string, response httpx.Response, reason string) {
+	if t.capturer == nil {
+		return
+	}
+	t.capturer.CaptureFailure(ctx, url, nil, response.Status, response.Body, reason)
+}